@@ -0,0 +1,175 @@
+package identity
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// Encoder/decoder BER minimal untuk pesan LDAPv3 (RFC 4511).
+// Hanya mendukung yang dipakai LDAPProvider: tag < 31 dan panjang definite.
+
+const (
+	berClassUniversal   = 0x00
+	berClassApplication = 0x40
+	berClassContext     = 0x80
+	berConstructed      = 0x20
+
+	berTagBoolean     = 0x01
+	berTagInteger     = 0x02
+	berTagOctetString = 0x04
+	berTagEnumerated  = 0x0a
+	berTagSequence    = 0x10
+	berTagSet         = 0x11
+)
+
+// berMaxLength - Batas ukuran satu pesan dari server LDAP
+const berMaxLength = 1 << 20
+
+type berPacket struct {
+	class       byte
+	constructed bool
+	tag         byte
+	value       []byte
+	children    []*berPacket
+}
+
+func berEncode(identifier byte, content []byte) []byte {
+	out := []byte{identifier}
+	out = append(out, berLength(len(content))...)
+	return append(out, content...)
+}
+
+func berLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+
+	var raw []byte
+	for v := n; v > 0; v >>= 8 {
+		raw = append([]byte{byte(v)}, raw...)
+	}
+	return append([]byte{0x80 | byte(len(raw))}, raw...)
+}
+
+func berInteger(tag byte, v int64) []byte {
+	var raw []byte
+	for {
+		raw = append([]byte{byte(v)}, raw...)
+		if (v >= -128 && v < 128) || v == 0 {
+			break
+		}
+		v >>= 8
+	}
+	return berEncode(tag, raw)
+}
+
+func berOctetString(s string) []byte {
+	return berEncode(berTagOctetString, []byte(s))
+}
+
+func berBoolean(b bool) []byte {
+	if b {
+		return berEncode(berTagBoolean, []byte{0xff})
+	}
+	return berEncode(berTagBoolean, []byte{0x00})
+}
+
+func berSequence(identifier byte, children ...[]byte) []byte {
+	var content []byte
+	for _, child := range children {
+		content = append(content, child...)
+	}
+	return berEncode(identifier, content)
+}
+
+// berRead - Baca satu elemen BER utuh dari stream
+func berRead(r *bufio.Reader) (*berPacket, error) {
+	identifier, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if identifier&0x1f == 0x1f {
+		return nil, errors.New("ber: multi-byte tags are not supported")
+	}
+
+	first, err := r.ReadByte()
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	length := int(first)
+	if first&0x80 != 0 {
+		count := int(first & 0x7f)
+		if count == 0 || count > 4 {
+			return nil, errors.New("ber: unsupported length encoding")
+		}
+		length = 0
+		for i := 0; i < count; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > berMaxLength {
+		return nil, errors.New("ber: message too large")
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	return berParse(identifier, content)
+}
+
+func berParse(identifier byte, content []byte) (*berPacket, error) {
+	packet := &berPacket{
+		class:       identifier & 0xc0,
+		constructed: identifier&berConstructed != 0,
+		tag:         identifier & 0x1f,
+		value:       content,
+	}
+
+	if packet.constructed {
+		r := bufio.NewReader(bytes.NewReader(content))
+		for {
+			child, err := berRead(r)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			packet.children = append(packet.children, child)
+		}
+	}
+
+	return packet, nil
+}
+
+func (p *berPacket) int() int64 {
+	var v int64
+	for i, b := range p.value {
+		if i == 0 && b&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int64(b)
+	}
+	return v
+}
+
+func (p *berPacket) string() string {
+	return string(p.value)
+}
+
+// unexpectedEOF - EOF di tengah elemen berarti data terpotong, bukan akhir stream
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,321 @@
+package identity
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"UASBE/app/model"
+)
+
+// LDAPConfig - Direktori LDAP/Active Directory (search lalu bind sebagai user)
+type LDAPConfig struct {
+	URL          string // ldap://host:389 atau ldaps://host:636
+	BindDN       string // akun service untuk mencari user (kosong = anonymous)
+	BindPassword string
+	BaseDN       string
+	Timeout      time.Duration
+
+	// Nama atribut di entry user
+	UserAttribute       string // atribut username, mis. uid atau sAMAccountName
+	EmailAttribute      string
+	NameAttribute       string
+	GroupAttribute      string // mis. memberOf
+	StudentIDAttribute  string // NIM
+	LecturerIDAttribute string // NIP
+}
+
+// LDAPProvider - Login dengan username/password direktori lewat POST /auth/login
+type LDAPProvider struct {
+	cfg LDAPConfig
+}
+
+// Operasi dan result code LDAPv3 (RFC 4511) yang dipakai
+const (
+	ldapOpBindRequest      = 0
+	ldapOpBindResponse     = 1
+	ldapOpUnbindRequest    = 2
+	ldapOpSearchRequest    = 3
+	ldapOpSearchResultItem = 4
+	ldapOpSearchResultDone = 5
+	ldapOpSearchResultRef  = 19
+
+	ldapResultSuccess            = 0
+	ldapResultInvalidCredentials = 49
+
+	ldapScopeSubtree    = 2
+	ldapFilterEquality  = 3
+	ldapDerefNever      = 0
+	ldapSearchSizeLimit = 2 // cukup untuk mendeteksi username ganda
+)
+
+func NewLDAPProvider(cfg LDAPConfig) *LDAPProvider {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.UserAttribute == "" {
+		cfg.UserAttribute = "uid"
+	}
+	if cfg.EmailAttribute == "" {
+		cfg.EmailAttribute = "mail"
+	}
+	if cfg.NameAttribute == "" {
+		cfg.NameAttribute = "cn"
+	}
+	return &LDAPProvider{cfg: cfg}
+}
+
+func (p *LDAPProvider) Name() string {
+	return model.IdentityProviderLDAP
+}
+
+// Authenticate - Cari entry user dengan akun service, lalu bind dengan DN user
+// dan password yang diberikan. Password kosong selalu ditolak (RFC 4513
+// "unauthenticated bind" akan dianggap sukses oleh server).
+func (p *LDAPProvider) Authenticate(ctx context.Context, username string, password string) (*model.ExternalIdentity, error) {
+	if strings.TrimSpace(username) == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := p.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	if err := conn.bind(p.cfg.BindDN, p.cfg.BindPassword); err != nil {
+		return nil, fmt.Errorf("ldap: service bind failed: %w", err)
+	}
+
+	attributes := []string{p.cfg.EmailAttribute, p.cfg.NameAttribute}
+	for _, attr := range []string{p.cfg.GroupAttribute, p.cfg.StudentIDAttribute, p.cfg.LecturerIDAttribute} {
+		if attr != "" {
+			attributes = append(attributes, attr)
+		}
+	}
+
+	entries, err := conn.search(p.cfg.BaseDN, p.cfg.UserAttribute, username, attributes)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrUserNotFound
+	}
+	if len(entries) > 1 {
+		return nil, fmt.Errorf("ldap: username %q matches more than one entry", username)
+	}
+	entry := entries[0]
+
+	if err := conn.bind(entry.dn, password); err != nil {
+		return nil, err
+	}
+
+	return &model.ExternalIdentity{
+		Provider:   model.IdentityProviderLDAP,
+		Subject:    entry.dn,
+		Username:   username,
+		Email:      entry.first(p.cfg.EmailAttribute),
+		FullName:   entry.first(p.cfg.NameAttribute),
+		Groups:     entry.attrs[strings.ToLower(p.cfg.GroupAttribute)],
+		StudentID:  entry.first(p.cfg.StudentIDAttribute),
+		LecturerID: entry.first(p.cfg.LecturerIDAttribute),
+	}, nil
+}
+
+//
+// ==================== CONNECTION ======================
+//
+
+type ldapConn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	messageID int64
+}
+
+type ldapEntry struct {
+	dn    string
+	attrs map[string][]string // nama atribut huruf kecil
+}
+
+func (e ldapEntry) first(attr string) string {
+	values := e.attrs[strings.ToLower(attr)]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (p *LDAPProvider) dial(ctx context.Context) (*ldapConn, error) {
+	u, err := url.Parse(p.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: invalid url: %w", err)
+	}
+
+	host := u.Host
+	dialer := &net.Dialer{Timeout: p.cfg.Timeout}
+
+	var conn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	case "ldaps":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "636")
+		}
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: u.Hostname()}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", host)
+	default:
+		return nil, fmt.Errorf("ldap: unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ldap: connect failed: %w", err)
+	}
+
+	deadline := time.Now().Add(p.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	return &ldapConn{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+func (c *ldapConn) close() {
+	c.send(berEncode(berClassApplication|ldapOpUnbindRequest, nil))
+	c.conn.Close()
+}
+
+func (c *ldapConn) send(op []byte) error {
+	c.messageID++
+	message := berSequence(berClassUniversal|berConstructed|berTagSequence,
+		berInteger(berTagInteger, c.messageID),
+		op,
+	)
+	_, err := c.conn.Write(message)
+	return err
+}
+
+// receive - Baca satu LDAPMessage untuk request terakhir, return protocolOp-nya
+func (c *ldapConn) receive() (*berPacket, error) {
+	packet, err := berRead(c.reader)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: read failed: %w", err)
+	}
+	if len(packet.children) < 2 || packet.children[0].int() != c.messageID {
+		return nil, errors.New("ldap: malformed response")
+	}
+
+	op := packet.children[1]
+	if op.class != berClassApplication {
+		return nil, errors.New("ldap: malformed response")
+	}
+	return op, nil
+}
+
+// bind - Simple bind; ErrInvalidCredentials jika server menolak password
+func (c *ldapConn) bind(dn string, password string) error {
+	request := berSequence(berClassApplication|berConstructed|ldapOpBindRequest,
+		berInteger(berTagInteger, 3),
+		berOctetString(dn),
+		berEncode(berClassContext|0, []byte(password)),
+	)
+	if err := c.send(request); err != nil {
+		return err
+	}
+
+	op, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if op.tag != ldapOpBindResponse || len(op.children) < 1 {
+		return errors.New("ldap: unexpected bind response")
+	}
+
+	switch code := op.children[0].int(); code {
+	case ldapResultSuccess:
+		return nil
+	case ldapResultInvalidCredentials:
+		return ErrInvalidCredentials
+	default:
+		return fmt.Errorf("ldap: bind failed with result code %d", code)
+	}
+}
+
+// search - Cari entry dengan filter (attr=value) di bawah baseDN
+func (c *ldapConn) search(baseDN string, attr string, value string, attributes []string) ([]ldapEntry, error) {
+	var attrList [][]byte
+	for _, a := range attributes {
+		attrList = append(attrList, berOctetString(a))
+	}
+
+	request := berSequence(berClassApplication|berConstructed|ldapOpSearchRequest,
+		berOctetString(baseDN),
+		berInteger(berTagEnumerated, ldapScopeSubtree),
+		berInteger(berTagEnumerated, ldapDerefNever),
+		berInteger(berTagInteger, ldapSearchSizeLimit),
+		berInteger(berTagInteger, 0),
+		berBoolean(false),
+		berSequence(berClassContext|berConstructed|ldapFilterEquality,
+			berOctetString(attr),
+			berOctetString(value),
+		),
+		berSequence(berClassUniversal|berConstructed|berTagSequence, attrList...),
+	)
+	if err := c.send(request); err != nil {
+		return nil, err
+	}
+
+	var entries []ldapEntry
+	for {
+		op, err := c.receive()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.tag {
+		case ldapOpSearchResultItem:
+			entries = append(entries, parseLDAPEntry(op))
+		case ldapOpSearchResultRef:
+			// referral ke server lain tidak diikuti
+		case ldapOpSearchResultDone:
+			if len(op.children) < 1 {
+				return nil, errors.New("ldap: malformed search result")
+			}
+			// sizeLimitExceeded (4) tetap membawa entry yang sudah terkirim
+			if code := op.children[0].int(); code != ldapResultSuccess && code != 4 {
+				return nil, fmt.Errorf("ldap: search failed with result code %d", code)
+			}
+			return entries, nil
+		default:
+			return nil, errors.New("ldap: unexpected search response")
+		}
+	}
+}
+
+func parseLDAPEntry(op *berPacket) ldapEntry {
+	entry := ldapEntry{attrs: map[string][]string{}}
+	if len(op.children) < 2 {
+		return entry
+	}
+
+	entry.dn = op.children[0].string()
+	for _, attr := range op.children[1].children {
+		if len(attr.children) < 2 {
+			continue
+		}
+		name := strings.ToLower(attr.children[0].string())
+		for _, value := range attr.children[1].children {
+			entry.attrs[name] = append(entry.attrs[name], value.string())
+		}
+	}
+	return entry
+}
@@ -0,0 +1,365 @@
+package identity
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"UASBE/app/model"
+)
+
+// OIDCConfig - Client OIDC (authorization code flow, client_secret_basic)
+type OIDCConfig struct {
+	Issuer       string // URL issuer; discovery dibaca dari {Issuer}/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // harus sama dengan yang didaftarkan di IdP (callback /auth/oidc/callback)
+	Scopes       []string
+
+	// Nama claim di ID token
+	GroupsClaim     string
+	StudentIDClaim  string // NIM
+	LecturerIDClaim string // NIP
+}
+
+// OIDCProvider - Login lewat halaman IdP kampus (Keycloak, Azure AD, Google Workspace, dsb.)
+type OIDCProvider struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]crypto.PublicKey
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewOIDCProvider - client nil = http.Client dengan timeout 10 detik
+func NewOIDCProvider(cfg OIDCConfig, client *http.Client) *OIDCProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+
+	return &OIDCProvider{cfg: cfg, client: client}
+}
+
+func (p *OIDCProvider) Name() string {
+	return model.IdentityProviderOIDC
+}
+
+// AuthCodeURL - URL halaman login IdP
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state string, nonce string) (string, error) {
+	disc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.cfg.ClientID)
+	params.Set("redirect_uri", p.cfg.RedirectURL)
+	params.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+
+	separator := "?"
+	if strings.Contains(disc.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return disc.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange - Tukar authorization code dengan ID token lalu verifikasi
+// signature, issuer, audience, masa berlaku dan nonce
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, nonce string) (*model.ExternalIdentity, error) {
+	disc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, disc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tokenRes struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenRes); err != nil {
+		return nil, fmt.Errorf("oidc: invalid token response: %w", err)
+	}
+
+	// code salah/kadaluarsa = kredensial ditolak, bukan IdP bermasalah
+	if tokenRes.Error == "invalid_grant" {
+		return nil, ErrInvalidCredentials
+	}
+	if resp.StatusCode != http.StatusOK || tokenRes.IDToken == "" {
+		return nil, fmt.Errorf("oidc: token endpoint returned %d %s", resp.StatusCode, tokenRes.Error)
+	}
+
+	claims, err := p.verifyIDToken(ctx, tokenRes.IDToken, disc.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidToken)
+	}
+
+	return p.identityFromClaims(claims)
+}
+
+// verifyIDToken - Verifikasi ID token dengan JWKS IdP.
+// kid yang belum dikenal memicu satu kali reload JWKS (rotasi key di IdP).
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, rawToken string, issuer string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := jwt.ParseWithClaims(rawToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+func (p *OIDCProvider) identityFromClaims(claims jwt.MapClaims) (*model.ExternalIdentity, error) {
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+
+	ext := &model.ExternalIdentity{
+		Provider:   model.IdentityProviderOIDC,
+		Subject:    subject,
+		Username:   stringClaim(claims, "preferred_username"),
+		Email:      stringClaim(claims, "email"),
+		FullName:   stringClaim(claims, "name"),
+		Groups:     stringsClaim(claims, p.cfg.GroupsClaim),
+		StudentID:  stringClaim(claims, p.cfg.StudentIDClaim),
+		LecturerID: stringClaim(claims, p.cfg.LecturerIDClaim),
+	}
+	return ext, nil
+}
+
+// discover - Baca dokumen discovery sekali lalu simpan
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	disc := &oidcDiscovery{}
+	if err := p.getJSON(ctx, p.cfg.Issuer+"/.well-known/openid-configuration", disc); err != nil {
+		return nil, fmt.Errorf("oidc: discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(disc.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("oidc: discovery issuer %q does not match %q", disc.Issuer, p.cfg.Issuer)
+	}
+	if disc.AuthorizationEndpoint == "" || disc.TokenEndpoint == "" || disc.JWKSURI == "" {
+		return nil, errors.New("oidc: discovery document is incomplete")
+	}
+
+	p.discovery = disc
+	return disc, nil
+}
+
+func (p *OIDCProvider) publicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	if err := p.loadKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key, ok = p.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+func (p *OIDCProvider) loadKeys(ctx context.Context) error {
+	disc, err := p.discover(ctx)
+	if err != nil {
+		return err
+	}
+
+	var set model.JWKSet
+	if err := p.getJSON(ctx, disc.JWKSURI, &set); err != nil {
+		return fmt.Errorf("oidc: failed to load jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseJWK(jwk)
+		if err != nil {
+			continue // key dengan tipe yang tidak didukung dilewati
+		}
+		keys[jwk.Kid] = key
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// parseJWK - Public key dari JWK (RSA, EC P-256/P-384, Ed25519)
+func parseJWK(jwk model.JWK) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	if name == "" {
+		return ""
+	}
+	switch value := claims[name].(type) {
+	case string:
+		return value
+	case float64:
+		// NIM/NIP kadang dikirim sebagai angka
+		return fmt.Sprintf("%.0f", value)
+	}
+	return ""
+}
+
+// stringsClaim - Claim berupa array string, atau satu string dipisah koma/spasi
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	if name == "" {
+		return nil
+	}
+
+	var values []string
+	switch value := claims[name].(type) {
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+	case string:
+		values = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	return values
+}
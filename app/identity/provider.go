@@ -0,0 +1,39 @@
+package identity
+
+import (
+	"context"
+	"errors"
+
+	"UASBE/app/model"
+)
+
+// ErrInvalidCredentials - Username/password ditolak oleh direktori
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrInvalidToken - Respons IdP tidak lolos verifikasi (signature, audience, nonce, dsb.)
+var ErrInvalidToken = errors.New("invalid identity token")
+
+// ErrUserNotFound - Username tidak ada di direktori (login lokal boleh dicoba)
+var ErrUserNotFound = errors.New("user not found in directory")
+
+// Provider - Sumber identitas eksternal (direktori kampus).
+// Setiap provider mengimplementasikan salah satu dari PasswordProvider
+// atau RedirectProvider sesuai cara user membuktikan identitasnya.
+type Provider interface {
+	Name() string
+}
+
+// PasswordProvider - Provider yang memverifikasi username + password secara
+// langsung (LDAP bind). Dipakai oleh POST /auth/login.
+type PasswordProvider interface {
+	Provider
+	Authenticate(ctx context.Context, username string, password string) (*model.ExternalIdentity, error)
+}
+
+// RedirectProvider - Provider dengan login di halaman IdP (OIDC authorization code).
+// AuthCodeURL → user login di IdP → callback membawa code → Exchange.
+type RedirectProvider interface {
+	Provider
+	AuthCodeURL(ctx context.Context, state string, nonce string) (string, error)
+	Exchange(ctx context.Context, code string, nonce string) (*model.ExternalIdentity, error)
+}
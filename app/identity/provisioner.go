@@ -0,0 +1,246 @@
+package identity
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"UASBE/app/model"
+	"UASBE/app/repository"
+)
+
+// ErrNoRoleMapped - Tidak ada grup direktori yang dipetakan ke role dan tidak ada role default
+var ErrNoRoleMapped = errors.New("no role mapped for directory groups")
+
+// ErrAccountConflict - Username/email dari direktori sudah dipakai user lokal yang tidak terhubung
+var ErrAccountConflict = errors.New("username or email already used by another account")
+
+// GroupRole - Pemetaan satu grup direktori ke nama role lokal
+type GroupRole struct {
+	Group string // nama grup atau DN lengkap (case-insensitive)
+	Role  string // nama role di tabel roles
+}
+
+// ParseGroupRoles - Format "grup=Role;grup lain=Role Lain",
+// mis. "mahasiswa=Mahasiswa;cn=dosen,ou=groups,dc=kampus,dc=ac,dc=id=Dosen Wali".
+// Nama role diambil dari "=" terakhir karena DN grup juga memakai "=".
+func ParseGroupRoles(raw string) []GroupRole {
+	var mappings []GroupRole
+	for _, item := range strings.Split(raw, ";") {
+		idx := strings.LastIndex(item, "=")
+		if idx <= 0 {
+			continue
+		}
+		group := strings.TrimSpace(item[:idx])
+		role := strings.TrimSpace(item[idx+1:])
+		if group != "" && role != "" {
+			mappings = append(mappings, GroupRole{Group: group, Role: role})
+		}
+	}
+	return mappings
+}
+
+// Provisioner - Ubah identitas eksternal menjadi user lokal:
+//  1. subject sudah terhubung → user yang terhubung
+//  2. NIM/NIP cocok dengan students/lecturers → link ke user tersebut
+//  3. selain itu → buat user baru (just-in-time) dengan role hasil pemetaan grup
+//
+// Role user yang sudah ada disinkronkan setiap login jika grupnya terpetakan.
+type Provisioner struct {
+	userRepo     repository.UserRepository
+	roleRepo     repository.RoleRepository
+	studentRepo  repository.StudentRepository
+	lecturerRepo repository.LecturerRepository
+	identityRepo repository.IdentityRepository
+	revokeRepo   repository.TokenRevocationRepository
+
+	groupRoles  []GroupRole
+	defaultRole string
+}
+
+func NewProvisioner(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	studentRepo repository.StudentRepository,
+	lecturerRepo repository.LecturerRepository,
+	identityRepo repository.IdentityRepository,
+	revokeRepo repository.TokenRevocationRepository,
+	groupRoles []GroupRole,
+	defaultRole string,
+) *Provisioner {
+	return &Provisioner{
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		studentRepo:  studentRepo,
+		lecturerRepo: lecturerRepo,
+		identityRepo: identityRepo,
+		revokeRepo:   revokeRepo,
+		groupRoles:   groupRoles,
+		defaultRole:  defaultRole,
+	}
+}
+
+// Resolve - User lokal untuk identitas eksternal (dibuat jika belum ada).
+// Status akun tidak dicek di sini; pemanggil tetap harus menolak akun nonaktif.
+func (p *Provisioner) Resolve(ext *model.ExternalIdentity) (*model.User, error) {
+	link, err := p.identityRepo.FindByProviderSubject(ext.Provider, ext.Subject)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil {
+		user, err := p.userRepo.FindByID(link.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.syncRole(user, ext); err != nil {
+			return nil, err
+		}
+		p.identityRepo.TouchLastLogin(link.ID)
+		return user, nil
+	}
+
+	user, err := p.findByAcademicID(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	if user != nil {
+		if err := p.syncRole(user, ext); err != nil {
+			return nil, err
+		}
+	} else {
+		user, err = p.createUser(ext)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	link = &model.UserIdentity{UserID: user.ID, Provider: ext.Provider, Subject: ext.Subject}
+	if err := p.identityRepo.Create(link); err != nil {
+		return nil, err
+	}
+	p.identityRepo.TouchLastLogin(link.ID)
+
+	return user, nil
+}
+
+// findByAcademicID - User lokal dengan NIM (students) atau NIP (lecturers) yang sama.
+// Return nil tanpa error jika tidak ada yang cocok.
+func (p *Provisioner) findByAcademicID(ext *model.ExternalIdentity) (*model.User, error) {
+	userID := ""
+
+	if ext.StudentID != "" {
+		if student, err := p.studentRepo.FindByStudentID(ext.StudentID); err == nil {
+			userID = student.ID
+		}
+	}
+	if userID == "" && ext.LecturerID != "" {
+		if lecturer, err := p.lecturerRepo.FindByLecturerID(ext.LecturerID); err == nil {
+			userID = lecturer.ID
+		}
+	}
+	if userID == "" {
+		return nil, nil
+	}
+
+	return p.userRepo.FindByID(userID)
+}
+
+// createUser - Provisioning just-in-time. Password lokal dikosongkan sehingga
+// user hanya bisa login lewat direktori. Username/email yang bentrok dengan
+// user lokal lain tidak otomatis di-link (bisa saja orang yang berbeda).
+func (p *Provisioner) createUser(ext *model.ExternalIdentity) (*model.User, error) {
+	role, err := p.mapRole(ext.Groups)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		if p.defaultRole == "" {
+			return nil, ErrNoRoleMapped
+		}
+		if role, err = p.roleRepo.GetRoleByName(p.defaultRole); err != nil {
+			return nil, err
+		}
+	}
+
+	username := ext.Username
+	if username == "" {
+		username = ext.Subject
+	}
+	if ext.Email == "" {
+		return nil, errors.New("directory account has no email address")
+	}
+
+	if _, err := p.userRepo.FindByUsername(username); err == nil {
+		return nil, ErrAccountConflict
+	}
+	if _, err := p.userRepo.FindByEmail(ext.Email); err == nil {
+		return nil, ErrAccountConflict
+	}
+
+	fullName := ext.FullName
+	if fullName == "" {
+		fullName = username
+	}
+
+	user := &model.User{
+		Username: username,
+		Email:    ext.Email,
+		FullName: fullName,
+		RoleID:   role.ID,
+		Role:     role.Name,
+		IsActive: true,
+	}
+	if err := p.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// syncRole - Samakan role user dengan pemetaan grup. Tanpa grup terpetakan role tidak diubah.
+func (p *Provisioner) syncRole(user *model.User, ext *model.ExternalIdentity) error {
+	role, err := p.mapRole(ext.Groups)
+	if err != nil || role == nil || role.ID == user.RoleID {
+		return err
+	}
+
+	if err := p.userRepo.UpdateRole(user.ID, role.ID); err != nil {
+		return err
+	}
+
+	// sama seperti AssignRole: token lama membawa role lama. Dibulatkan ke detik
+	// (presisi iat) supaya token dari login yang sedang berjalan tetap berlaku.
+	if err := p.revokeRepo.SetTokensValidAfter(user.ID, time.Now().Truncate(time.Second)); err != nil {
+		return err
+	}
+	user.RoleID = role.ID
+	user.Role = role.Name
+	return nil
+}
+
+// mapRole - Role dari pemetaan pertama yang cocok (urutan config = prioritas)
+func (p *Provisioner) mapRole(groups []string) (*model.Role, error) {
+	for _, mapping := range p.groupRoles {
+		for _, group := range groups {
+			if groupMatches(group, mapping.Group) {
+				return p.roleRepo.GetRoleByName(mapping.Role)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// groupMatches - Cocok dengan nama grup, DN lengkap, atau nilai RDN pertama
+// dari DN (cn=dosen,ou=groups,... cocok dengan "dosen")
+func groupMatches(group string, want string) bool {
+	if strings.EqualFold(group, want) {
+		return true
+	}
+
+	rdn := strings.SplitN(group, ",", 2)[0]
+	if idx := strings.Index(rdn, "="); idx >= 0 {
+		return strings.EqualFold(strings.TrimSpace(rdn[idx+1:]), want)
+	}
+	return false
+}
@@ -1,14 +1,49 @@
 package model
 
-import "time"
-
+// Representasi tabel "permissions" (format nama: resource:action)
 type Permission struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string `json:"id" db:"id"`
+	Name        string `json:"name" db:"name"`
+	Resource    string `json:"resource" db:"resource"`
+	Action      string `json:"action" db:"action"`
+	Description string `json:"description" db:"description"`
 }
 
 type PermissionResponse struct {
-	Name string `json:"name"`
-}
\ No newline at end of file
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Resource    string `json:"resource"`
+	Action      string `json:"action"`
+	Description string `json:"description"`
+}
+
+// ===================== PERMISSION NAMES ========================
+// Permission yang dipakai app/policy untuk menentukan jangkauan akses
+
+const (
+	PermissionAchievementRead           = "achievement:read"
+	PermissionAchievementReadAll        = "achievement:read_all"        // semua prestasi / mahasiswa
+	PermissionAchievementReadDepartment = "achievement:read_department" // mahasiswa satu program studi dengan dosen
+	PermissionAchievementVerify         = "achievement:verify"
+	PermissionAchievementUpdate         = "achievement:update"
+	PermissionAchievementDelete         = "achievement:delete"
+)
+
+// ===================== ACCESS SCOPE ========================
+// Jangkauan data yang boleh dibaca actor, hasil keputusan app/policy.
+// Dipakai repository untuk memfilter query.
+
+const (
+	ScopeAll        = "all"        // semua data
+	ScopeDepartment = "department" // mahasiswa dengan program_study = departemen dosen (+ advisee sendiri)
+	ScopeAdvisees   = "advisees"   // mahasiswa bimbingan (students.advisor_id)
+	ScopeOwn        = "own"        // data milik mahasiswa itu sendiri
+	ScopeNone       = "none"       // tidak ada akses
+)
+
+type AccessScope struct {
+	Kind       string
+	StudentID  string // ScopeOwn
+	AdvisorID  string // ScopeAdvisees, ScopeDepartment
+	Department string // ScopeDepartment
+}
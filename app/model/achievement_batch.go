@@ -0,0 +1,45 @@
+package model
+
+// ===================== BATCH VERIFY/REJECT REQUEST ========================
+// Mode "atomic" (default): semua item harus lolos atau tidak ada yang diubah.
+// Mode "partial": item yang lolos tetap diproses walaupun item lain gagal.
+// Maksimal 100 item per request.
+
+const (
+	BatchModeAtomic  = "atomic"
+	BatchModePartial = "partial"
+)
+
+type BatchVerifyRequest struct {
+	IDs  []string `json:"ids" validate:"required,min=1,max=100,dive,required"`
+	Mode string   `json:"mode" validate:"omitempty,oneof=atomic partial"`
+}
+
+type BatchRejectRequest struct {
+	Items         []BatchRejectItem `json:"items" validate:"required,min=1,max=100,dive"`
+	RejectionNote string            `json:"rejection_note"` // dipakai untuk item tanpa rejection_note sendiri
+	Mode          string            `json:"mode" validate:"omitempty,oneof=atomic partial"`
+}
+
+type BatchRejectItem struct {
+	ID            string `json:"id" validate:"required"`
+	RejectionNote string `json:"rejection_note"`
+}
+
+// ===================== BATCH RESPONSE ========================
+
+type BatchItemResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Status  string `json:"status,omitempty"` // status prestasi setelah diproses (atau status saat ini jika gagal)
+	Code    int    `json:"code,omitempty"`   // kode HTTP yang setara jika item dikirim sendiri
+	Error   string `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	Mode      string            `json:"mode"`
+	Total     int               `json:"total"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
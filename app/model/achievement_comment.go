@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// ===================== ACHIEVEMENT COMMENT (POSTGRESQL) ========================
+// Representasi tabel "achievement_comments": diskusi mahasiswa dan dosen wali pada prestasi.
+// ParentID nil = komentar utama, selain itu balasan (thread).
+
+type AchievementComment struct {
+	ID               string               `json:"id" db:"id"`
+	AchievementRefID string               `json:"achievement_ref_id" db:"achievement_ref_id"`
+	ParentID         *string              `json:"parent_id,omitempty" db:"parent_id"`
+	AuthorID         *string              `json:"author_id,omitempty" db:"author_id"`
+	AuthorRole       string               `json:"author_role,omitempty" db:"author_role"` // role penulis saat komentar dibuat
+	AuthorName       string               `json:"author_name,omitempty"`                  // dari join ke users, bukan kolom
+	Body             string               `json:"body" db:"body"`
+	Mentions         []CommentMention     `json:"mentions"`
+	Replies          []AchievementComment `json:"replies,omitempty"`
+	CreatedAt        time.Time            `json:"created_at" db:"created_at"`
+}
+
+// CommentMention - User yang disebut dengan @username di komentar
+// (tabel "achievement_comment_mentions")
+type CommentMention struct {
+	UserID   string `json:"user_id" db:"user_id"`
+	Username string `json:"username"`
+}
+
+// ===================== COMMENT REQUEST ========================
+
+type AchievementCommentRequest struct {
+	Body     string  `json:"body" validate:"required,max=2000"`
+	ParentID *string `json:"parent_id"` // isi untuk membalas komentar lain
+}
+
+// ===================== REQUEST REVISION REQUEST ========================
+
+type RequestRevisionRequest struct {
+	Note string `json:"note" validate:"required"`
+}
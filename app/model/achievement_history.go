@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// ===================== ACHIEVEMENT STATUS HISTORY (POSTGRESQL) ========================
+// Representasi tabel "achievement_status_history": satu baris per perubahan status.
+// Baris pertama setiap prestasi (FromStatus nil) adalah saat prestasi dibuat.
+
+type AchievementStatusHistory struct {
+	ID               string     `json:"id" db:"id"`
+	AchievementRefID string     `json:"achievement_ref_id" db:"achievement_ref_id"`
+	FromStatus       *string    `json:"from_status,omitempty" db:"from_status"`
+	ToStatus         string     `json:"to_status" db:"to_status"`
+	ActorID          *string    `json:"actor_id,omitempty" db:"actor_id"`
+	ActorRole        string     `json:"actor_role,omitempty" db:"actor_role"`     // role actor saat transisi terjadi
+	ActorName        string     `json:"actor_name,omitempty"`                     // dari join ke users, bukan kolom
+	OnBehalfOf       *string    `json:"on_behalf_of,omitempty" db:"on_behalf_of"` // dosen wali yang diwakili jika actor delegasi
+	OnBehalfOfName   string     `json:"on_behalf_of_name,omitempty"`              // dari join ke users, bukan kolom
+	ApprovalStage    *int       `json:"approval_stage,omitempty" db:"approval_stage"`
+	StageName        *string    `json:"approval_stage_name,omitempty" db:"approval_stage_name"` // tahap persetujuan yang diputuskan actor
+	Note             *string    `json:"note,omitempty" db:"note"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
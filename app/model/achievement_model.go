@@ -21,6 +21,7 @@ type Achievement struct {
 	Points          int                    `bson:"points" json:"points"`
 	CreatedAt       time.Time              `bson:"createdAt" json:"created_at"`
 	UpdatedAt       time.Time              `bson:"updatedAt" json:"updated_at"`
+	DeletedAt       *time.Time             `bson:"deletedAt,omitempty" json:"deleted_at,omitempty"` // soft delete, dihapus permanen oleh purge job
 }
 
 type Attachment struct {
@@ -30,6 +31,24 @@ type Attachment struct {
 	UploadedAt time.Time `bson:"uploadedAt" json:"uploaded_at"`
 }
 
+// ===================== ACHIEVEMENT STATUS ========================
+// Transisi antar status diatur app/workflow
+
+const (
+	AchievementStatusDraft     = "draft"
+	AchievementStatusSubmitted = "submitted"
+	AchievementStatusVerified  = "verified"
+	AchievementStatusRejected  = "rejected"
+	AchievementStatusDeleted   = "deleted"
+
+	// Dikembalikan ke mahasiswa untuk diperbaiki, tidak dihitung sebagai penolakan
+	AchievementStatusRevisionRequested = "revision_requested"
+)
+
+// ErrCodeInvalidTransition - Aksi tidak valid untuk status prestasi saat ini (409).
+// Field data berisi current_status dan allowed_next_states.
+const ErrCodeInvalidTransition = "INVALID_TRANSITION"
+
 // ===================== ACHIEVEMENT REFERENCE (POSTGRESQL) ========================
 
 
@@ -37,11 +56,15 @@ type AchievementReference struct {
 	ID                 string     `json:"id" db:"id"`
 	StudentID          string     `json:"student_id" db:"student_id"`
 	MongoAchievementID string     `json:"mongo_achievement_id" db:"mongo_achievement_id"`
-	Status             string     `json:"status" db:"status"` // 'draft', 'submitted', 'verified', 'rejected', 'deleted'
+	Status             string     `json:"status" db:"status"` // 'draft', 'submitted', 'verified', 'rejected', 'revision_requested', 'deleted'
 	SubmittedAt        *time.Time `json:"submitted_at,omitempty" db:"submitted_at"`
 	VerifiedAt         *time.Time `json:"verified_at,omitempty" db:"verified_at"`
 	VerifiedBy         *string    `json:"verified_by,omitempty" db:"verified_by"`
-	RejectionNote      *string    `json:"rejection_note,omitempty" db:"rejection_note"`
+	RejectionNote      *string    `json:"rejection_note,omitempty" db:"rejection_note"` // catatan penolakan terakhir, tetap ada saat diajukan ulang
+	SubmissionCount    int        `json:"submission_count" db:"submission_count"`
+	ApprovalChain      *string    `json:"approval_chain,omitempty" db:"approval_chain"` // rantai persetujuan, dipilih saat diajukan
+	ApprovalStage      int        `json:"approval_stage" db:"approval_stage"`           // indeks tahap yang sedang menunggu (0 = tahap pertama)
+	DeletedAt          *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`         // diisi saat status deleted, acuan retensi purge
 	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
 }
@@ -95,10 +118,22 @@ type AchievementResponse struct {
 	VerifiedAt      *string                `json:"verified_at,omitempty"`
 	VerifiedBy      *string                `json:"verified_by,omitempty"`
 	RejectionNote   *string                `json:"rejection_note,omitempty"`
+	SubmissionCount int                    `json:"submission_count"`
+	Approval        *ApprovalProgress      `json:"approval,omitempty"`
+	DeletedAt       *string                `json:"deleted_at,omitempty"`
 	CreatedAt       string                 `json:"created_at"`
 	UpdatedAt       string                 `json:"updated_at"`
 }
 
+// ApprovalProgress - Posisi prestasi di rantai persetujuannya (tahap dihitung dari 1)
+type ApprovalProgress struct {
+	Chain       string   `json:"chain"`
+	Stages      []string `json:"stages"`
+	Stage       int      `json:"stage"`
+	StageName   string   `json:"stage_name"`
+	TotalStages int      `json:"total_stages"`
+}
+
 // ===================== ACHIEVEMENT LIST RESPONSE ========================
 
 type AchievementListResponse struct {
@@ -109,6 +144,15 @@ type AchievementListResponse struct {
 	TotalPages   int                   `json:"total_pages"`
 }
 
+// ===================== PURGE RESULT ========================
+
+// AchievementPurgeResult - Hasil satu kali purge prestasi terhapus yang melewati masa retensi
+type AchievementPurgeResult struct {
+	Checked      int `json:"checked"`
+	Purged       int `json:"purged"`
+	FilesRemoved int `json:"files_removed"`
+}
+
 // ===================== UPLOAD ATTACHMENT REQUEST ========================
 
 type UploadAttachmentRequest struct {
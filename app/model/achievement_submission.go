@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// ===================== ACHIEVEMENT SUBMISSION (POSTGRESQL) ========================
+// Representasi tabel "achievement_submissions": snapshot isi prestasi (MongoDB)
+// setiap kali diajukan, supaya dosen wali bisa melihat perubahan sejak pengajuan sebelumnya.
+
+type AchievementSubmission struct {
+	ID               string              `json:"id" db:"id"`
+	AchievementRefID string              `json:"achievement_ref_id" db:"achievement_ref_id"`
+	SubmissionNumber int                 `json:"submission_number" db:"submission_number"` // 1 = pengajuan pertama
+	Snapshot         AchievementSnapshot `json:"snapshot" db:"snapshot"`
+	SubmittedBy      *string             `json:"submitted_by,omitempty" db:"submitted_by"`
+	CreatedAt        time.Time           `json:"created_at" db:"created_at"`
+}
+
+// AchievementSnapshot - Field prestasi yang bisa diubah mahasiswa
+type AchievementSnapshot struct {
+	AchievementType string                 `json:"achievement_type"`
+	Title           string                 `json:"title"`
+	Description     string                 `json:"description"`
+	Details         map[string]interface{} `json:"details"`
+	Tags            []string               `json:"tags"`
+	Points          int                    `json:"points"`
+	Attachments     []Attachment           `json:"attachments"`
+}
+
+// NewAchievementSnapshot - Snapshot dari dokumen MongoDB
+func NewAchievementSnapshot(achievement *Achievement) *AchievementSnapshot {
+	return &AchievementSnapshot{
+		AchievementType: achievement.AchievementType,
+		Title:           achievement.Title,
+		Description:     achievement.Description,
+		Details:         achievement.Details,
+		Tags:            achievement.Tags,
+		Points:          achievement.Points,
+		Attachments:     achievement.Attachments,
+	}
+}
+
+// ===================== ACHIEVEMENT CHANGES RESPONSE ========================
+
+type FieldChange struct {
+	Field    string      `json:"field"` // mis. "title", "details.rank"
+	Previous interface{} `json:"previous"`
+	Current  interface{} `json:"current"`
+}
+
+type AchievementChangesResponse struct {
+	AchievementID         string        `json:"achievement_id"`
+	Status                string        `json:"status"`
+	SubmissionCount       int           `json:"submission_count"`
+	ComparedToSubmission  *int          `json:"compared_to_submission"` // nil jika belum ada pengajuan sebelumnya
+	PreviousRejectionNote *string       `json:"previous_rejection_note,omitempty"`
+	Changes               []FieldChange `json:"changes"`
+}
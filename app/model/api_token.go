@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// ===================== API TOKEN ENTITY ========================
+// Representasi tabel "api_tokens" di database
+// Token personal untuk script/integrasi. Token asli hanya ditampilkan sekali;
+// yang disimpan hanya hash SHA-256 dan prefix untuk identifikasi di UI.
+
+type APIToken struct {
+	ID          string     `json:"id" db:"id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	Name        string     `json:"name" db:"name"`
+	TokenHash   string     `json:"-" db:"token_hash"`
+	TokenPrefix string     `json:"token_prefix" db:"token_prefix"`
+	Scopes      []string   `json:"scopes" db:"scopes"` // subset permission pemilik saat dibuat
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"` // nil = tidak kadaluarsa
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ===================== API TOKEN CREATE REQUEST ========================
+
+type APITokenCreateRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1"` // nama permission
+	ExpiresInDays int      `json:"expires_in_days" validate:"min=0"` // 0 = tidak kadaluarsa
+}
+
+// ===================== API TOKEN RESPONSE ========================
+
+type APITokenResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	TokenPrefix string   `json:"token_prefix"`
+	Scopes      []string `json:"scopes"`
+	ExpiresAt   string   `json:"expires_at,omitempty"`
+	LastUsedAt  string   `json:"last_used_at,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// APITokenCreatedResponse - Token asli hanya ada di response pembuatan
+type APITokenCreatedResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
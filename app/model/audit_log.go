@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Aksi yang dicatat di audit_logs
+const (
+	AuditActionImpersonationStart   = "impersonation.start"   // admin mulai login sebagai user lain
+	AuditActionImpersonationRequest = "impersonation.request" // setiap request dengan token impersonation
+)
+
+// ===================== AUDIT LOG ENTITY ========================
+// Representasi tabel "audit_logs" di database
+
+type AuditLog struct {
+	ID         string    `json:"id" db:"id"`
+	Action     string    `json:"action" db:"action"`
+	ActorID    string    `json:"actor_id" db:"actor_id"`     // user yang sebenarnya (admin)
+	SubjectID  string    `json:"subject_id" db:"subject_id"` // user yang di-impersonate
+	Method     string    `json:"method" db:"method"`
+	Path       string    `json:"path" db:"path"`
+	StatusCode int       `json:"status_code" db:"status_code"`
+	IPAddress  string    `json:"ip_address" db:"ip_address"`
+	Detail     string    `json:"detail,omitempty" db:"detail"` // mis. alasan impersonation
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// ===================== IMPERSONATION ========================
+
+// ImpersonationActor - Admin di balik token impersonation (claim "act", RFC 8693)
+type ImpersonationActor struct {
+	UserID   string `json:"sub"`
+	Username string `json:"username"`
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"` // dicatat di audit log
+}
+
+type ImpersonationResponse struct {
+	Token          string       `json:"token"`
+	ExpiresAt      string       `json:"expires_at"`
+	User           UserResponse `json:"user"`
+	ImpersonatedBy string       `json:"impersonated_by"`
+	ReadOnly       bool         `json:"read_only"` // true = hanya GET yang diizinkan
+}
@@ -19,9 +19,10 @@ type RefreshTokenRequest struct {
 // Menghasilkan token + data user
 
 type LoginResponse struct {
-	Token        string       `json:"token"`
-	RefreshToken string       `json:"refreshToken"`
-	User         UserResponse `json:"user"`
+	Token         string       `json:"token"`
+	RefreshToken  string       `json:"refreshToken"`
+	User          UserResponse `json:"user"`
+	RecoveryCodes []string     `json:"recoveryCodes,omitempty"` // hanya saat MFA baru diaktifkan lewat login
 }
 
 // ===================== API RESPONSE WRAPPER ==================
@@ -32,22 +33,72 @@ type APIResponse struct {
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"` // kode error yang bisa dibaca mesin (lihat ErrCode*)
 }
 
+// ===================== AUTH ERROR CODES ======================
+// Dikirim di field "code" saat login / refresh ditolak
+//
+//	INVALID_CREDENTIALS  401  username atau password salah
+//	ACCOUNT_INACTIVE     403  akun dinonaktifkan admin (is_active = false)
+//	ACCOUNT_LOCKED       423  akun dikunci sementara (locked_until > sekarang)
+//	ACCOUNT_DELETED      403  akun sudah dihapus (deleted_at terisi)
+//	TOO_MANY_REQUESTS    429  terlalu banyak percobaan, lihat header Retry-After
+//	MFA_REQUIRED         400  role mewajibkan MFA, setup TOTP dulu lewat /auth/mfa/challenge/setup
+
+const (
+	ErrCodeInvalidCredentials = "INVALID_CREDENTIALS"
+	ErrCodeAccountInactive    = "ACCOUNT_INACTIVE"
+	ErrCodeAccountLocked      = "ACCOUNT_LOCKED"
+	ErrCodeAccountDeleted     = "ACCOUNT_DELETED"
+	ErrCodeTooManyRequests    = "TOO_MANY_REQUESTS"
+	ErrCodeMFARequired        = "MFA_REQUIRED"
+)
+
 // ===================== JWT ACCESS TOKEN CLAIMS ===============
 
 type JWTClaims struct {
 	UserID      string   `json:"user_id"`
 	Username    string   `json:"username"`
 	Role        string   `json:"role"`
-	Permissions []string `json:"permissions"`
+	RoleID      string   `json:"role_id,omitempty"`
+	Permissions []string `json:"permissions"` // snapshot saat login; AuthRequired menimpa dengan permission terbaru dari database
+	Purpose     string   `json:"purpose,omitempty"` // kosong = access token; TokenPurposeMFA / TokenPurposeRefresh bukan access token
+	SessionID   string   `json:"sid,omitempty"`     // ID sesi login (user_sessions); kosong untuk token lama
+	APITokenID  string   `json:"-"`                 // terisi jika request memakai API token personal (bukan JWT)
+
+	Impersonator *ImpersonationActor `json:"act,omitempty"` // terisi jika token hasil impersonation oleh admin
 
 	jwt.RegisteredClaims
 }
 
 // ===================== JWT REFRESH TOKEN CLAIMS ==============
 
+// TokenPurposeRefresh - Nilai claim "purpose" untuk refresh token.
+// Refresh token hanya bisa ditukar di /auth/refresh, tidak diterima sebagai access token.
+const TokenPurposeRefresh = "refresh"
+
 type RefreshTokenClaims struct {
-	UserID string `json:"user_id"`
+	UserID  string `json:"user_id"`
+	Purpose string `json:"purpose"` // selalu TokenPurposeRefresh
 	jwt.RegisteredClaims
 }
+
+// ===================== JSON WEB KEY SET ======================
+// Public key untuk verifikasi token (GET /.well-known/jwks.json, RFC 7517)
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve (Ed25519) atau EC curve (P-256)
+	X   string `json:"x,omitempty"`   // OKP public key / EC x
+	Y   string `json:"y,omitempty"`   // EC y (hanya untuk key IdP eksternal)
+}
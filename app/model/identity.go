@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Nama provider identitas eksternal (kolom user_identities.provider)
+const (
+	IdentityProviderOIDC = "oidc"
+	IdentityProviderLDAP = "ldap"
+)
+
+// TokenPurposeOIDCState - Nilai claim "purpose" untuk state login OIDC (cookie)
+const TokenPurposeOIDCState = "oidc_state"
+
+// ===================== USER IDENTITY ENTITY ========================
+// Representasi tabel "user_identities" di database
+// Menghubungkan akun di direktori kampus (provider + subject) ke user lokal
+
+type UserIdentity struct {
+	ID          string     `json:"id" db:"id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	Provider    string     `json:"provider" db:"provider"`
+	Subject     string     `json:"subject" db:"subject"` // "sub" OIDC atau DN LDAP
+	LastLoginAt *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ===================== EXTERNAL IDENTITY ========================
+// Hasil autentikasi dari identity provider (belum tentu punya user lokal)
+
+type ExternalIdentity struct {
+	Provider   string
+	Subject    string
+	Username   string
+	Email      string
+	FullName   string
+	Groups     []string
+	StudentID  string // NIM, untuk link ke tabel students
+	LecturerID string // NIP, untuk link ke tabel lecturers
+}
+
+// ===================== OIDC STATE CLAIMS ========================
+// Disimpan di cookie selama redirect ke IdP; ID = parameter state
+
+type OIDCStateClaims struct {
+	Nonce   string `json:"nonce"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
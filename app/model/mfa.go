@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// TokenPurposeMFA - Nilai claim "purpose" untuk challenge token MFA.
+// Challenge token hanya bisa ditukar di /auth/mfa/*, tidak diterima sebagai access token.
+const TokenPurposeMFA = "mfa"
+
+// ===================== MFA RECOVERY CODE ENTITY ========================
+// Representasi tabel "mfa_recovery_codes" di database
+// Kode asli hanya ditampilkan sekali ke user; yang disimpan hanya hash SHA-256
+
+type MFARecoveryCode struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	CodeHash  string     `json:"-" db:"code_hash"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ===================== MFA CHALLENGE RESPONSE ========================
+// Dikembalikan oleh login jika user memakai MFA (atau role mewajibkannya).
+// EnrollmentRequired = true: user belum punya TOTP, harus setup dulu lewat
+// /auth/mfa/challenge/setup sebelum /auth/mfa/verify.
+
+type MFAChallengeResponse struct {
+	MFARequired        bool   `json:"mfaRequired"`
+	EnrollmentRequired bool   `json:"enrollmentRequired"`
+	ChallengeToken     string `json:"challengeToken"`
+	ExpiresAt          string `json:"expiresAt"`
+}
+
+// ===================== MFA VERIFY REQUEST ========================
+// Langkah kedua login; Code berisi kode TOTP 6 digit atau recovery code
+
+type MFAVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// MFAChallengeSetupRequest - Enrollment saat login untuk role yang mewajibkan MFA
+type MFAChallengeSetupRequest struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+}
+
+// ===================== MFA SETUP RESPONSE ========================
+// Secret + URI otpauth:// (untuk QR code); MFA belum aktif sampai kode dikonfirmasi
+
+type MFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+// ===================== MFA ACTIVATE / DISABLE REQUEST ========================
+
+type MFAActivateRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type MFADisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"` // kode TOTP atau recovery code
+}
+
+// ===================== MFA RECOVERY CODES RESPONSE ========================
+// Ditampilkan sekali saat MFA diaktifkan; masing-masing hanya bisa dipakai sekali
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Jenis notifikasi
+const (
+	NotificationVerificationReminder  = "verification.reminder"  // prestasi bimbingan menunggu verifikasi melewati batas pengingat
+	NotificationVerificationEscalated = "verification.escalated" // prestasi dieskalasi karena dosen wali belum memverifikasi
+	NotificationCommentMention        = "comment.mention"        // user di-mention (@username) di komentar prestasi
+)
+
+// ===================== NOTIFICATION ENTITY ========================
+// Representasi tabel "notifications" (notifikasi in-app per user)
+
+type Notification struct {
+	ID               string     `json:"id" db:"id"`
+	UserID           string     `json:"user_id" db:"user_id"`
+	Type             string     `json:"type" db:"type"`
+	AchievementRefID *string    `json:"achievement_ref_id,omitempty" db:"achievement_ref_id"`
+	Message          string     `json:"message" db:"message"`
+	ReadAt           *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// ===================== PASSWORD RESET TOKEN ENTITY ========================
+// Representasi tabel "password_reset_tokens" di database
+// Token asli hanya dikirim sekali ke admin; yang disimpan hanya hash SHA-256
+
+type PasswordResetToken struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	CreatedBy string     `json:"created_by" db:"created_by"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ===================== CHANGE PASSWORD REQUEST ========================
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// ===================== RESET PASSWORD REQUEST ========================
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// ===================== PASSWORD RESET TOKEN RESPONSE ========================
+// Dikembalikan ke admin saat reset diminta; token hanya bisa dipakai sekali
+
+type PasswordResetTokenResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// ===================== REFRESH TOKEN ENTITY ========================
+// Representasi tabel "refresh_tokens" di database
+// ID = jti di dalam refresh token JWT
+// FamilyID = ID rantai rotasi; semua token hasil refresh dari satu login
+// berbagi FamilyID yang sama
+
+type RefreshToken struct {
+	ID         string     `json:"id" db:"id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	FamilyID   string     `json:"family_id" db:"family_id"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	ReplacedBy *string    `json:"replaced_by,omitempty" db:"replaced_by"` // jti token pengganti (jika sudah dirotasi)
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
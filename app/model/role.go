@@ -2,15 +2,58 @@ package model
 
 import "time"
 
+// ===================== BUILT-IN ROLES ========================
+// Role bawaan dari seeder; tidak boleh dihapus atau di-rename lewat API
+
+const (
+	RoleAdmin     = "Admin"
+	RoleMahasiswa = "Mahasiswa"
+	RoleDosenWali = "Dosen Wali"
+)
+
+// PermissionUserManage - Permission yang tidak boleh dicabut dari role Admin
+// supaya admin tidak mengunci dirinya sendiri dari manajemen role/user
+const PermissionUserManage = "user:manage"
+
+// IsBuiltInRole - true untuk role bawaan (Admin, Mahasiswa, Dosen Wali)
+func IsBuiltInRole(name string) bool {
+	return name == RoleAdmin || name == RoleMahasiswa || name == RoleDosenWali
+}
+
 type Role struct {
 	ID          string    `json:"id" db:"id"`
 	Name        string    `json:"name" db:"name"`
 	Description string    `json:"description" db:"description"` // ⭐ TAMBAHKAN INI
+	MFARequired bool      `json:"mfa_required" db:"mfa_required"` // user dengan role ini wajib MFA saat login
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
 type RoleResponse struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"` // ⭐ TAMBAHKAN INI JUGA (opsional)
-}
\ No newline at end of file
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"` // ⭐ TAMBAHKAN INI JUGA (opsional)
+	BuiltIn     bool     `json:"built_in"`
+	MFARequired bool     `json:"mfa_required"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// ===================== ROLE REQUESTS ========================
+
+type RoleCreateRequest struct {
+	Name        string   `json:"name" validate:"required,max=50"`
+	Description string   `json:"description"`
+	MFARequired bool     `json:"mfa_required"`
+	Permissions []string `json:"permissions"` // nama permission, opsional
+}
+
+type RoleUpdateRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=50"`
+	Description *string `json:"description,omitempty"`
+	MFARequired *bool   `json:"mfa_required,omitempty"`
+}
+
+// RolePermissionRequest - Attach permission ke role (berdasarkan nama permission)
+type RolePermissionRequest struct {
+	Permission string `json:"permission" validate:"required"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// ===================== USER SESSION ENTITY ========================
+// Representasi tabel "user_sessions" di database
+// Satu sesi = satu login (satu device). ID sesi sama dengan FamilyID
+// refresh token, dan dibawa di access token sebagai claim "sid".
+
+type UserSession struct {
+	ID         string     `json:"id" db:"id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	IPAddress  string     `json:"ip_address" db:"ip_address"`
+	IssuedAt   time.Time  `json:"issued_at" db:"issued_at"`
+	LastUsedAt time.Time  `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// ===================== SESSION RESPONSE ========================
+// Current = true untuk sesi yang dipakai request ini
+
+type SessionResponse struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	IssuedAt   string `json:"issued_at"`
+	LastUsedAt string `json:"last_used_at"`
+	Current    bool   `json:"current"`
+}
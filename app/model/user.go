@@ -6,16 +6,45 @@ import "time"
 // Representasi tabel "users" di database
 
 type User struct {
-	ID           string    `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` 
-	FullName     string    `json:"full_name"`
-	RoleID       string    `json:"role_id"`
-	Role         string    `json:"role"`
-	IsActive     bool      `json:"is_active"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                  string     `json:"id"`
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	PasswordHash        string     `json:"-"`
+	FullName            string     `json:"full_name"`
+	RoleID              string     `json:"role_id"`
+	Role                string     `json:"role"`
+	IsActive            bool       `json:"is_active"`
+	FailedLoginAttempts int        `json:"failed_login_attempts"`  // reset ke 0 saat login berhasil / admin unlock
+	LockedUntil         *time.Time `json:"locked_until,omitempty"` // terisi jika akun dikunci sementara
+	DeletedAt           *time.Time `json:"deleted_at,omitempty"`   // terisi jika akun sudah dihapus (soft delete)
+	MFAEnabled          bool       `json:"mfa_enabled"`            // true setelah enrollment TOTP dikonfirmasi
+	MFASecret           *string    `json:"-"`                      // secret TOTP (base32), terisi sejak setup walau belum aktif
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// ===================== ACCOUNT STATUS ========================
+// Status akun diturunkan dari kolom is_active, locked_until dan deleted_at
+// di tabel "users". Urutan prioritas: deleted > locked > inactive > active
+
+const (
+	AccountStatusActive   = "active"
+	AccountStatusInactive = "inactive"
+	AccountStatusLocked   = "locked"
+	AccountStatusDeleted  = "deleted"
+)
+
+func (u *User) AccountStatus() string {
+	if u.DeletedAt != nil {
+		return AccountStatusDeleted
+	}
+	if u.LockedUntil != nil && u.LockedUntil.After(time.Now()) {
+		return AccountStatusLocked
+	}
+	if !u.IsActive {
+		return AccountStatusInactive
+	}
+	return AccountStatusActive
 }
 
 // ===================== USER CREATE REQUEST =====================
@@ -58,11 +87,17 @@ type UserResponse struct {
 	Email           string            `json:"email"`
 	FullName        string            `json:"full_name"`
 	Role            string            `json:"role"`
+	RoleID          string            `json:"role_id,omitempty"`
 	IsActive        bool              `json:"is_active"`
+	AccountStatus   string            `json:"account_status,omitempty"` // active, inactive, locked, deleted
+	LockedUntil     *string           `json:"locked_until,omitempty"`
+	FailedLogins    int               `json:"failed_login_attempts,omitempty"`
+	MFAEnabled      bool              `json:"mfa_enabled"`
 	CreatedAt       string            `json:"created_at"`
 	Permissions     []string          `json:"permissions,omitempty"` 
 	StudentProfile  *StudentResponse  `json:"student_profile,omitempty"`  // jika role = Mahasiswa
 	LecturerProfile *LecturerResponse `json:"lecturer_profile,omitempty"` // jika role = Dosen Wali
+	ImpersonatedBy  string            `json:"impersonated_by,omitempty"`  // username admin jika sedang impersonation
 }
 
 // ===================== USER LIST RESPONSE ====================
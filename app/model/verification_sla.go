@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// Tahap SLA prestasi yang menunggu verifikasi
+const (
+	SLAStageReminder   = "reminder"   // melewati batas pengingat, dosen wali diingatkan
+	SLAStageEscalation = "escalation" // melewati batas eskalasi, diteruskan ke reviewer departemen / admin
+)
+
+// ===================== OVERDUE ACHIEVEMENT ========================
+// Prestasi berstatus submitted yang menunggu lebih lama dari batas SLA.
+// Lama menunggu dihitung dari awal tahap persetujuan saat ini (StageStartedAt,
+// atau SubmittedAt di tahap pertama). RemindedAt / EscalatedAt sebelum awal tahap
+// berasal dari tahap / pengajuan sebelumnya dan dianggap belum dikirim
+// (dikosongkan oleh repository).
+
+type OverdueAchievement struct {
+	ID           string     `json:"id"`
+	StudentID    string     `json:"student_id"`
+	StudentNIM   string     `json:"student_nim"`
+	StudentName  string     `json:"student_name"`
+	ProgramStudy string     `json:"program_study"`
+	AdvisorID    *string    `json:"advisor_id,omitempty"`
+	AdvisorName  *string    `json:"advisor_name,omitempty"`
+	SubmittedAt  time.Time  `json:"submitted_at"`
+
+	ApprovalChain     *string    `json:"-"`
+	ApprovalStage     int        `json:"-"`
+	ApprovalStageName string     `json:"approval_stage_name,omitempty"` // diisi service dari rantai persetujuan
+	StageStartedAt    *time.Time `json:"stage_started_at,omitempty"`    // persetujuan tahap sebelumnya; nil di tahap pertama
+
+	WaitingDays  int        `json:"waiting_days"`
+	Stage        string     `json:"stage"` // reminder / escalation
+	RemindedAt   *time.Time `json:"reminded_at,omitempty"`
+	EscalatedAt  *time.Time `json:"escalated_at,omitempty"`
+}
+
+// ===================== OVERDUE REPORT RESPONSE ========================
+// GET /api/v1/reports/overdue
+
+type OverdueReport struct {
+	ReminderAfterDays float64              `json:"reminder_after_days"`
+	EscalateAfterDays float64              `json:"escalate_after_days"`
+	Total             int                  `json:"total"`
+	Escalated         int                  `json:"escalated"`
+	Items             []OverdueAchievement `json:"items"`
+}
+
+// SLARunResult - Hasil satu kali pengecekan scheduler SLA
+type SLARunResult struct {
+	Checked   int `json:"checked"`
+	Reminded  int `json:"reminded"`
+	Escalated int `json:"escalated"`
+}
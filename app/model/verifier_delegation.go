@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// ===================== VERIFIER DELEGATION ENTITY ========================
+// Representasi tabel "verifier_delegations": dosen wali (advisor) memberi hak
+// verify/reject atas mahasiswa bimbingannya ke dosen lain (delegate) selama
+// StartsOn..EndsOn (inklusif), mis. saat cuti / sabbatical.
+
+type VerifierDelegation struct {
+	ID           string     `json:"id" db:"id"`
+	AdvisorID    string     `json:"advisor_id" db:"advisor_id"`
+	AdvisorName  string     `json:"advisor_name,omitempty"` // dari join ke users, bukan kolom
+	DelegateID   string     `json:"delegate_id" db:"delegate_id"`
+	DelegateName string     `json:"delegate_name,omitempty"`  // dari join ke users, bukan kolom
+	StartsOn     string     `json:"starts_on" db:"starts_on"` // YYYY-MM-DD
+	EndsOn       string     `json:"ends_on" db:"ends_on"`     // YYYY-MM-DD
+	Reason       *string    `json:"reason,omitempty" db:"reason"`
+	CreatedBy    *string    `json:"created_by,omitempty" db:"created_by"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	Active       bool       `json:"active"` // belum dicabut dan berlaku hari ini (dihitung service)
+}
+
+// IsActiveOn - Delegasi belum dicabut dan tanggal (YYYY-MM-DD) ada di rentang StartsOn..EndsOn
+func (d *VerifierDelegation) IsActiveOn(date string) bool {
+	return d.RevokedAt == nil && d.StartsOn <= date && date <= d.EndsOn
+}
+
+// ===================== CREATE DELEGATION REQUEST ========================
+// POST /api/v1/lecturers/:id/delegations (:id = dosen wali yang mendelegasikan)
+
+type CreateDelegationRequest struct {
+	DelegateID string  `json:"delegate_id" validate:"required"`
+	StartsOn   string  `json:"starts_on" validate:"required,datetime=2006-01-02"`
+	EndsOn     string  `json:"ends_on" validate:"required,datetime=2006-01-02"`
+	Reason     *string `json:"reason" validate:"omitempty,max=255"`
+}
@@ -0,0 +1,272 @@
+package policy
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/repository"
+)
+
+// Policy - Titik tunggal keputusan otorisasi.
+// Keputusan diambil dari permission (role → permission di database) dan
+// relasi data (pemilik, dosen wali, departemen), bukan dari nama role,
+// sehingga role baru (mis. Kaprodi) cukup diberi permission yang sesuai.
+type Policy struct {
+	studentRepo  repository.StudentRepository
+	lecturerRepo repository.LecturerRepository
+}
+
+func New(
+	studentRepo repository.StudentRepository,
+	lecturerRepo repository.LecturerRepository,
+) *Policy {
+	return &Policy{
+		studentRepo:  studentRepo,
+		lecturerRepo: lecturerRepo,
+	}
+}
+
+// Actor - User yang sedang melakukan request.
+// Profil mahasiswa/dosen di-load sekali saat pertama kali dibutuhkan.
+type Actor struct {
+	UserID string
+
+	policy      *Policy
+	permissions map[string]bool
+
+	student        *model.Student
+	studentLoaded  bool
+	lecturer       *model.Lecturer
+	lecturerLoaded bool
+
+	delegations map[string]bool // advisorID → actor delegasi aktif
+}
+
+// Actor - Buat actor dari claims JWT (claims.Permissions sudah diperbarui oleh AuthRequired)
+func (p *Policy) Actor(claims *model.JWTClaims) *Actor {
+	permissions := make(map[string]bool, len(claims.Permissions))
+	for _, perm := range claims.Permissions {
+		permissions[perm] = true
+	}
+
+	return &Actor{
+		UserID:      claims.UserID,
+		policy:      p,
+		permissions: permissions,
+	}
+}
+
+// Can - Cek apakah actor memiliki permission
+func (a *Actor) Can(permission string) bool {
+	return a.permissions[permission]
+}
+
+// Student - Profil mahasiswa milik actor (nil jika actor bukan mahasiswa)
+func (a *Actor) Student() *model.Student {
+	if !a.studentLoaded {
+		a.student, _ = a.policy.studentRepo.FindByUserID(a.UserID)
+		a.studentLoaded = true
+	}
+	return a.student
+}
+
+// Lecturer - Profil dosen milik actor (nil jika actor bukan dosen)
+func (a *Actor) Lecturer() *model.Lecturer {
+	if !a.lecturerLoaded {
+		a.lecturer, _ = a.policy.lecturerRepo.FindByUserID(a.UserID)
+		a.lecturerLoaded = true
+	}
+	return a.lecturer
+}
+
+//
+// ==================== READ ======================
+//
+
+// ReadScope - Jangkauan data mahasiswa/prestasi yang boleh dibaca actor:
+//   - achievement:read_all                 → semua
+//   - profil mahasiswa                     → milik sendiri
+//   - profil dosen + achievement:read_department → satu program studi + bimbingan
+//   - profil dosen                         → mahasiswa bimbingan
+func (a *Actor) ReadScope() model.AccessScope {
+	if a.Can(model.PermissionAchievementReadAll) {
+		return model.AccessScope{Kind: model.ScopeAll}
+	}
+
+	if student := a.Student(); student != nil {
+		return model.AccessScope{Kind: model.ScopeOwn, StudentID: student.ID}
+	}
+
+	if lecturer := a.Lecturer(); lecturer != nil {
+		if a.Can(model.PermissionAchievementReadDepartment) {
+			return model.AccessScope{
+				Kind:       model.ScopeDepartment,
+				AdvisorID:  lecturer.ID,
+				Department: lecturer.Department,
+			}
+		}
+		return model.AccessScope{Kind: model.ScopeAdvisees, AdvisorID: lecturer.ID}
+	}
+
+	return model.AccessScope{Kind: model.ScopeNone}
+}
+
+// CanReadStudent - Cek apakah data mahasiswa (profil, prestasi, laporan) boleh dibaca
+func (a *Actor) CanReadStudent(student *model.Student) bool {
+	if student == nil {
+		return false
+	}
+
+	scope := a.ReadScope()
+	switch scope.Kind {
+	case model.ScopeAll:
+		return true
+	case model.ScopeOwn:
+		return student.ID == scope.StudentID
+	case model.ScopeAdvisees:
+		return a.actsForAdvisor(student, scope.AdvisorID)
+	case model.ScopeDepartment:
+		return student.ProgramStudy == scope.Department || a.actsForAdvisor(student, scope.AdvisorID)
+	default:
+		return false
+	}
+}
+
+// CanSeeAchievement - Prestasi yang di-soft delete hanya terlihat oleh admin (user:manage)
+// sampai di-restore atau di-purge
+func (a *Actor) CanSeeAchievement(ref *model.AchievementReference) bool {
+	return ref.Status != model.AchievementStatusDeleted || a.Can(model.PermissionUserManage)
+}
+
+// CanReadAchievement - Cek apakah prestasi boleh dibaca
+func (a *Actor) CanReadAchievement(ref *model.AchievementReference) bool {
+	if !a.CanSeeAchievement(ref) {
+		return false
+	}
+	if a.Can(model.PermissionAchievementReadAll) {
+		return true
+	}
+
+	student, _ := a.policy.studentRepo.FindByID(ref.StudentID)
+	return a.CanReadStudent(student)
+}
+
+// CanViewAdvisees - Cek apakah daftar mahasiswa bimbingan seorang dosen boleh dibaca
+func (a *Actor) CanViewAdvisees(lecturer *model.Lecturer) bool {
+	if a.Can(model.PermissionAchievementReadAll) {
+		return true
+	}
+
+	own := a.Lecturer()
+	if own == nil {
+		return false
+	}
+
+	return own.ID == lecturer.ID ||
+		(a.Can(model.PermissionAchievementReadDepartment) && own.Department == lecturer.Department)
+}
+
+//
+// ==================== WRITE ======================
+//
+
+// IsOwner - Cek apakah actor adalah mahasiswa pemilik prestasi
+// (syarat untuk update, delete, submit, dan upload attachment)
+func (a *Actor) IsOwner(ref *model.AchievementReference) bool {
+	student := a.Student()
+	return student != nil && student.ID == ref.StudentID
+}
+
+// CanVerifyAchievement - Cek apakah actor boleh verify/reject prestasi:
+// butuh achievement:verify dan harus dosen wali dari mahasiswa pemilik
+// atau delegasi aktif dari dosen wali tersebut
+func (a *Actor) CanVerifyAchievement(ref *model.AchievementReference) bool {
+	_, ok := a.VerifierFor(ref)
+	return ok
+}
+
+// VerifierFor - Seperti CanVerifyAchievement, ditambah dosen wali yang diwakili:
+// onBehalfOf nil jika actor sendiri dosen wali, berisi ID dosen wali jika actor delegasi
+func (a *Actor) VerifierFor(ref *model.AchievementReference) (onBehalfOf *string, ok bool) {
+	if !a.Can(model.PermissionAchievementVerify) {
+		return nil, false
+	}
+
+	lecturer := a.Lecturer()
+	if lecturer == nil {
+		return nil, false
+	}
+
+	student, _ := a.policy.studentRepo.FindByID(ref.StudentID)
+	if student == nil || student.AdvisorID == nil {
+		return nil, false
+	}
+	if isAdvisor(student, lecturer.ID) {
+		return nil, true
+	}
+	if a.isDelegateOf(*student.AdvisorID) {
+		advisorID := *student.AdvisorID
+		return &advisorID, true
+	}
+	return nil, false
+}
+
+// ReviewsDepartmentOf - Cek apakah actor reviewer departemen mahasiswa pemilik prestasi:
+// dosen dengan achievement:read_department yang departemennya = program studi mahasiswa
+// (approver tahap "department" di rantai persetujuan, mis. Kaprodi)
+func (a *Actor) ReviewsDepartmentOf(ref *model.AchievementReference) bool {
+	if !a.Can(model.PermissionAchievementReadDepartment) {
+		return false
+	}
+
+	lecturer := a.Lecturer()
+	if lecturer == nil || lecturer.Department == "" {
+		return false
+	}
+
+	student, _ := a.policy.studentRepo.FindByID(ref.StudentID)
+	return student != nil && student.ProgramStudy == lecturer.Department
+}
+
+// CanManageDelegations - Cek apakah actor boleh melihat / mengatur delegasi seorang dosen:
+// dosen itu sendiri (dengan achievement:verify) atau admin (user:manage)
+func (a *Actor) CanManageDelegations(lecturer *model.Lecturer) bool {
+	if a.Can(model.PermissionUserManage) {
+		return true
+	}
+
+	own := a.Lecturer()
+	return own != nil && own.ID == lecturer.ID && a.Can(model.PermissionAchievementVerify)
+}
+
+// actsForAdvisor - Actor (dosen lecturerID) adalah dosen wali mahasiswa, atau
+// delegasi aktif dari dosen walinya
+func (a *Actor) actsForAdvisor(student *model.Student, lecturerID string) bool {
+	if isAdvisor(student, lecturerID) {
+		return true
+	}
+	return student.AdvisorID != nil && a.isDelegateOf(*student.AdvisorID)
+}
+
+// isDelegateOf - Actor (dosen) memegang delegasi aktif dari advisorID.
+// Hasil di-cache per actor karena dicek berulang dalam satu request (mis. batch).
+func (a *Actor) isDelegateOf(advisorID string) bool {
+	lecturer := a.Lecturer()
+	if lecturer == nil || lecturer.ID == advisorID {
+		return false
+	}
+
+	if active, ok := a.delegations[advisorID]; ok {
+		return active
+	}
+	active, err := a.policy.lecturerRepo.IsActiveDelegate(advisorID, lecturer.ID)
+	active = err == nil && active
+
+	if a.delegations == nil {
+		a.delegations = map[string]bool{}
+	}
+	a.delegations[advisorID] = active
+	return active
+}
+
+func isAdvisor(student *model.Student, lecturerID string) bool {
+	return student.AdvisorID != nil && *student.AdvisorID == lecturerID
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"UASBE/app/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AchievementCommentRepository - Komentar (thread) pada prestasi
+type AchievementCommentRepository interface {
+	Create(comment *model.AchievementComment, notifications []model.Notification) error
+	FindByID(id string) (*model.AchievementComment, error)
+	GetByAchievementRefID(refID string) ([]model.AchievementComment, error)
+}
+
+type achievementCommentRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementCommentRepository(db *sql.DB) AchievementCommentRepository {
+	return &achievementCommentRepository{db}
+}
+
+// Create - Simpan komentar beserta mention-nya dalam satu transaksi
+func (r *achievementCommentRepository) Create(comment *model.AchievementComment, notifications []model.Notification) error {
+	comment.ID = uuid.New().String()
+	comment.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO achievement_comments (id, achievement_ref_id, parent_id, author_id, author_role, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = tx.Exec(query,
+		comment.ID,
+		comment.AchievementRefID,
+		comment.ParentID,
+		comment.AuthorID,
+		comment.AuthorRole,
+		comment.Body,
+		comment.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, mention := range comment.Mentions {
+		_, err := tx.Exec(
+			`INSERT INTO achievement_comment_mentions (comment_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			comment.ID, mention.UserID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := insertNotifications(tx, notifications); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindByID - Komentar tanpa mention (untuk validasi parent)
+func (r *achievementCommentRepository) FindByID(id string) (*model.AchievementComment, error) {
+	comment := &model.AchievementComment{}
+	query := `
+		SELECT c.id, c.achievement_ref_id, c.parent_id, c.author_id, c.author_role, COALESCE(u.full_name, ''), c.body, c.created_at
+		FROM achievement_comments c
+		LEFT JOIN users u ON u.id = c.author_id
+		WHERE c.id = $1
+	`
+	err := r.db.QueryRow(query, id).Scan(
+		&comment.ID,
+		&comment.AchievementRefID,
+		&comment.ParentID,
+		&comment.AuthorID,
+		&comment.AuthorRole,
+		&comment.AuthorName,
+		&comment.Body,
+		&comment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetByAchievementRefID - Semua komentar prestasi (datar, urut dari yang paling lama)
+// beserta mention-nya. Susunan thread dibentuk di service.
+func (r *achievementCommentRepository) GetByAchievementRefID(refID string) ([]model.AchievementComment, error) {
+	query := `
+		SELECT c.id, c.achievement_ref_id, c.parent_id, c.author_id, c.author_role, COALESCE(u.full_name, ''), c.body, c.created_at
+		FROM achievement_comments c
+		LEFT JOIN users u ON u.id = c.author_id
+		WHERE c.achievement_ref_id = $1
+		ORDER BY c.created_at ASC, c.id ASC
+	`
+	rows, err := r.db.Query(query, refID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.AchievementComment{}
+	index := map[string]int{}
+	for rows.Next() {
+		var comment model.AchievementComment
+		err := rows.Scan(
+			&comment.ID,
+			&comment.AchievementRefID,
+			&comment.ParentID,
+			&comment.AuthorID,
+			&comment.AuthorRole,
+			&comment.AuthorName,
+			&comment.Body,
+			&comment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		comment.Mentions = []model.CommentMention{}
+		index[comment.ID] = len(comments)
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	mentionQuery := `
+		SELECT m.comment_id, m.user_id, u.username
+		FROM achievement_comment_mentions m
+		JOIN achievement_comments c ON c.id = m.comment_id
+		JOIN users u ON u.id = m.user_id
+		WHERE c.achievement_ref_id = $1
+		ORDER BY u.username
+	`
+	mentionRows, err := r.db.Query(mentionQuery, refID)
+	if err != nil {
+		return nil, err
+	}
+	defer mentionRows.Close()
+
+	for mentionRows.Next() {
+		var commentID string
+		var mention model.CommentMention
+		if err := mentionRows.Scan(&commentID, &mention.UserID, &mention.Username); err != nil {
+			return nil, err
+		}
+		if i, ok := index[commentID]; ok {
+			comments[i].Mentions = append(comments[i].Mentions, mention)
+		}
+	}
+	return comments, mentionRows.Err()
+}
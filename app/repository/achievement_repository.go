@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"UASBE/app/model"
 	"time"
 
@@ -13,6 +16,12 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrStatusChanged - Status prestasi sudah diubah request lain sejak dibaca
+var ErrStatusChanged = errors.New("achievement status changed concurrently")
+
+// ErrAchievementNotFound - Dokumen prestasi tidak ada di MongoDB
+var ErrAchievementNotFound = errors.New("achievement document not found")
+
 type AchievementRepository interface {
 	// PostgreSQL - Achievement References
 	CreateReference(ref *model.AchievementReference) error
@@ -21,16 +30,31 @@ type AchievementRepository interface {
 	GetReferenceByMongoID(mongoID string) (*model.AchievementReference, error)
 	GetReferencesByStudentID(studentID string, status string, limit, offset int) ([]model.AchievementReference, error)
 	CountReferencesByStudentID(studentID string, status string) (int, error)
-	GetReferencesByAdvisorID(advisorID string, status string, limit, offset int) ([]model.AchievementReference, error)
-	CountReferencesByAdvisorID(advisorID string, status string) (int, error)
-	GetAllReferences(status string, limit, offset int) ([]model.AchievementReference, error)
-	CountAllReferences(status string) (int, error)
+	GetReferencesByScope(scope model.AccessScope, status string, limit, offset int) ([]model.AchievementReference, error)
+	CountReferencesByScope(scope model.AccessScope, status string) (int, error)
+
+	// PostgreSQL - Prestasi terhapus (restore oleh admin, purge setelah masa retensi)
+	GetDeletedReferences(limit, offset int) ([]model.AchievementReference, error)
+	CountDeletedReferences() (int, error)
+	GetPurgeableReferences(deletedBefore time.Time) ([]model.AchievementReference, error)
+	PurgeReference(id string, cleanup func() error) error
+
+	// PostgreSQL - Status History
+	TransitionReference(ref *model.AchievementReference, entry *model.AchievementStatusHistory) error
+	TransitionReferences(refs []*model.AchievementReference, entries []*model.AchievementStatusHistory) error
+	GetStatusHistory(refID string) ([]model.AchievementStatusHistory, error)
+
+	// PostgreSQL - Submissions (snapshot isi prestasi setiap kali diajukan)
+	SubmitReference(ref *model.AchievementReference, entry *model.AchievementStatusHistory, snapshot *model.AchievementSnapshot) error
+	GetSubmissions(refID string) ([]model.AchievementSubmission, error)
 
 	// MongoDB - Achievements
 	CreateAchievement(achievement *model.Achievement) (string, error)
 	UpdateAchievement(id string, achievement *model.Achievement) error
 	GetAchievementByID(id string) (*model.Achievement, error)
 	DeleteAchievement(id string) error
+	RestoreAchievement(id string) error
+	PurgeAchievement(id string) error
 	AddAttachment(achievementID string, attachment model.Attachment) error
 }
 
@@ -50,18 +74,25 @@ func NewAchievementRepository(pgDB *sql.DB, mongoDB *mongo.Database) Achievement
 // ==================== POSTGRESQL METHODS (REFERENCES) ======================
 //
 
-// CreateReference - Insert reference baru
+// CreateReference - Insert reference baru beserta baris pertama riwayat status
+// (actor = mahasiswa pemilik; students.id sama dengan users.id)
 func (r *achievementRepository) CreateReference(ref *model.AchievementReference) error {
 	ref.ID = uuid.New().String()
 	ref.CreatedAt = time.Now()
 	ref.UpdatedAt = time.Now()
 
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO achievement_references 
-		(id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		(id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, submission_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
-	_, err := r.pgDB.Exec(query,
+	_, err = tx.Exec(query,
 		ref.ID,
 		ref.StudentID,
 		ref.MongoAchievementID,
@@ -70,10 +101,26 @@ func (r *achievementRepository) CreateReference(ref *model.AchievementReference)
 		ref.VerifiedAt,
 		ref.VerifiedBy,
 		ref.RejectionNote,
+		ref.SubmissionCount,
 		ref.CreatedAt,
 		ref.UpdatedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	historyQuery := `
+		INSERT INTO achievement_status_history (id, achievement_ref_id, from_status, to_status, actor_id, actor_role, created_at)
+		SELECT $1, $2, NULL, $3, u.id, COALESCE(ro.name, ''), $4
+		FROM users u
+		LEFT JOIN roles ro ON ro.id = u.role_id
+		WHERE u.id = $5
+	`
+	if _, err := tx.Exec(historyQuery, uuid.New().String(), ref.ID, ref.Status, ref.CreatedAt, ref.StudentID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // UpdateReference - Update reference
@@ -82,8 +129,9 @@ func (r *achievementRepository) UpdateReference(ref *model.AchievementReference)
 
 	query := `
 		UPDATE achievement_references
-		SET status = $1, submitted_at = $2, verified_at = $3, verified_by = $4, rejection_note = $5, updated_at = $6
-		WHERE id = $7
+		SET status = $1, submitted_at = $2, verified_at = $3, verified_by = $4, rejection_note = $5, submission_count = $6,
+			approval_chain = $7, approval_stage = $8, deleted_at = $9, updated_at = $10
+		WHERE id = $11
 	`
 	_, err := r.pgDB.Exec(query,
 		ref.Status,
@@ -91,17 +139,234 @@ func (r *achievementRepository) UpdateReference(ref *model.AchievementReference)
 		ref.VerifiedAt,
 		ref.VerifiedBy,
 		ref.RejectionNote,
+		ref.SubmissionCount,
+		ref.ApprovalChain,
+		ref.ApprovalStage,
+		ref.DeletedAt,
 		ref.UpdatedAt,
 		ref.ID,
 	)
 	return err
 }
 
+// TransitionReference - Update reference dan catat transisi status dalam satu transaksi.
+// entry.FromStatus wajib diisi dengan status yang dibaca pemanggil; jika status di
+// database sudah berbeda (request lain lebih dulu) return ErrStatusChanged.
+func (r *achievementRepository) TransitionReference(ref *model.AchievementReference, entry *model.AchievementStatusHistory) error {
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.transition(tx, ref, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// TransitionReferences - Beberapa transisi dalam satu transaksi (batch atomic).
+// refs[i] dipasangkan dengan entries[i]; satu saja ErrStatusChanged membatalkan semuanya.
+func (r *achievementRepository) TransitionReferences(refs []*model.AchievementReference, entries []*model.AchievementStatusHistory) error {
+	if len(refs) != len(entries) {
+		return errors.New("each reference requires a history entry")
+	}
+
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range refs {
+		if err := r.transition(tx, refs[i], entries[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SubmitReference - Seperti TransitionReference, sekaligus menyimpan snapshot isi
+// prestasi sebagai pengajuan ke-ref.SubmissionCount
+func (r *achievementRepository) SubmitReference(ref *model.AchievementReference, entry *model.AchievementStatusHistory, snapshot *model.AchievementSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.transition(tx, ref, entry); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO achievement_submissions (id, achievement_ref_id, submission_number, snapshot, submitted_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.Exec(query, uuid.New().String(), ref.ID, ref.SubmissionCount, data, entry.ActorID, entry.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Helper: transition - Conditional update status + insert riwayat di dalam tx
+func (r *achievementRepository) transition(tx *sql.Tx, ref *model.AchievementReference, entry *model.AchievementStatusHistory) error {
+	if entry.FromStatus == nil {
+		return errors.New("transition requires from status")
+	}
+
+	ref.UpdatedAt = time.Now()
+	entry.ID = uuid.New().String()
+	entry.AchievementRefID = ref.ID
+	entry.ToStatus = ref.Status
+	entry.CreatedAt = ref.UpdatedAt
+
+	// Keputusan tahap persetujuan hanya berlaku jika tahap di database masih sama
+	// (dua approver tahap yang sama tidak bisa sama-sama memajukan rantai; withdraw
+	// gagal jika tahap pertama disetujui bersamaan)
+	query := `
+		UPDATE achievement_references
+		SET status = $1, submitted_at = $2, verified_at = $3, verified_by = $4, rejection_note = $5, submission_count = $6,
+			approval_chain = $7, approval_stage = $8, deleted_at = $9, updated_at = $10
+		WHERE id = $11 AND status = $12 AND ($13::int IS NULL OR approval_stage = $13)
+	`
+	result, err := tx.Exec(query,
+		ref.Status,
+		ref.SubmittedAt,
+		ref.VerifiedAt,
+		ref.VerifiedBy,
+		ref.RejectionNote,
+		ref.SubmissionCount,
+		ref.ApprovalChain,
+		ref.ApprovalStage,
+		ref.DeletedAt,
+		ref.UpdatedAt,
+		ref.ID,
+		*entry.FromStatus,
+		entry.ApprovalStage,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrStatusChanged
+	}
+
+	historyQuery := `
+		INSERT INTO achievement_status_history
+		(id, achievement_ref_id, from_status, to_status, actor_id, actor_role, on_behalf_of, approval_stage, approval_stage_name, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err = tx.Exec(historyQuery,
+		entry.ID,
+		entry.AchievementRefID,
+		entry.FromStatus,
+		entry.ToStatus,
+		entry.ActorID,
+		entry.ActorRole,
+		entry.OnBehalfOf,
+		entry.ApprovalStage,
+		entry.StageName,
+		entry.Note,
+		entry.CreatedAt,
+	)
+	return err
+}
+
+// GetSubmissions - Semua snapshot pengajuan prestasi, urut dari pengajuan pertama
+func (r *achievementRepository) GetSubmissions(refID string) ([]model.AchievementSubmission, error) {
+	query := `
+		SELECT id, achievement_ref_id, submission_number, snapshot, submitted_by, created_at
+		FROM achievement_submissions
+		WHERE achievement_ref_id = $1
+		ORDER BY submission_number ASC
+	`
+	rows, err := r.pgDB.Query(query, refID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var submissions []model.AchievementSubmission
+	for rows.Next() {
+		var submission model.AchievementSubmission
+		var data []byte
+		err := rows.Scan(
+			&submission.ID,
+			&submission.AchievementRefID,
+			&submission.SubmissionNumber,
+			&data,
+			&submission.SubmittedBy,
+			&submission.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &submission.Snapshot); err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, submission)
+	}
+	return submissions, rows.Err()
+}
+
+// GetStatusHistory - Riwayat status prestasi, urut dari yang paling lama
+func (r *achievementRepository) GetStatusHistory(refID string) ([]model.AchievementStatusHistory, error) {
+	query := `
+		SELECT h.id, h.achievement_ref_id, h.from_status, h.to_status, h.actor_id, h.actor_role,
+			COALESCE(u.full_name, ''), h.on_behalf_of, COALESCE(ob.full_name, ''), h.approval_stage, h.approval_stage_name,
+			h.note, h.created_at
+		FROM achievement_status_history h
+		LEFT JOIN users u ON u.id = h.actor_id
+		LEFT JOIN users ob ON ob.id = h.on_behalf_of
+		WHERE h.achievement_ref_id = $1
+		ORDER BY h.created_at ASC, h.id ASC
+	`
+	rows, err := r.pgDB.Query(query, refID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []model.AchievementStatusHistory
+	for rows.Next() {
+		var entry model.AchievementStatusHistory
+		err := rows.Scan(
+			&entry.ID,
+			&entry.AchievementRefID,
+			&entry.FromStatus,
+			&entry.ToStatus,
+			&entry.ActorID,
+			&entry.ActorRole,
+			&entry.ActorName,
+			&entry.OnBehalfOf,
+			&entry.OnBehalfOfName,
+			&entry.ApprovalStage,
+			&entry.StageName,
+			&entry.Note,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+	return history, rows.Err()
+}
+
 // GetReferenceByID - Get reference by ID
 func (r *achievementRepository) GetReferenceByID(id string) (*model.AchievementReference, error) {
 	ref := &model.AchievementReference{}
 	query := `
-		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, submission_count, approval_chain, approval_stage, deleted_at, created_at, updated_at
 		FROM achievement_references
 		WHERE id = $1
 	`
@@ -114,6 +379,10 @@ func (r *achievementRepository) GetReferenceByID(id string) (*model.AchievementR
 		&ref.VerifiedAt,
 		&ref.VerifiedBy,
 		&ref.RejectionNote,
+		&ref.SubmissionCount,
+		&ref.ApprovalChain,
+		&ref.ApprovalStage,
+		&ref.DeletedAt,
 		&ref.CreatedAt,
 		&ref.UpdatedAt,
 	)
@@ -127,7 +396,7 @@ func (r *achievementRepository) GetReferenceByID(id string) (*model.AchievementR
 func (r *achievementRepository) GetReferenceByMongoID(mongoID string) (*model.AchievementReference, error) {
 	ref := &model.AchievementReference{}
 	query := `
-		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, submission_count, approval_chain, approval_stage, deleted_at, created_at, updated_at
 		FROM achievement_references
 		WHERE mongo_achievement_id = $1
 	`
@@ -140,6 +409,10 @@ func (r *achievementRepository) GetReferenceByMongoID(mongoID string) (*model.Ac
 		&ref.VerifiedAt,
 		&ref.VerifiedBy,
 		&ref.RejectionNote,
+		&ref.SubmissionCount,
+		&ref.ApprovalChain,
+		&ref.ApprovalStage,
+		&ref.DeletedAt,
 		&ref.CreatedAt,
 		&ref.UpdatedAt,
 	)
@@ -157,7 +430,7 @@ func (r *achievementRepository) GetReferencesByStudentID(studentID string, statu
 
 	if status != "" {
 		query = `
-			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, submission_count, approval_chain, approval_stage, deleted_at, created_at, updated_at
 			FROM achievement_references
 			WHERE student_id = $1 AND status = $2 AND status != 'deleted'
 			ORDER BY created_at DESC
@@ -166,7 +439,7 @@ func (r *achievementRepository) GetReferencesByStudentID(studentID string, statu
 		rows, err = r.pgDB.Query(query, studentID, status, limit, offset)
 	} else {
 		query = `
-			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
+			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, submission_count, approval_chain, approval_stage, deleted_at, created_at, updated_at
 			FROM achievement_references
 			WHERE student_id = $1 AND status != 'deleted'
 			ORDER BY created_at DESC
@@ -199,34 +472,39 @@ func (r *achievementRepository) CountReferencesByStudentID(studentID string, sta
 	return count, err
 }
 
-// GetReferencesByAdvisorID - Get achievements dari mahasiswa bimbingan (untuk Dosen Wali)
-func (r *achievementRepository) GetReferencesByAdvisorID(advisorID string, status string, limit, offset int) ([]model.AchievementReference, error) {
-	var query string
-	var rows *sql.Rows
-	var err error
+// GetReferencesByScope - Get references sesuai jangkauan akses dari app/policy
+func (r *achievementRepository) GetReferencesByScope(scope model.AccessScope, status string, limit, offset int) ([]model.AchievementReference, error) {
+	where, args := r.scopeWhere(scope, status)
+	args = append(args, limit, offset)
 
-	if status != "" {
-		query = `
-			SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE s.advisor_id = $1 AND ar.status = $2 AND ar.status != 'deleted'
-			ORDER BY ar.created_at DESC
-			LIMIT $3 OFFSET $4
-		`
-		rows, err = r.pgDB.Query(query, advisorID, status, limit, offset)
-	} else {
-		query = `
-			SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE s.advisor_id = $1 AND ar.status != 'deleted'
-			ORDER BY ar.created_at DESC
-			LIMIT $2 OFFSET $3
-		`
-		rows, err = r.pgDB.Query(query, advisorID, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.submission_count, ar.approval_chain, ar.approval_stage, ar.deleted_at, ar.created_at, ar.updated_at
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		WHERE %s
+		ORDER BY ar.created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.pgDB.Query(query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
+
+	return r.scanReferences(rows)
+}
 
+// GetDeletedReferences - Reference berstatus deleted, terbaru dihapus dulu
+func (r *achievementRepository) GetDeletedReferences(limit, offset int) ([]model.AchievementReference, error) {
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, submission_count, approval_chain, approval_stage, deleted_at, created_at, updated_at
+		FROM achievement_references
+		WHERE status = 'deleted'
+		ORDER BY deleted_at DESC NULLS LAST, id
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := r.pgDB.Query(query, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -235,80 +513,85 @@ func (r *achievementRepository) GetReferencesByAdvisorID(advisorID string, statu
 	return r.scanReferences(rows)
 }
 
-// CountReferencesByAdvisorID - Count achievements dari mahasiswa bimbingan
-func (r *achievementRepository) CountReferencesByAdvisorID(advisorID string, status string) (int, error) {
+// CountDeletedReferences - Jumlah reference berstatus deleted
+func (r *achievementRepository) CountDeletedReferences() (int, error) {
 	var count int
-	var query string
+	err := r.pgDB.QueryRow(`SELECT COUNT(*) FROM achievement_references WHERE status = 'deleted'`).Scan(&count)
+	return count, err
+}
 
-	if status != "" {
-		query = `
-			SELECT COUNT(*)
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE s.advisor_id = $1 AND ar.status = $2 AND ar.status != 'deleted'
-		`
-		err := r.pgDB.QueryRow(query, advisorID, status).Scan(&count)
-		return count, err
+// GetPurgeableReferences - Reference deleted dengan deleted_at <= deletedBefore, paling lama dulu
+func (r *achievementRepository) GetPurgeableReferences(deletedBefore time.Time) ([]model.AchievementReference, error) {
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, submission_count, approval_chain, approval_stage, deleted_at, created_at, updated_at
+		FROM achievement_references
+		WHERE status = 'deleted' AND deleted_at <= $1
+		ORDER BY deleted_at ASC
+	`
+	rows, err := r.pgDB.Query(query, deletedBefore)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	query = `
-		SELECT COUNT(*)
-		FROM achievement_references ar
-		JOIN students s ON ar.student_id = s.id
-		WHERE s.advisor_id = $1 AND ar.status != 'deleted'
-	`
-	err := r.pgDB.QueryRow(query, advisorID).Scan(&count)
-	return count, err
+	return r.scanReferences(rows)
 }
 
-// GetAllReferences - Get all references (untuk Admin)
-func (r *achievementRepository) GetAllReferences(status string, limit, offset int) ([]model.AchievementReference, error) {
-	var query string
-	var rows *sql.Rows
-	var err error
-
-	if status != "" {
-		query = `
-			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
-			FROM achievement_references
-			WHERE status = $1 AND status != 'deleted'
-			ORDER BY created_at DESC
-			LIMIT $2 OFFSET $3
-		`
-		rows, err = r.pgDB.Query(query, status, limit, offset)
-	} else {
-		query = `
-			SELECT id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at
-			FROM achievement_references
-			WHERE status != 'deleted'
-			ORDER BY created_at DESC
-			LIMIT $1 OFFSET $2
-		`
-		rows, err = r.pgDB.Query(query, limit, offset)
+// PurgeReference - Hapus permanen reference yang masih berstatus deleted
+// (riwayat, snapshot pengajuan dan komentar ikut terhapus lewat ON DELETE CASCADE).
+// Row dihapus lebih dulu di dalam transaksi, lalu cleanup (file lampiran, dokumen
+// MongoDB) dijalankan sebelum commit: restore yang bersamaan menunggu row ini lalu
+// gagal, dan cleanup yang gagal membatalkan penghapusan.
+// ErrStatusChanged jika prestasi sudah di-restore (cleanup tidak dijalankan).
+func (r *achievementRepository) PurgeReference(id string, cleanup func() error) error {
+	tx, err := r.pgDB.Begin()
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
+	var deletedID string
+	err = tx.QueryRow(`DELETE FROM achievement_references WHERE id = $1 AND status = 'deleted' RETURNING id`, id).Scan(&deletedID)
+	if err == sql.ErrNoRows {
+		return ErrStatusChanged
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
 
-	return r.scanReferences(rows)
+	if err := cleanup(); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// CountAllReferences - Count all references
-func (r *achievementRepository) CountAllReferences(status string) (int, error) {
+// CountReferencesByScope - Count references sesuai jangkauan akses
+func (r *achievementRepository) CountReferencesByScope(scope model.AccessScope, status string) (int, error) {
+	where, args := r.scopeWhere(scope, status)
+
+	query := `
+		SELECT COUNT(*)
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		WHERE ` + where
+
 	var count int
-	var query string
+	err := r.pgDB.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// Helper: scopeWhere - Kondisi WHERE untuk status + scope
+func (r *achievementRepository) scopeWhere(scope model.AccessScope, status string) (string, []interface{}) {
+	where := "ar.status != 'deleted'"
+	var args []interface{}
 
 	if status != "" {
-		query = `SELECT COUNT(*) FROM achievement_references WHERE status = $1 AND status != 'deleted'`
-		err := r.pgDB.QueryRow(query, status).Scan(&count)
-		return count, err
+		args = append(args, status)
+		where += fmt.Sprintf(" AND ar.status = $%d", len(args))
 	}
 
-	query = `SELECT COUNT(*) FROM achievement_references WHERE status != 'deleted'`
-	err := r.pgDB.QueryRow(query).Scan(&count)
-	return count, err
+	filter, args := scopeFilter(scope, args)
+	return where + filter, args
 }
 
 // Helper: scanReferences
@@ -325,6 +608,10 @@ func (r *achievementRepository) scanReferences(rows *sql.Rows) ([]model.Achievem
 			&ref.VerifiedAt,
 			&ref.VerifiedBy,
 			&ref.RejectionNote,
+			&ref.SubmissionCount,
+			&ref.ApprovalChain,
+			&ref.ApprovalStage,
+			&ref.DeletedAt,
 			&ref.CreatedAt,
 			&ref.UpdatedAt,
 		)
@@ -393,6 +680,9 @@ func (r *achievementRepository) GetAchievementByID(id string) (*model.Achievemen
 	var achievement model.Achievement
 	filter := bson.M{"_id": objectID}
 	err = collection.FindOne(ctx, filter).Decode(&achievement)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrAchievementNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -400,8 +690,40 @@ func (r *achievementRepository) GetAchievementByID(id string) (*model.Achievemen
 	return &achievement, nil
 }
 
-// DeleteAchievement - Soft delete (optional, bisa juga hard delete)
+// DeleteAchievement - Soft delete: isi deletedAt, dokumen tetap ada sampai di-purge
 func (r *achievementRepository) DeleteAchievement(id string) error {
+	return r.setDeletedAt(id, bson.M{"$set": bson.M{"deletedAt": time.Now()}})
+}
+
+// RestoreAchievement - Batalkan soft delete
+func (r *achievementRepository) RestoreAchievement(id string) error {
+	return r.setDeletedAt(id, bson.M{"$unset": bson.M{"deletedAt": ""}})
+}
+
+// Helper: setDeletedAt - Update penanda soft delete; ErrAchievementNotFound jika dokumen tidak ada
+func (r *achievementRepository) setDeletedAt(id string, update bson.M) error {
+	collection := r.mongoDB.Collection("achievements")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrAchievementNotFound
+	}
+	return nil
+}
+
+// PurgeAchievement - Hapus permanen dokumen prestasi (rollback create, purge job).
+// Dokumen yang sudah tidak ada tidak dianggap error.
+func (r *achievementRepository) PurgeAchievement(id string) error {
 	collection := r.mongoDB.Collection("achievements")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"database/sql"
+	"UASBE/app/model"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiTokenTouchInterval - last_used_at hanya di-update jika sudah lebih lama dari ini
+const apiTokenTouchInterval = time.Minute
+
+type APITokenRepository interface {
+	Create(token *model.APIToken) error
+	FindByID(id string) (*model.APIToken, error)
+	FindByHash(hash string) (*model.APIToken, *model.User, error)
+	GetByUserID(userID string) ([]model.APIToken, error)
+	Touch(id string) error
+	Revoke(id string) error
+}
+
+type apiTokenRepository struct {
+	db *sql.DB
+}
+
+func NewAPITokenRepository(db *sql.DB) APITokenRepository {
+	return &apiTokenRepository{db}
+}
+
+// Scopes disimpan sebagai teks dipisah koma (nama permission tidak mengandung koma)
+func joinScopes(scopes []string) string {
+	return strings.Join(scopes, ",")
+}
+
+func splitScopes(raw string) []string {
+	if raw == "" {
+		return []string{}
+	}
+	return strings.Split(raw, ",")
+}
+
+// Create - Simpan token baru (hanya hash)
+func (r *apiTokenRepository) Create(token *model.APIToken) error {
+	token.ID = uuid.New().String()
+	token.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO api_tokens (id, user_id, name, token_hash, token_prefix, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(query,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.TokenHash,
+		token.TokenPrefix,
+		joinScopes(token.Scopes),
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+const apiTokenColumns = `t.id, t.user_id, t.name, t.token_hash, t.token_prefix, t.scopes,
+	t.expires_at, t.last_used_at, t.revoked_at, t.created_at`
+
+func scanAPIToken(scan func(dest ...interface{}) error, extra ...interface{}) (*model.APIToken, error) {
+	token := &model.APIToken{}
+	var scopes string
+
+	dest := []interface{}{
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.TokenHash,
+		&token.TokenPrefix,
+		&scopes,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	}
+	if err := scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	token.Scopes = splitScopes(scopes)
+	return token, nil
+}
+
+// FindByID - Cari token berdasarkan ID (termasuk yang sudah di-revoke)
+func (r *apiTokenRepository) FindByID(id string) (*model.APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_tokens t WHERE t.id = $1`
+	return scanAPIToken(r.db.QueryRow(query, id).Scan)
+}
+
+// FindByHash - Token beserta pemiliknya (role, status akun) untuk AuthRequired
+func (r *apiTokenRepository) FindByHash(hash string) (*model.APIToken, *model.User, error) {
+	query := `
+		SELECT ` + apiTokenColumns + `,
+			u.username, u.is_active, u.deleted_at, COALESCE(u.role_id::text, ''), COALESCE(ro.name, '')
+		FROM api_tokens t
+		JOIN users u ON u.id = t.user_id
+		LEFT JOIN roles ro ON ro.id = u.role_id
+		WHERE t.token_hash = $1
+	`
+	owner := &model.User{}
+	token, err := scanAPIToken(r.db.QueryRow(query, hash).Scan,
+		&owner.Username,
+		&owner.IsActive,
+		&owner.DeletedAt,
+		&owner.RoleID,
+		&owner.Role,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owner.ID = token.UserID
+	return token, owner, nil
+}
+
+// GetByUserID - Token milik user yang belum di-revoke (termasuk yang sudah kadaluarsa)
+func (r *apiTokenRepository) GetByUserID(userID string) ([]model.APIToken, error) {
+	query := `
+		SELECT ` + apiTokenColumns + `
+		FROM api_tokens t
+		WHERE t.user_id = $1 AND t.revoked_at IS NULL
+		ORDER BY t.created_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []model.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// Touch - Catat pemakaian token; dibatasi sekali per apiTokenTouchInterval
+// supaya request beruntun dari script tidak menulis ke database terus-menerus
+func (r *apiTokenRepository) Touch(id string) error {
+	now := time.Now()
+	query := `
+		UPDATE api_tokens
+		SET last_used_at = $1
+		WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3)
+	`
+	_, err := r.db.Exec(query, now, id, now.Add(-apiTokenTouchInterval))
+	return err
+}
+
+// Revoke - Token langsung tidak berlaku
+func (r *apiTokenRepository) Revoke(id string) error {
+	query := `UPDATE api_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+	_, err := r.db.Exec(query, time.Now(), id)
+	return err
+}
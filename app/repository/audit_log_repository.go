@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"database/sql"
+	"UASBE/app/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLogRepository - Jejak audit (append-only) di tabel audit_logs
+type AuditLogRepository interface {
+	Create(entry *model.AuditLog) error
+}
+
+type auditLogRepository struct {
+	db *sql.DB
+}
+
+func NewAuditLogRepository(db *sql.DB) AuditLogRepository {
+	return &auditLogRepository{db}
+}
+
+// Create - Simpan satu entri audit
+func (r *auditLogRepository) Create(entry *model.AuditLog) error {
+	entry.ID = uuid.New().String()
+	entry.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO audit_logs (id, action, actor_id, subject_id, method, path, status_code, ip_address, detail, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.db.Exec(query,
+		entry.ID,
+		entry.Action,
+		entry.ActorID,
+		entry.SubjectID,
+		entry.Method,
+		entry.Path,
+		entry.StatusCode,
+		entry.IPAddress,
+		entry.Detail,
+		entry.CreatedAt,
+	)
+	return err
+}
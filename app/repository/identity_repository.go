@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"UASBE/app/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdentityRepository - Link akun direktori (OIDC/LDAP) ke user lokal di tabel user_identities
+type IdentityRepository interface {
+	Create(identity *model.UserIdentity) error
+	FindByProviderSubject(provider string, subject string) (*model.UserIdentity, error)
+	TouchLastLogin(id string) error
+}
+
+type identityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) IdentityRepository {
+	return &identityRepository{db}
+}
+
+// Create - Simpan link baru (satu subject hanya boleh terhubung ke satu user)
+func (r *identityRepository) Create(identity *model.UserIdentity) error {
+	identity.ID = uuid.New().String()
+	identity.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.Exec(query,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.CreatedAt,
+	)
+	return err
+}
+
+// FindByProviderSubject - Cari link berdasarkan provider + subject
+func (r *identityRepository) FindByProviderSubject(provider string, subject string) (*model.UserIdentity, error) {
+	identity := &model.UserIdentity{}
+	query := `
+		SELECT id, user_id, provider, subject, last_login_at, created_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+	err := r.db.QueryRow(query, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.LastLoginAt,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// TouchLastLogin - Catat waktu login terakhir lewat provider ini
+func (r *identityRepository) TouchLastLogin(id string) error {
+	_, err := r.db.Exec(`UPDATE user_identities SET last_login_at = $1 WHERE id = $2`, time.Now(), id)
+	return err
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"UASBE/app/model"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type LecturerRepository interface {
@@ -15,6 +17,13 @@ type LecturerRepository interface {
 	Delete(id string) error
 	GetAll(limit, offset int) ([]model.Lecturer, error)
 	CountAll() (int, error)
+
+	// Delegasi hak verifikasi antar dosen
+	CreateDelegation(delegation *model.VerifierDelegation) error
+	FindDelegationByID(id string) (*model.VerifierDelegation, error)
+	GetDelegations(lecturerID string) ([]model.VerifierDelegation, error)
+	RevokeDelegation(id string) error
+	IsActiveDelegate(advisorID, delegateID string) (bool, error)
 }
 
 type lecturerRepository struct {
@@ -146,4 +155,127 @@ func (r *lecturerRepository) CountAll() (int, error) {
 	query := `SELECT COUNT(*) FROM lecturers`
 	err := r.db.QueryRow(query).Scan(&count)
 	return count, err
-}
\ No newline at end of file
+}
+
+//
+// ==================== VERIFIER DELEGATION ======================
+//
+
+// delegationColumns - Kolom delegasi + nama dosen wali & delegasi (alias d, ua, ud)
+const delegationColumns = `
+	d.id, d.advisor_id, COALESCE(ua.full_name, ''), d.delegate_id, COALESCE(ud.full_name, ''),
+	TO_CHAR(d.starts_on, 'YYYY-MM-DD'), TO_CHAR(d.ends_on, 'YYYY-MM-DD'), d.reason, d.created_by, d.revoked_at, d.created_at
+`
+
+// CreateDelegation - Simpan delegasi baru
+func (r *lecturerRepository) CreateDelegation(delegation *model.VerifierDelegation) error {
+	delegation.ID = uuid.New().String()
+	delegation.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO verifier_delegations (id, advisor_id, delegate_id, starts_on, ends_on, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(query,
+		delegation.ID,
+		delegation.AdvisorID,
+		delegation.DelegateID,
+		delegation.StartsOn,
+		delegation.EndsOn,
+		delegation.Reason,
+		delegation.CreatedBy,
+		delegation.CreatedAt,
+	)
+	return err
+}
+
+// FindDelegationByID - Cari delegasi berdasarkan ID
+func (r *lecturerRepository) FindDelegationByID(id string) (*model.VerifierDelegation, error) {
+	query := `
+		SELECT ` + delegationColumns + `
+		FROM verifier_delegations d
+		LEFT JOIN users ua ON ua.id = d.advisor_id
+		LEFT JOIN users ud ON ud.id = d.delegate_id
+		WHERE d.id = $1
+	`
+	delegation, err := scanDelegation(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+	return delegation, nil
+}
+
+// GetDelegations - Delegasi yang diberikan atau diterima dosen, terbaru dulu
+func (r *lecturerRepository) GetDelegations(lecturerID string) ([]model.VerifierDelegation, error) {
+	query := `
+		SELECT ` + delegationColumns + `
+		FROM verifier_delegations d
+		LEFT JOIN users ua ON ua.id = d.advisor_id
+		LEFT JOIN users ud ON ud.id = d.delegate_id
+		WHERE d.advisor_id = $1 OR d.delegate_id = $1
+		ORDER BY d.starts_on DESC, d.created_at DESC
+	`
+	rows, err := r.db.Query(query, lecturerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delegations := []model.VerifierDelegation{}
+	for rows.Next() {
+		delegation, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, *delegation)
+	}
+	return delegations, rows.Err()
+}
+
+// RevokeDelegation - Cabut delegasi (baris tetap disimpan sebagai jejak)
+func (r *lecturerRepository) RevokeDelegation(id string) error {
+	query := `
+		UPDATE verifier_delegations
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL
+	`
+	_, err := r.db.Exec(query, time.Now(), id)
+	return err
+}
+
+// IsActiveDelegate - true jika delegateID memegang delegasi dari advisorID yang
+// belum dicabut dan berlaku hari ini
+func (r *lecturerRepository) IsActiveDelegate(advisorID, delegateID string) (bool, error) {
+	var active bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM verifier_delegations
+			WHERE advisor_id = $1 AND delegate_id = $2 AND revoked_at IS NULL
+				AND CURRENT_DATE BETWEEN starts_on AND ends_on
+		)
+	`
+	err := r.db.QueryRow(query, advisorID, delegateID).Scan(&active)
+	return active, err
+}
+
+// Helper: scanDelegation - Scan satu baris delegationColumns
+func scanDelegation(row interface{ Scan(dest ...interface{}) error }) (*model.VerifierDelegation, error) {
+	delegation := &model.VerifierDelegation{}
+	err := row.Scan(
+		&delegation.ID,
+		&delegation.AdvisorID,
+		&delegation.AdvisorName,
+		&delegation.DelegateID,
+		&delegation.DelegateName,
+		&delegation.StartsOn,
+		&delegation.EndsOn,
+		&delegation.Reason,
+		&delegation.CreatedBy,
+		&delegation.RevokedAt,
+		&delegation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return delegation, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFARepository - State TOTP di tabel users (mfa_secret, mfa_enabled)
+// dan recovery code di tabel mfa_recovery_codes
+type MFARepository interface {
+	SetSecret(userID string, secret string) error
+	Enable(userID string, recoveryCodeHashes []string) error
+	Disable(userID string) error
+	UseRecoveryCode(userID string, codeHash string) (bool, error)
+}
+
+type mfaRepository struct {
+	db *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) MFARepository {
+	return &mfaRepository{db}
+}
+
+// SetSecret - Simpan secret TOTP baru (MFA belum aktif sampai Enable dipanggil)
+func (r *mfaRepository) SetSecret(userID string, secret string) error {
+	query := `
+		UPDATE users
+		SET mfa_secret = $1, mfa_enabled = false, updated_at = $2
+		WHERE id = $3
+	`
+	_, err := r.db.Exec(query, secret, time.Now(), userID)
+	return err
+}
+
+// Enable - Aktifkan MFA dan ganti semua recovery code dalam satu transaksi
+func (r *mfaRepository) Enable(userID string, recoveryCodeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	if _, err := tx.Exec(`UPDATE users SET mfa_enabled = true, updated_at = $1 WHERE id = $2`, now, userID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		query := `
+			INSERT INTO mfa_recovery_codes (id, user_id, code_hash, created_at)
+			VALUES ($1, $2, $3, $4)
+		`
+		if _, err := tx.Exec(query, uuid.New().String(), userID, hash, now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Disable - Matikan MFA, hapus secret dan recovery code
+func (r *mfaRepository) Disable(userID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET mfa_enabled = false, mfa_secret = NULL, updated_at = $1
+		WHERE id = $2
+	`
+	if _, err := tx.Exec(query, time.Now(), userID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode - Tandai recovery code sudah dipakai
+// Return false jika kode tidak ada atau sudah pernah dipakai
+func (r *mfaRepository) UseRecoveryCode(userID string, codeHash string) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`
+	result, err := r.db.Exec(query, time.Now(), userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
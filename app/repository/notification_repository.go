@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"UASBE/app/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationRepository - Notifikasi in-app per user (tabel notifications).
+// Notifikasi SLA verifikasi dibuat oleh VerificationSLARepository di dalam
+// transaksi yang sama dengan penanda pengingat / eskalasi; notifikasi mention oleh
+// AchievementCommentRepository bersama komentarnya.
+type NotificationRepository interface {
+	GetByUserID(userID string, unreadOnly bool) ([]model.Notification, error)
+	MarkRead(id string, userID string) error
+}
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepository{db}
+}
+
+// GetByUserID - Notifikasi milik user, terbaru dulu (maksimal 100)
+func (r *notificationRepository) GetByUserID(userID string, unreadOnly bool) ([]model.Notification, error) {
+	query := `
+		SELECT id, user_id, type, achievement_ref_id, message, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND ($2 = false OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT 100
+	`
+	rows, err := r.db.Query(query, userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []model.Notification{}
+	for rows.Next() {
+		var n model.Notification
+		err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.Type,
+			&n.AchievementRefID,
+			&n.Message,
+			&n.ReadAt,
+			&n.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+// MarkRead - Tandai notifikasi milik user sebagai sudah dibaca.
+// Return sql.ErrNoRows jika notifikasi tidak ada atau milik user lain.
+func (r *notificationRepository) MarkRead(id string, userID string) error {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, $1)
+		WHERE id = $2 AND user_id = $3
+	`
+	result, err := r.db.Exec(query, time.Now(), id, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// insertNotifications - Simpan notifikasi di dalam tx milik repository lain
+func insertNotifications(tx *sql.Tx, notifications []model.Notification) error {
+	for i := range notifications {
+		n := &notifications[i]
+		n.ID = uuid.New().String()
+		n.CreatedAt = time.Now()
+
+		_, err := tx.Exec(`
+			INSERT INTO notifications (id, user_id, type, achievement_ref_id, message, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, n.ID, n.UserID, n.Type, n.AchievementRefID, n.Message, n.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"UASBE/app/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasswordResetRepository interface {
+	Create(token *model.PasswordResetToken) error
+	FindByTokenHash(tokenHash string) (*model.PasswordResetToken, error)
+	MarkUsed(id string) (bool, error)
+	InvalidateByUserID(userID string) error
+}
+
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) PasswordResetRepository {
+	return &passwordResetRepository{db}
+}
+
+// Create - Simpan token reset baru (hanya hash token yang disimpan)
+func (r *passwordResetRepository) Create(token *model.PasswordResetToken) error {
+	token.ID = uuid.New().String()
+	token.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(query,
+		token.ID,
+		token.UserID,
+		token.TokenHash,
+		token.CreatedBy,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+// FindByTokenHash - Cari token reset berdasarkan hash
+func (r *passwordResetRepository) FindByTokenHash(tokenHash string) (*model.PasswordResetToken, error) {
+	token := &model.PasswordResetToken{}
+	query := `
+		SELECT id, user_id, token_hash, created_by, expires_at, used_at, created_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1
+	`
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.CreatedBy,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// MarkUsed - Tandai token sudah dipakai
+// Return false jika token sudah dipakai lebih dulu (request lain menang)
+func (r *passwordResetRepository) MarkUsed(id string) (bool, error) {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = $1
+		WHERE id = $2 AND used_at IS NULL
+	`
+	result, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// InvalidateByUserID - Matikan semua token reset user yang belum dipakai
+// Dipanggil saat token baru diterbitkan dan saat password berhasil diganti
+func (r *passwordResetRepository) InvalidateByUserID(userID string) error {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = $1
+		WHERE user_id = $2 AND used_at IS NULL
+	`
+	_, err := r.db.Exec(query, time.Now(), userID)
+	return err
+}
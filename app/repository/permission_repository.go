@@ -2,10 +2,19 @@ package repository
 
 import (
 	"database/sql"
+	"UASBE/app/model"
+	"sync"
+	"time"
 )
 
 type PermissionRepository interface {
 	GetPermissionsByRoleID(roleID string) ([]string, error)
+
+	// Permission management
+	GetAll() ([]model.Permission, error)
+	FindByName(name string) (*model.Permission, error)
+	AttachToRole(roleID string, permissionID string) error
+	DetachFromRole(roleID string, permissionID string) error
 }
 
 type permissionRepository struct {
@@ -40,4 +49,151 @@ func (r *permissionRepository) GetPermissionsByRoleID(roleID string) ([]string,
 	}
 	
 	return permissions, nil
-}
\ No newline at end of file
+}
+
+// GetAll - Ambil semua permission, urut berdasarkan nama
+func (r *permissionRepository) GetAll() ([]model.Permission, error) {
+	query := `
+		SELECT id, name, resource, action, COALESCE(description, '')
+		FROM permissions
+		ORDER BY name
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []model.Permission
+	for rows.Next() {
+		var perm model.Permission
+		if err := rows.Scan(&perm.ID, &perm.Name, &perm.Resource, &perm.Action, &perm.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, perm)
+	}
+
+	return permissions, nil
+}
+
+// FindByName - Cari permission berdasarkan nama (misal "achievement:read")
+func (r *permissionRepository) FindByName(name string) (*model.Permission, error) {
+	perm := &model.Permission{}
+	query := `
+		SELECT id, name, resource, action, COALESCE(description, '')
+		FROM permissions
+		WHERE name = $1
+	`
+	err := r.db.QueryRow(query, name).Scan(&perm.ID, &perm.Name, &perm.Resource, &perm.Action, &perm.Description)
+	if err != nil {
+		return nil, err
+	}
+	return perm, nil
+}
+
+// AttachToRole - Tambah permission ke role (idempotent)
+func (r *permissionRepository) AttachToRole(roleID string, permissionID string) error {
+	query := `
+		INSERT INTO role_permissions (role_id, permission_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.Exec(query, roleID, permissionID)
+	return err
+}
+
+// DetachFromRole - Cabut permission dari role
+func (r *permissionRepository) DetachFromRole(roleID string, permissionID string) error {
+	query := `DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2`
+	_, err := r.db.Exec(query, roleID, permissionID)
+	return err
+}
+
+//
+// ==================== CACHED PERMISSION REPOSITORY ======================
+// Cache in-process di depan PermissionRepository supaya AuthRequired bisa
+// membaca permission terbaru tiap request tanpa query ke database setiap kali.
+// Entry kadaluarsa setelah ttl; perubahan role_permissions lewat repository ini
+// langsung meng-invalidate cache role tersebut.
+//
+
+type CachedPermissionRepository interface {
+	PermissionRepository
+	Invalidate(roleID string)
+	InvalidateAll()
+}
+
+type permissionCacheEntry struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+type cachedPermissionRepository struct {
+	inner   PermissionRepository
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[string]permissionCacheEntry // role_id -> permissions
+}
+
+func NewCachedPermissionRepository(inner PermissionRepository, ttl time.Duration) CachedPermissionRepository {
+	return &cachedPermissionRepository{
+		inner:   inner,
+		ttl:     ttl,
+		entries: make(map[string]permissionCacheEntry),
+	}
+}
+
+func (r *cachedPermissionRepository) GetPermissionsByRoleID(roleID string) ([]string, error) {
+	r.mu.RLock()
+	entry, ok := r.entries[roleID]
+	r.mu.RUnlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.permissions, nil
+	}
+
+	permissions, err := r.inner.GetPermissionsByRoleID(roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.entries[roleID] = permissionCacheEntry{permissions: permissions, expiresAt: time.Now().Add(r.ttl)}
+		r.mu.Unlock()
+	}
+
+	return permissions, nil
+}
+
+func (r *cachedPermissionRepository) GetAll() ([]model.Permission, error) {
+	return r.inner.GetAll()
+}
+
+func (r *cachedPermissionRepository) FindByName(name string) (*model.Permission, error) {
+	return r.inner.FindByName(name)
+}
+
+func (r *cachedPermissionRepository) AttachToRole(roleID string, permissionID string) error {
+	defer r.Invalidate(roleID)
+	return r.inner.AttachToRole(roleID, permissionID)
+}
+
+func (r *cachedPermissionRepository) DetachFromRole(roleID string, permissionID string) error {
+	defer r.Invalidate(roleID)
+	return r.inner.DetachFromRole(roleID, permissionID)
+}
+
+// Invalidate - Hapus cache satu role (dipanggil saat permission role berubah)
+func (r *cachedPermissionRepository) Invalidate(roleID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, roleID)
+}
+
+// InvalidateAll - Kosongkan seluruh cache
+func (r *cachedPermissionRepository) InvalidateAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = make(map[string]permissionCacheEntry)
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"UASBE/app/model"
+	"time"
+)
+
+type RefreshTokenRepository interface {
+	Create(token *model.RefreshToken) error
+	FindByID(id string) (*model.RefreshToken, error)
+	MarkRotated(id string, replacedBy string) (bool, error)
+	Revoke(id string) error
+	RevokeFamily(familyID string) error
+	RevokeAllByUserID(userID string) error
+}
+
+type refreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db}
+}
+
+// Create - Simpan refresh token baru (ID = jti sudah di-set dari luar)
+func (r *refreshTokenRepository) Create(token *model.RefreshToken) error {
+	token.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.Exec(query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+// FindByID - Cari refresh token berdasarkan jti
+func (r *refreshTokenRepository) FindByID(id string) (*model.RefreshToken, error) {
+	token := &model.RefreshToken{}
+	query := `
+		SELECT id, user_id, family_id, expires_at, revoked_at, replaced_by, created_at
+		FROM refresh_tokens
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(query, id).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// MarkRotated - Tandai token sudah dirotasi dan diganti token baru
+// Return false jika token sudah tidak aktif (sudah dirotasi/di-revoke sebelumnya),
+// sehingga dua request refresh paralel dengan token yang sama tidak bisa sama-sama lolos
+func (r *refreshTokenRepository) MarkRotated(id string, replacedBy string) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $1, replaced_by = $2
+		WHERE id = $3 AND revoked_at IS NULL
+	`
+	result, err := r.db.Exec(query, time.Now(), replacedBy, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// Revoke - Revoke satu refresh token
+func (r *refreshTokenRepository) Revoke(id string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+	_, err := r.db.Exec(query, time.Now(), id)
+	return err
+}
+
+// RevokeFamily - Revoke semua token dalam satu rantai rotasi
+// beserta sesi login-nya (user_sessions.id = family_id)
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.revokeWithSessions(
+		`UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`,
+		`UPDATE user_sessions SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`,
+		familyID,
+	)
+}
+
+// RevokeAllByUserID - Revoke semua refresh token dan sesi login milik user
+func (r *refreshTokenRepository) RevokeAllByUserID(userID string) error {
+	return r.revokeWithSessions(
+		`UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`,
+		`UPDATE user_sessions SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`,
+		userID,
+	)
+}
+
+func (r *refreshTokenRepository) revokeWithSessions(tokenQuery string, sessionQuery string, id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec(tokenQuery, now, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(sessionQuery, now, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"UASBE/app/model"
 	"time"
 
@@ -13,11 +14,11 @@ import (
 
 type ReportRepository interface {
 	// Statistics methods
-	GetTotalByType(studentID *string, advisorID *string) (map[string]int, error)
-	GetTotalByPeriod(studentID *string, advisorID *string) ([]model.PeriodStats, error)
-	GetTopStudents(limit int, advisorID *string) ([]model.TopStudent, error)
-	GetCompetitionLevelDistribution(studentID *string, advisorID *string) (map[string]int, error)
-	GetStatusBreakdown(studentID *string, advisorID *string) (map[string]int, error)
+	GetTotalByType(scope model.AccessScope) (map[string]int, error)
+	GetTotalByPeriod(scope model.AccessScope) ([]model.PeriodStats, error)
+	GetTopStudents(limit int, scope model.AccessScope) ([]model.TopStudent, error)
+	GetCompetitionLevelDistribution(scope model.AccessScope) (map[string]int, error)
+	GetStatusBreakdown(scope model.AccessScope) (map[string]int, error)
 	
 	// Student report methods
 	GetStudentSummary(studentID string) (*model.StudentSummary, error)
@@ -43,32 +44,18 @@ func NewReportRepository(pgDB *sql.DB, mongoDB *mongo.Database) ReportRepository
 //
 
 // GetTotalByType - Hitung total prestasi per tipe
-func (r *reportRepository) GetTotalByType(studentID *string, advisorID *string) (map[string]int, error) {
+func (r *reportRepository) GetTotalByType(scope model.AccessScope) (map[string]int, error) {
 	collection := r.mongoDB.Collection("achievements")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Build filter based on scope
-	var refQuery string
-	var args []interface{}
-	
-	if studentID != nil {
-		// Specific student
-		refQuery = `SELECT mongo_achievement_id FROM achievement_references WHERE status = 'verified' AND student_id = $1`
-		args = append(args, *studentID)
-	} else if advisorID != nil {
-		// All students of advisor
-		refQuery = `
-			SELECT ar.mongo_achievement_id 
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE ar.status = 'verified' AND s.advisor_id = $1
-		`
-		args = append(args, *advisorID)
-	} else {
-		// All achievements
-		refQuery = `SELECT mongo_achievement_id FROM achievement_references WHERE status = 'verified'`
-	}
+	filter, args := scopeFilter(scope, nil)
+	refQuery := `
+		SELECT ar.mongo_achievement_id
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		WHERE ar.status = 'verified'` + filter
 
 	rows, err := r.pgDB.Query(refQuery, args...)
 	if err != nil {
@@ -108,47 +95,19 @@ func (r *reportRepository) GetTotalByType(studentID *string, advisorID *string)
 }
 
 // GetTotalByPeriod - Hitung total prestasi per periode (bulan)
-func (r *reportRepository) GetTotalByPeriod(studentID *string, advisorID *string) ([]model.PeriodStats, error) {
-	var query string
-	var args []interface{}
-
-	if studentID != nil {
-		query = `
-			SELECT 
-				TO_CHAR(verified_at, 'YYYY-MM') as period,
-				COUNT(*) as count
-			FROM achievement_references
-			WHERE status = 'verified' AND student_id = $1 AND verified_at IS NOT NULL
-			GROUP BY TO_CHAR(verified_at, 'YYYY-MM')
-			ORDER BY period DESC
-			LIMIT 12
-		`
-		args = append(args, *studentID)
-	} else if advisorID != nil {
-		query = `
-			SELECT 
-				TO_CHAR(ar.verified_at, 'YYYY-MM') as period,
-				COUNT(*) as count
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE ar.status = 'verified' AND s.advisor_id = $1 AND ar.verified_at IS NOT NULL
-			GROUP BY TO_CHAR(ar.verified_at, 'YYYY-MM')
-			ORDER BY period DESC
-			LIMIT 12
-		`
-		args = append(args, *advisorID)
-	} else {
-		query = `
-			SELECT 
-				TO_CHAR(verified_at, 'YYYY-MM') as period,
-				COUNT(*) as count
-			FROM achievement_references
-			WHERE status = 'verified' AND verified_at IS NOT NULL
-			GROUP BY TO_CHAR(verified_at, 'YYYY-MM')
-			ORDER BY period DESC
-			LIMIT 12
-		`
-	}
+func (r *reportRepository) GetTotalByPeriod(scope model.AccessScope) ([]model.PeriodStats, error) {
+	filter, args := scopeFilter(scope, nil)
+	query := `
+		SELECT 
+			TO_CHAR(ar.verified_at, 'YYYY-MM') as period,
+			COUNT(*) as count
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		WHERE ar.status = 'verified' AND ar.verified_at IS NOT NULL` + filter + `
+		GROUP BY TO_CHAR(ar.verified_at, 'YYYY-MM')
+		ORDER BY period DESC
+		LIMIT 12
+	`
 
 	rows, err := r.pgDB.Query(query, args...)
 	if err != nil {
@@ -175,45 +134,24 @@ func (r *reportRepository) GetTotalByPeriod(studentID *string, advisorID *string
 }
 
 // GetTopStudents - Dapatkan top mahasiswa berprestasi
-func (r *reportRepository) GetTopStudents(limit int, advisorID *string) ([]model.TopStudent, error) {
-	var query string
-	var args []interface{}
-
-	if advisorID != nil {
-		query = `
-			SELECT 
-				s.id,
-				s.student_id,
-				u.full_name,
-				s.program_study,
-				COUNT(ar.id) as achievement_count
-			FROM students s
-			JOIN users u ON s.id = u.id
-			JOIN achievement_references ar ON s.id = ar.student_id
-			WHERE ar.status = 'verified' AND s.advisor_id = $1
-			GROUP BY s.id, s.student_id, u.full_name, s.program_study
-			ORDER BY achievement_count DESC
-			LIMIT $2
-		`
-		args = append(args, *advisorID, limit)
-	} else {
-		query = `
-			SELECT 
-				s.id,
-				s.student_id,
-				u.full_name,
-				s.program_study,
-				COUNT(ar.id) as achievement_count
-			FROM students s
-			JOIN users u ON s.id = u.id
-			JOIN achievement_references ar ON s.id = ar.student_id
-			WHERE ar.status = 'verified'
-			GROUP BY s.id, s.student_id, u.full_name, s.program_study
-			ORDER BY achievement_count DESC
-			LIMIT $1
-		`
-		args = append(args, limit)
-	}
+func (r *reportRepository) GetTopStudents(limit int, scope model.AccessScope) ([]model.TopStudent, error) {
+	filter, args := scopeFilter(scope, nil)
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT 
+			s.id,
+			s.student_id,
+			u.full_name,
+			s.program_study,
+			COUNT(ar.id) as achievement_count
+		FROM students s
+		JOIN users u ON s.id = u.id
+		JOIN achievement_references ar ON s.id = ar.student_id
+		WHERE ar.status = 'verified'%s
+		GROUP BY s.id, s.student_id, u.full_name, s.program_study
+		ORDER BY achievement_count DESC
+		LIMIT $%d
+	`, filter, len(args))
 
 	rows, err := r.pgDB.Query(query, args...)
 	if err != nil {
@@ -268,29 +206,18 @@ func (r *reportRepository) GetTopStudents(limit int, advisorID *string) ([]model
 }
 
 // GetCompetitionLevelDistribution - Distribusi tingkat kompetisi
-func (r *reportRepository) GetCompetitionLevelDistribution(studentID *string, advisorID *string) (map[string]int, error) {
+func (r *reportRepository) GetCompetitionLevelDistribution(scope model.AccessScope) (map[string]int, error) {
 	collection := r.mongoDB.Collection("achievements")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Get verified achievement mongo IDs
-	var query string
-	var args []interface{}
-
-	if studentID != nil {
-		query = `SELECT mongo_achievement_id FROM achievement_references WHERE status = 'verified' AND student_id = $1`
-		args = append(args, *studentID)
-	} else if advisorID != nil {
-		query = `
-			SELECT ar.mongo_achievement_id 
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE ar.status = 'verified' AND s.advisor_id = $1
-		`
-		args = append(args, *advisorID)
-	} else {
-		query = `SELECT mongo_achievement_id FROM achievement_references WHERE status = 'verified'`
-	}
+	filter, args := scopeFilter(scope, nil)
+	query := `
+		SELECT ar.mongo_achievement_id
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		WHERE ar.status = 'verified'` + filter
 
 	rows, err := r.pgDB.Query(query, args...)
 	if err != nil {
@@ -325,35 +252,15 @@ func (r *reportRepository) GetCompetitionLevelDistribution(studentID *string, ad
 }
 
 // GetStatusBreakdown - Breakdown by status
-func (r *reportRepository) GetStatusBreakdown(studentID *string, advisorID *string) (map[string]int, error) {
-	var query string
-	var args []interface{}
-
-	if studentID != nil {
-		query = `
-			SELECT status, COUNT(*) as count
-			FROM achievement_references
-			WHERE student_id = $1 AND status != 'deleted'
-			GROUP BY status
-		`
-		args = append(args, *studentID)
-	} else if advisorID != nil {
-		query = `
-			SELECT ar.status, COUNT(*) as count
-			FROM achievement_references ar
-			JOIN students s ON ar.student_id = s.id
-			WHERE s.advisor_id = $1 AND ar.status != 'deleted'
-			GROUP BY ar.status
-		`
-		args = append(args, *advisorID)
-	} else {
-		query = `
-			SELECT status, COUNT(*) as count
-			FROM achievement_references
-			WHERE status != 'deleted'
-			GROUP BY status
-		`
-	}
+func (r *reportRepository) GetStatusBreakdown(scope model.AccessScope) (map[string]int, error) {
+	filter, args := scopeFilter(scope, nil)
+	query := `
+		SELECT ar.status, COUNT(*) as count
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		WHERE ar.status != 'deleted'` + filter + `
+		GROUP BY ar.status
+	`
 
 	rows, err := r.pgDB.Query(query, args...)
 	if err != nil {
@@ -440,15 +347,15 @@ func (r *reportRepository) GetStudentSummary(studentID string) (*model.StudentSu
 
 // GetStudentAchievementsByType - Count by type
 func (r *reportRepository) GetStudentAchievementsByType(studentID string) (map[string]int, error) {
-	return r.GetTotalByType(&studentID, nil)
+	return r.GetTotalByType(model.AccessScope{Kind: model.ScopeOwn, StudentID: studentID})
 }
 
 // GetStudentAchievementsByStatus - Count by status
 func (r *reportRepository) GetStudentAchievementsByStatus(studentID string) (map[string]int, error) {
-	return r.GetStatusBreakdown(&studentID, nil)
+	return r.GetStatusBreakdown(model.AccessScope{Kind: model.ScopeOwn, StudentID: studentID})
 }
 
 // GetStudentTimeline - Monthly timeline
 func (r *reportRepository) GetStudentTimeline(studentID string) ([]model.PeriodStats, error) {
-	return r.GetTotalByPeriod(&studentID, nil)
+	return r.GetTotalByPeriod(model.AccessScope{Kind: model.ScopeOwn, StudentID: studentID})
 }
\ No newline at end of file
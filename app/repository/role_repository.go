@@ -3,11 +3,21 @@ package repository
 import (
 	"database/sql"
 	"UASBE/app/model"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type RoleRepository interface {
 	GetRoleByID(id string) (*model.Role, error)
 	GetRoleByName(name string) (*model.Role, error) // ⭐ TAMBAHKAN METHOD INI
+
+	// Role management
+	GetAll() ([]model.Role, error)
+	Create(role *model.Role, permissionIDs []string) error
+	Update(role *model.Role) error
+	Delete(id string) error
+	CountUsers(roleID string) (int, error)
 }
 
 type roleRepository struct {
@@ -21,8 +31,8 @@ func NewRoleRepository(db *sql.DB) RoleRepository {
 // GetRoleByID - Cari role berdasarkan ID (EXISTING)
 func (r *roleRepository) GetRoleByID(id string) (*model.Role, error) {
 	role := &model.Role{}
-	query := `SELECT id, name, description, created_at FROM roles WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt)
+	query := `SELECT id, name, description, mfa_required, created_at FROM roles WHERE id = $1`
+	err := r.db.QueryRow(query, id).Scan(&role.ID, &role.Name, &role.Description, &role.MFARequired, &role.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -32,10 +42,83 @@ func (r *roleRepository) GetRoleByID(id string) (*model.Role, error) {
 // GetRoleByName - Cari role berdasarkan name (NEW)
 func (r *roleRepository) GetRoleByName(name string) (*model.Role, error) {
 	role := &model.Role{}
-	query := `SELECT id, name, description, created_at FROM roles WHERE name = $1`
-	err := r.db.QueryRow(query, name).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt)
+	query := `SELECT id, name, description, mfa_required, created_at FROM roles WHERE name = $1`
+	err := r.db.QueryRow(query, name).Scan(&role.ID, &role.Name, &role.Description, &role.MFARequired, &role.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 	return role, nil
-}
\ No newline at end of file
+}
+
+// GetAll - Ambil semua role, urut berdasarkan nama
+func (r *roleRepository) GetAll() ([]model.Role, error) {
+	query := `SELECT id, name, COALESCE(description, ''), mfa_required, created_at FROM roles ORDER BY name`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []model.Role
+	for rows.Next() {
+		var role model.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.MFARequired, &role.CreatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
+// Create - Buat role baru
+func (r *roleRepository) Create(role *model.Role, permissionIDs []string) error {
+	role.ID = uuid.New().String()
+	role.CreatedAt = time.Now()
+
+	// Role dan permission-nya dalam satu transaksi: tidak ada role setengah jadi
+	// yang membuat retry gagal dengan nama role yang sudah ada
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO roles (id, name, description, mfa_required, created_at) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.Exec(query, role.ID, role.Name, role.Description, role.MFARequired, role.CreatedAt); err != nil {
+		return err
+	}
+
+	for _, permissionID := range permissionIDs {
+		_, err := tx.Exec(
+			`INSERT INTO role_permissions (role_id, permission_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			role.ID, permissionID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Update - Update nama, deskripsi dan kewajiban MFA role
+func (r *roleRepository) Update(role *model.Role) error {
+	query := `UPDATE roles SET name = $1, description = $2, mfa_required = $3 WHERE id = $4`
+	_, err := r.db.Exec(query, role.Name, role.Description, role.MFARequired, role.ID)
+	return err
+}
+
+// Delete - Hapus role (role_permissions ikut terhapus lewat ON DELETE CASCADE)
+func (r *roleRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM roles WHERE id = $1`, id)
+	return err
+}
+
+// CountUsers - Jumlah user (yang belum dihapus) dengan role ini
+func (r *roleRepository) CountUsers(roleID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM users WHERE role_id = $1 AND deleted_at IS NULL`
+	err := r.db.QueryRow(query, roleID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"fmt"
+
+	"UASBE/app/model"
+)
+
+// scopeFilter - Kondisi WHERE tambahan sesuai AccessScope.
+// Query harus memakai alias ar (achievement_references) dan s (students).
+// Placeholder dilanjutkan dari jumlah args yang sudah ada.
+func scopeFilter(scope model.AccessScope, args []interface{}) (string, []interface{}) {
+	switch scope.Kind {
+	case model.ScopeAll:
+		return "", args
+	case model.ScopeOwn:
+		args = append(args, scope.StudentID)
+		return fmt.Sprintf(" AND ar.student_id = $%d", len(args)), args
+	case model.ScopeAdvisees:
+		// Mahasiswa bimbingan sendiri, ditambah bimbingan dosen yang sedang mendelegasikan ke actor
+		args = append(args, scope.AdvisorID)
+		return fmt.Sprintf(" AND (s.advisor_id = $%d OR %s)", len(args), delegatedAdvisees(len(args))), args
+	case model.ScopeDepartment:
+		// Mahasiswa satu program studi, ditambah mahasiswa bimbingan sendiri / delegasi
+		args = append(args, scope.Department, scope.AdvisorID)
+		return fmt.Sprintf(" AND (s.program_study = $%d OR s.advisor_id = $%d OR %s)", len(args)-1, len(args), delegatedAdvisees(len(args))), args
+	default:
+		return " AND FALSE", args
+	}
+}
+
+// delegatedAdvisees - Kondisi mahasiswa yang dosen walinya sedang mendelegasikan
+// hak verifikasi ke dosen pada placeholder ke-n
+func delegatedAdvisees(n int) string {
+	return fmt.Sprintf(`s.advisor_id IN (
+		SELECT vd.advisor_id FROM verifier_delegations vd
+		WHERE vd.delegate_id = $%d AND vd.revoked_at IS NULL AND CURRENT_DATE BETWEEN vd.starts_on AND vd.ends_on
+	)`, n)
+}
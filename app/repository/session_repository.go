@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"database/sql"
+	"UASBE/app/model"
+	"time"
+)
+
+// sessionTouchInterval - last_used_at hanya di-update jika sudah lebih lama dari ini,
+// supaya AuthRequired tidak menulis ke database di setiap request
+const sessionTouchInterval = time.Minute
+
+// SessionRepository - Sesi login per device di tabel user_sessions.
+// ID sesi = family_id refresh token, sehingga revoke sesi juga me-revoke
+// refresh token-nya (dan sebaliknya, lihat RefreshTokenRepository.RevokeFamily).
+type SessionRepository interface {
+	Create(session *model.UserSession) error
+	FindByID(id string) (*model.UserSession, error)
+	GetActiveByUserID(userID string) ([]model.UserSession, error)
+	Touch(id string) (bool, error)
+	Revoke(id string) error
+}
+
+type sessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) SessionRepository {
+	return &sessionRepository{db}
+}
+
+// Create - Simpan sesi baru saat login (ID sudah di-set dari luar = family_id)
+func (r *sessionRepository) Create(session *model.UserSession) error {
+	now := time.Now()
+	session.IssuedAt = now
+	session.LastUsedAt = now
+
+	query := `
+		INSERT INTO user_sessions (id, user_id, user_agent, ip_address, issued_at, last_used_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(query,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IPAddress,
+		session.IssuedAt,
+		session.LastUsedAt,
+	)
+	return err
+}
+
+// FindByID - Cari sesi berdasarkan ID (termasuk yang sudah di-revoke)
+func (r *sessionRepository) FindByID(id string) (*model.UserSession, error) {
+	session := &model.UserSession{}
+	query := `
+		SELECT id, user_id, user_agent, ip_address, issued_at, last_used_at, revoked_at
+		FROM user_sessions
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(query, id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.IssuedAt,
+		&session.LastUsedAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetActiveByUserID - Sesi yang belum di-revoke dan masih punya refresh token berlaku
+func (r *sessionRepository) GetActiveByUserID(userID string) ([]model.UserSession, error) {
+	query := `
+		SELECT s.id, s.user_id, s.user_agent, s.ip_address, s.issued_at, s.last_used_at, s.revoked_at
+		FROM user_sessions s
+		WHERE s.user_id = $1
+		  AND s.revoked_at IS NULL
+		  AND EXISTS (
+			SELECT 1 FROM refresh_tokens rt
+			WHERE rt.family_id = s.id AND rt.expires_at > $2
+		  )
+		ORDER BY s.last_used_at DESC
+	`
+	rows, err := r.db.Query(query, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []model.UserSession
+	for rows.Next() {
+		var session model.UserSession
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.UserAgent,
+			&session.IPAddress,
+			&session.IssuedAt,
+			&session.LastUsedAt,
+			&session.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// Touch - Catat pemakaian sesi. Return false jika sesi tidak ada atau sudah di-revoke.
+func (r *sessionRepository) Touch(id string) (bool, error) {
+	var lastUsedAt time.Time
+	var revokedAt *time.Time
+
+	err := r.db.QueryRow(`SELECT last_used_at, revoked_at FROM user_sessions WHERE id = $1`, id).
+		Scan(&lastUsedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if revokedAt != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	if now.Sub(lastUsedAt) >= sessionTouchInterval {
+		query := `UPDATE user_sessions SET last_used_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+		if _, err := r.db.Exec(query, now, id); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Revoke - Revoke sesi beserta refresh token family-nya dalam satu transaksi.
+// Access token dengan sid ini langsung ditolak AuthRequired.
+func (r *sessionRepository) Revoke(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec(`UPDATE user_sessions SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, now, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`, now, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// TokenRevocationRepository - Denylist access token (berdasarkan jti) dan
+// batas waktu "token valid setelah" per user. Dipakai middleware.AuthRequired
+// supaya deactivate user, ganti role, ganti password dan logout langsung berlaku
+// tanpa menunggu access token expired.
+type TokenRevocationRepository interface {
+	DenyToken(tokenID string, userID string, expiresAt time.Time) error
+	IsTokenDenied(tokenID string) (bool, error)
+	SetTokensValidAfter(userID string, validAfter time.Time) error
+	GetTokensValidAfter(userID string) (*time.Time, error)
+}
+
+//
+// ==================== POSTGRESQL BACKEND ======================
+//
+
+type tokenRevocationRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRevocationRepository(db *sql.DB) TokenRevocationRepository {
+	return &tokenRevocationRepository{db}
+}
+
+// DenyToken - Masukkan jti ke denylist sampai token tersebut expired
+func (r *tokenRevocationRepository) DenyToken(tokenID string, userID string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_access_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	if _, err := r.db.Exec(query, tokenID, userID, expiresAt, time.Now()); err != nil {
+		return err
+	}
+
+	// Bersihkan entry yang token-nya sudah expired (tidak perlu dicek lagi)
+	_, err := r.db.Exec(`DELETE FROM revoked_access_tokens WHERE expires_at < $1`, time.Now())
+	return err
+}
+
+// IsTokenDenied - Cek apakah jti ada di denylist
+func (r *tokenRevocationRepository) IsTokenDenied(tokenID string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_access_tokens WHERE jti = $1)`
+	err := r.db.QueryRow(query, tokenID).Scan(&exists)
+	return exists, err
+}
+
+// SetTokensValidAfter - Token user yang diterbitkan sebelum validAfter dianggap tidak berlaku
+func (r *tokenRevocationRepository) SetTokensValidAfter(userID string, validAfter time.Time) error {
+	query := `
+		INSERT INTO user_token_cutoffs (user_id, valid_after)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET valid_after = EXCLUDED.valid_after
+	`
+	_, err := r.db.Exec(query, userID, validAfter.Truncate(time.Second))
+	return err
+}
+
+// GetTokensValidAfter - Return nil jika user belum pernah punya batas waktu
+func (r *tokenRevocationRepository) GetTokensValidAfter(userID string) (*time.Time, error) {
+	var validAfter time.Time
+	query := `SELECT valid_after FROM user_token_cutoffs WHERE user_id = $1`
+	err := r.db.QueryRow(query, userID).Scan(&validAfter)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &validAfter, nil
+}
+
+//
+// ==================== IN-MEMORY BACKEND ======================
+// Untuk development / single instance. Data hilang saat server restart.
+//
+
+type memoryTokenRevocationRepository struct {
+	mu          sync.RWMutex
+	denied      map[string]time.Time // jti -> expires_at
+	validAfters map[string]time.Time // user_id -> valid_after
+}
+
+func NewMemoryTokenRevocationRepository() TokenRevocationRepository {
+	return &memoryTokenRevocationRepository{
+		denied:      make(map[string]time.Time),
+		validAfters: make(map[string]time.Time),
+	}
+}
+
+func (r *memoryTokenRevocationRepository) DenyToken(tokenID string, userID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, exp := range r.denied {
+		if exp.Before(now) {
+			delete(r.denied, id)
+		}
+	}
+
+	r.denied[tokenID] = expiresAt
+	return nil
+}
+
+func (r *memoryTokenRevocationRepository) IsTokenDenied(tokenID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.denied[tokenID]
+	return exists, nil
+}
+
+func (r *memoryTokenRevocationRepository) SetTokensValidAfter(userID string, validAfter time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.validAfters[userID] = validAfter.Truncate(time.Second)
+	return nil
+}
+
+func (r *memoryTokenRevocationRepository) GetTokensValidAfter(userID string) (*time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	validAfter, exists := r.validAfters[userID]
+	if !exists {
+		return nil, nil
+	}
+	return &validAfter, nil
+}
@@ -19,10 +19,18 @@ type UserRepository interface {
 	Create(user *model.User) error
 	Update(user *model.User) error
 	Delete(id string) error
+	SoftDelete(id string) error
 	FindByEmail(email string) (*model.User, error)
 	GetAll(limit, offset int, roleName string) ([]model.User, error)
 	CountAll(roleName string) (int, error)
+	CountActiveWithPermission(permission string) (int, error)
 	UpdateRole(userID string, roleID string) error
+	UpdatePassword(userID string, passwordHash string) error
+
+	// Login attempt tracking (brute-force protection)
+	IncrementFailedLogins(userID string) (int, error)
+	LockUntil(userID string, until time.Time) error
+	ResetFailedLogins(userID string) error
 }
 
 type userRepository struct {
@@ -40,7 +48,7 @@ func NewUserRepository(db *sql.DB) UserRepository {
 func (r *userRepository) FindByUsername(username string) (*model.User, error) {
 	user := model.User{}
 	query := `
-		SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+		SELECT id, username, email, password_hash, full_name, role_id, is_active, failed_login_attempts, locked_until, deleted_at, mfa_enabled, mfa_secret, created_at, updated_at
 		FROM users
 		WHERE username = $1 LIMIT 1
 	`
@@ -52,6 +60,11 @@ func (r *userRepository) FindByUsername(username string) (*model.User, error) {
 		&user.FullName,
 		&user.RoleID,
 		&user.IsActive,
+		&user.FailedLoginAttempts,
+		&user.LockedUntil,
+		&user.DeletedAt,
+		&user.MFAEnabled,
+		&user.MFASecret,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -64,7 +77,7 @@ func (r *userRepository) FindByUsername(username string) (*model.User, error) {
 func (r *userRepository) FindByID(id string) (*model.User, error) {
 	user := model.User{}
 	query := `
-		SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+		SELECT id, username, email, password_hash, full_name, role_id, is_active, failed_login_attempts, locked_until, deleted_at, mfa_enabled, mfa_secret, created_at, updated_at
 		FROM users
 		WHERE id = $1 LIMIT 1
 	`
@@ -76,6 +89,11 @@ func (r *userRepository) FindByID(id string) (*model.User, error) {
 		&user.FullName,
 		&user.RoleID,
 		&user.IsActive,
+		&user.FailedLoginAttempts,
+		&user.LockedUntil,
+		&user.DeletedAt,
+		&user.MFAEnabled,
+		&user.MFASecret,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -164,11 +182,33 @@ func (r *userRepository) Delete(id string) error {
 	return err
 }
 
+// SoftDelete - Tandai user sebagai deleted (baris tetap ada supaya status akun bisa dibaca)
+func (r *userRepository) SoftDelete(id string) error {
+	query := `
+		UPDATE users
+		SET is_active = false, deleted_at = $1, updated_at = $1
+		WHERE id = $2
+	`
+	_, err := r.db.Exec(query, time.Now(), id)
+	return err
+}
+
+// UpdatePassword - Ganti password hash user
+func (r *userRepository) UpdatePassword(userID string, passwordHash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $1, updated_at = $2
+		WHERE id = $3
+	`
+	_, err := r.db.Exec(query, passwordHash, time.Now(), userID)
+	return err
+}
+
 // FindByEmail - Cari user berdasarkan email
 func (r *userRepository) FindByEmail(email string) (*model.User, error) {
 	user := &model.User{}
 	query := `
-		SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+		SELECT id, username, email, password_hash, full_name, role_id, is_active, failed_login_attempts, locked_until, deleted_at, mfa_enabled, mfa_secret, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -180,6 +220,11 @@ func (r *userRepository) FindByEmail(email string) (*model.User, error) {
 		&user.FullName,
 		&user.RoleID,
 		&user.IsActive,
+		&user.FailedLoginAttempts,
+		&user.LockedUntil,
+		&user.DeletedAt,
+		&user.MFAEnabled,
+		&user.MFASecret,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -197,7 +242,7 @@ func (r *userRepository) GetAll(limit, offset int, roleName string) ([]model.Use
 
 	if roleName != "" {
 		query = `
-			SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.role_id, u.is_active, u.created_at, u.updated_at
+			SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.role_id, u.is_active, u.failed_login_attempts, u.locked_until, u.deleted_at, u.mfa_enabled, u.mfa_secret, u.created_at, u.updated_at
 			FROM users u
 			JOIN roles r ON u.role_id = r.id
 			WHERE r.name = $1
@@ -207,7 +252,7 @@ func (r *userRepository) GetAll(limit, offset int, roleName string) ([]model.Use
 		rows, err = r.db.Query(query, roleName, limit, offset)
 	} else {
 		query = `
-			SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+			SELECT id, username, email, password_hash, full_name, role_id, is_active, failed_login_attempts, locked_until, deleted_at, mfa_enabled, mfa_secret, created_at, updated_at
 			FROM users
 			ORDER BY created_at DESC
 			LIMIT $1 OFFSET $2
@@ -231,6 +276,11 @@ func (r *userRepository) GetAll(limit, offset int, roleName string) ([]model.Use
 			&u.FullName,
 			&u.RoleID,
 			&u.IsActive,
+			&u.FailedLoginAttempts,
+			&u.LockedUntil,
+			&u.DeletedAt,
+			&u.MFAEnabled,
+			&u.MFASecret,
 			&u.CreatedAt,
 			&u.UpdatedAt,
 		)
@@ -263,6 +313,20 @@ func (r *userRepository) CountAll(roleName string) (int, error) {
 	return count, err
 }
 
+// CountActiveWithPermission - Jumlah user aktif (belum dihapus) yang role-nya memegang permission
+func (r *userRepository) CountActiveWithPermission(permission string) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*)
+		FROM users u
+		JOIN role_permissions rp ON rp.role_id = u.role_id
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE p.name = $1 AND u.is_active = true AND u.deleted_at IS NULL
+	`
+	err := r.db.QueryRow(query, permission).Scan(&count)
+	return count, err
+}
+
 // UpdateRole - Update role user
 func (r *userRepository) UpdateRole(userID string, roleID string) error {
 	query := `
@@ -272,4 +336,40 @@ func (r *userRepository) UpdateRole(userID string, roleID string) error {
 	`
 	_, err := r.db.Exec(query, roleID, time.Now(), userID)
 	return err
-}
\ No newline at end of file
+}
+
+//
+// ==================== LOGIN ATTEMPT TRACKING ======================
+//
+
+// IncrementFailedLogins - Tambah counter login gagal, return jumlah percobaan gagal terbaru
+func (r *userRepository) IncrementFailedLogins(userID string) (int, error) {
+	var attempts int
+	query := `
+		UPDATE users
+		SET failed_login_attempts = failed_login_attempts + 1, last_failed_login_at = $1
+		WHERE id = $2
+		RETURNING failed_login_attempts
+	`
+	err := r.db.QueryRow(query, time.Now(), userID).Scan(&attempts)
+	return attempts, err
+}
+
+// LockUntil - Kunci akun sampai waktu tertentu
+func (r *userRepository) LockUntil(userID string, until time.Time) error {
+	query := `UPDATE users SET locked_until = $1 WHERE id = $2`
+	_, err := r.db.Exec(query, until, userID)
+	return err
+}
+
+// ResetFailedLogins - Reset counter login gagal dan buka kunci akun
+// Dipakai saat login berhasil dan saat admin unlock
+func (r *userRepository) ResetFailedLogins(userID string) error {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = 0, locked_until = NULL, updated_at = $1
+		WHERE id = $2
+	`
+	_, err := r.db.Exec(query, time.Now(), userID)
+	return err
+}
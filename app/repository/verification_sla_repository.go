@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"UASBE/app/model"
+	"time"
+)
+
+// VerificationSLARepository - Prestasi yang terlalu lama menunggu verifikasi
+// (status submitted) beserta penanda pengingat / eskalasi di achievement_references
+type VerificationSLARepository interface {
+	GetOverdue(waitingSince time.Time, scope model.AccessScope) ([]model.OverdueAchievement, error)
+	FindReviewers(permission string, department string) ([]string, error)
+	MarkReminded(refID string, at time.Time, notifications []model.Notification) (bool, error)
+	MarkEscalated(refID string, at time.Time, notifications []model.Notification) (bool, error)
+}
+
+type verificationSLARepository struct {
+	db *sql.DB
+}
+
+func NewVerificationSLARepository(db *sql.DB) VerificationSLARepository {
+	return &verificationSLARepository{db}
+}
+
+// slaStageStartedAt - Awal tahap persetujuan saat ini: persetujuan tahap terakhir
+// (approval_stage > 0), selain itu submitted_at
+const slaStageStartedAt = `CASE WHEN ar.approval_stage > 0 THEN COALESCE((
+		SELECT MAX(h.created_at) FROM achievement_status_history h
+		WHERE h.achievement_ref_id = ar.id AND h.from_status = 'submitted' AND h.to_status = 'submitted'
+	), ar.submitted_at) ELSE ar.submitted_at END`
+
+// GetOverdue - Prestasi submitted yang tahap persetujuannya dimulai <= waitingSince, paling lama dulu.
+// reminded_at / escalated_at dari tahap / pengajuan sebelumnya (lebih awal dari awal tahap) dikosongkan.
+func (r *verificationSLARepository) GetOverdue(waitingSince time.Time, scope model.AccessScope) ([]model.OverdueAchievement, error) {
+	filter, args := scopeFilter(scope, []interface{}{waitingSince})
+	query := `
+		SELECT
+			ar.id,
+			ar.student_id,
+			s.student_id,
+			u.full_name,
+			COALESCE(s.program_study, ''),
+			s.advisor_id,
+			au.full_name,
+			ar.submitted_at,
+			ar.approval_chain,
+			ar.approval_stage,
+			CASE WHEN ar.approval_stage > 0 THEN stage.started_at END,
+			CASE WHEN ar.reminded_at >= stage.started_at THEN ar.reminded_at END,
+			CASE WHEN ar.escalated_at >= stage.started_at THEN ar.escalated_at END
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		JOIN users u ON s.id = u.id
+		LEFT JOIN users au ON s.advisor_id = au.id
+		CROSS JOIN LATERAL (SELECT ` + slaStageStartedAt + ` AS started_at) stage
+		WHERE ar.status = 'submitted' AND stage.started_at <= $1` + filter + `
+		ORDER BY stage.started_at ASC
+	`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.OverdueAchievement{}
+	for rows.Next() {
+		var item model.OverdueAchievement
+		err := rows.Scan(
+			&item.ID,
+			&item.StudentID,
+			&item.StudentNIM,
+			&item.StudentName,
+			&item.ProgramStudy,
+			&item.AdvisorID,
+			&item.AdvisorName,
+			&item.SubmittedAt,
+			&item.ApprovalChain,
+			&item.ApprovalStage,
+			&item.StageStartedAt,
+			&item.RemindedAt,
+			&item.EscalatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// FindReviewers - User aktif yang role-nya punya permission tersebut.
+// department tidak kosong = hanya dosen dengan departemen itu.
+func (r *verificationSLARepository) FindReviewers(permission string, department string) ([]string, error) {
+	query := `
+		SELECT DISTINCT u.id
+		FROM users u
+		JOIN role_permissions rp ON rp.role_id = u.role_id
+		JOIN permissions p ON p.id = rp.permission_id
+		LEFT JOIN lecturers l ON l.id = u.id
+		WHERE p.name = $1
+			AND u.is_active = true
+			AND u.deleted_at IS NULL
+			AND ($2 = '' OR l.department = $2)
+		ORDER BY u.id
+	`
+	rows, err := r.db.Query(query, permission, department)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, rows.Err()
+}
+
+// MarkReminded - Catat pengingat + simpan notifikasinya dalam satu transaksi.
+// Return false jika prestasi sudah tidak submitted atau sudah diingatkan
+// untuk tahap persetujuan ini (mis. oleh instance lain).
+func (r *verificationSLARepository) MarkReminded(refID string, at time.Time, notifications []model.Notification) (bool, error) {
+	return r.mark(`
+		UPDATE achievement_references ar
+		SET reminded_at = $1
+		WHERE ar.id = $2 AND ar.status = 'submitted' AND (ar.reminded_at IS NULL OR ar.reminded_at < `+slaStageStartedAt+`)
+	`, refID, at, notifications)
+}
+
+// MarkEscalated - Sama dengan MarkReminded untuk eskalasi
+func (r *verificationSLARepository) MarkEscalated(refID string, at time.Time, notifications []model.Notification) (bool, error) {
+	return r.mark(`
+		UPDATE achievement_references ar
+		SET escalated_at = $1
+		WHERE ar.id = $2 AND ar.status = 'submitted' AND (ar.escalated_at IS NULL OR ar.escalated_at < `+slaStageStartedAt+`)
+	`, refID, at, notifications)
+}
+
+// Helper: mark - Conditional update penanda SLA, notifikasi hanya disimpan jika update berhasil
+func (r *verificationSLARepository) mark(query string, refID string, at time.Time, notifications []model.Notification) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, at, refID)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return false, err
+	} else if affected == 0 {
+		return false, nil
+	}
+
+	if err := insertNotifications(tx, notifications); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
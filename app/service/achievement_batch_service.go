@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"UASBE/app/model"
+	"UASBE/app/repository"
+	"UASBE/app/workflow"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// batchInput - Satu item batch: ID prestasi + catatan (hanya untuk reject)
+type batchInput struct {
+	ID   string
+	Note *string
+}
+
+// batchItem - Item yang lolos pengecekan dan siap diubah statusnya
+type batchItem struct {
+	index      int
+	ctx        *workflow.Context
+	transition *workflow.Transition
+}
+
+//
+// ==================== BATCH VERIFY (POST /achievements/batch/verify) ======================
+// Dosen wali memverifikasi banyak prestasi sekaligus. Setiap item melewati
+// pengecekan yang sama dengan VerifyAchievement (approver tahap + status submitted);
+// item di tahap persetujuan antara hanya maju ke tahap berikutnya.
+//
+
+func (s *AchievementService) BatchVerify(c *fiber.Ctx) error {
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Parse request
+	req := new(model.BatchVerifyRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	// Validasi
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "ids is required (1-100 items), mode must be 'atomic' or 'partial'",
+		})
+	}
+
+	inputs := make([]batchInput, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		inputs = append(inputs, batchInput{ID: id})
+	}
+
+	return s.runBatch(c, claims, workflow.ActionVerify, req.Mode, inputs)
+}
+
+//
+// ==================== BATCH REJECT (POST /achievements/batch/reject) ======================
+// Sama dengan BatchVerify; setiap item wajib punya catatan penolakan
+// (rejection_note per item atau rejection_note bersama).
+//
+
+func (s *AchievementService) BatchReject(c *fiber.Ctx) error {
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Parse request
+	req := new(model.BatchRejectRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	// Validasi
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "items is required (1-100 items, each with id), mode must be 'atomic' or 'partial'",
+		})
+	}
+
+	inputs := make([]batchInput, 0, len(req.Items))
+	for _, item := range req.Items {
+		note := item.RejectionNote
+		if note == "" {
+			note = req.RejectionNote
+		}
+		if note == "" {
+			return c.Status(422).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "rejection note is required for item " + item.ID,
+			})
+		}
+		inputs = append(inputs, batchInput{ID: item.ID, Note: &note})
+	}
+
+	return s.runBatch(c, claims, workflow.ActionReject, req.Mode, inputs)
+}
+
+//
+// ==================== HELPER: RUN BATCH ======================
+// 1. Cek setiap item lewat workflow (404/403/409 per item, seperti endpoint tunggal)
+// 2. atomic: jika ada yang gagal, tidak ada yang diubah (409); jika semua lolos,
+//    semua transisi disimpan dalam satu transaksi
+//    partial: item yang lolos disimpan satu per satu
+// 3. Response berisi hasil per item sesuai urutan request
+//
+
+func (s *AchievementService) runBatch(c *fiber.Ctx, claims *model.JWTClaims, action, mode string, inputs []batchInput) error {
+	if mode == "" {
+		mode = model.BatchModeAtomic
+	}
+
+	result := model.BatchResult{
+		Mode:    mode,
+		Total:   len(inputs),
+		Results: make([]model.BatchItemResult, len(inputs)),
+	}
+
+	// Satu actor untuk seluruh batch (profil dosen cukup dimuat sekali)
+	actor := s.policy.Actor(claims)
+	seen := map[string]bool{}
+	var ready []batchItem
+
+	for i, input := range inputs {
+		result.Results[i] = model.BatchItemResult{ID: input.ID}
+		itemResult := &result.Results[i]
+
+		if seen[input.ID] {
+			batchItemFailed(itemResult, 422, "duplicate id in batch")
+			continue
+		}
+		seen[input.ID] = true
+
+		reference, err := s.achievementRepo.GetReferenceByID(input.ID)
+		if err != nil {
+			batchItemFailed(itemResult, 404, "achievement not found")
+			continue
+		}
+		itemResult.Status = reference.Status
+
+		wctx := &workflow.Context{
+			Reference: reference,
+			Actor:     actor,
+			Claims:    claims,
+			Note:      input.Note,
+			Chain:     s.approvals.Chain(reference.ApprovalChain),
+		}
+		if err := s.loadEarlierDeciders(wctx); err != nil {
+			batchItemFailed(itemResult, 500, "failed to get achievement history")
+			continue
+		}
+
+		// Verify di tahap yang bukan tahap terakhir rantai = approve tahap tersebut
+		itemAction := action
+		if action == workflow.ActionVerify {
+			itemAction = workflow.VerifyAction(wctx)
+		}
+		transition, err := s.workflow.Apply(itemAction, wctx)
+		if err != nil {
+			var forbidden *workflow.ForbiddenError
+			var invalid *workflow.TransitionError
+			switch {
+			case errors.As(err, &forbidden):
+				batchItemFailed(itemResult, 403, forbidden.Message)
+			case errors.As(err, &invalid):
+				batchItemFailed(itemResult, 409, invalid.Error())
+			default:
+				batchItemFailed(itemResult, 500, err.Error())
+			}
+			continue
+		}
+
+		ready = append(ready, batchItem{index: i, ctx: wctx, transition: transition})
+	}
+
+	if mode == model.BatchModeAtomic {
+		if len(ready) < len(inputs) {
+			for _, item := range ready {
+				batchItemFailed(&result.Results[item.index], 0, "not applied: batch aborted")
+			}
+			return batchAborted(c, result, "batch aborted: some items failed, nothing was changed")
+		}
+
+		if err := s.changeStatusAll(ready); err != nil {
+			for _, item := range ready {
+				batchItemFailed(&result.Results[item.index], 0, "not applied: batch aborted")
+			}
+			if errors.Is(err, repository.ErrStatusChanged) {
+				return batchAborted(c, result, "achievement status was changed by another request, please reload")
+			}
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to process batch",
+				Data:   batchCount(result),
+			})
+		}
+
+		for _, item := range ready {
+			result.Results[item.index].Success = true
+			result.Results[item.index].Status = item.ctx.Reference.Status
+		}
+	} else {
+		for _, item := range ready {
+			itemResult := &result.Results[item.index]
+			if err := s.changeStatus(item.ctx, item.transition.To); err != nil {
+				if errors.Is(err, repository.ErrStatusChanged) {
+					batchItemFailed(itemResult, 409, "achievement status was changed by another request, please reload")
+				} else {
+					batchItemFailed(itemResult, 500, "failed to update achievement status")
+				}
+				continue
+			}
+			itemResult.Success = true
+			itemResult.Status = item.ctx.Reference.Status
+		}
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "batch processed",
+		Data:    batchCount(result),
+	})
+}
+
+// changeStatusAll - changeStatus untuk semua item dalam satu transaksi
+func (s *AchievementService) changeStatusAll(items []batchItem) error {
+	refs := make([]*model.AchievementReference, 0, len(items))
+	entries := make([]*model.AchievementStatusHistory, 0, len(items))
+	fromStatuses := make([]string, 0, len(items))
+
+	for _, item := range items {
+		reference := item.ctx.Reference
+		fromStatuses = append(fromStatuses, reference.Status)
+		entries = append(entries, statusEntry(reference.Status, item.ctx))
+		reference.Status = item.transition.To
+		refs = append(refs, reference)
+	}
+
+	if err := s.achievementRepo.TransitionReferences(refs, entries); err != nil {
+		for i, ref := range refs {
+			ref.Status = fromStatuses[i]
+		}
+		return err
+	}
+	return nil
+}
+
+func batchItemFailed(item *model.BatchItemResult, code int, message string) {
+	item.Success = false
+	item.Code = code
+	item.Error = message
+}
+
+// batchCount - Isi jumlah berhasil/gagal dari hasil per item
+func batchCount(result model.BatchResult) model.BatchResult {
+	result.Succeeded, result.Failed = 0, 0
+	for _, item := range result.Results {
+		if item.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+	return result
+}
+
+func batchAborted(c *fiber.Ctx, result model.BatchResult, message string) error {
+	return c.Status(409).JSON(model.APIResponse{
+		Status: "error",
+		Error:  message,
+		Data:   batchCount(result),
+	})
+}
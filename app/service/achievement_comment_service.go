@@ -0,0 +1,339 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"UASBE/app/model"
+	"UASBE/app/workflow"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// mentionPattern - @username di awal teks atau setelah karakter non-kata (bukan alamat email)
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_.]+)`)
+
+//
+// ==================== REQUEST REVISION (POST /achievements/:id/request-revision) ======================
+// Dosen wali mengembalikan prestasi ke mahasiswa untuk diperbaiki (status revision_requested).
+// Berbeda dengan reject: rejection_note tidak diisi, catatan hanya tercatat di riwayat.
+//
+
+func (s *AchievementService) RequestRevision(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Parse request
+	req := new(model.RequestRevisionRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	// Validasi: note wajib diisi
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "note is required",
+		})
+	}
+
+	// Get reference
+	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Check authorization + status (approver tahap saat ini, status = submitted)
+	wctx := s.workflowContext(reference, claims, &req.Note)
+	if err := s.loadEarlierDeciders(wctx); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get achievement history",
+		})
+	}
+	transition, err := s.workflow.Apply(workflow.ActionRequestRevision, wctx)
+	if err != nil {
+		return workflowError(c, err)
+	}
+
+	// Update status menjadi 'revision_requested'
+	if err := s.changeStatus(wctx, transition.To); err != nil {
+		return statusChangeError(c, err, "failed to request revision")
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "revision requested",
+		Data: fiber.Map{
+			"status": reference.Status,
+			"note":   req.Note,
+		},
+	})
+}
+
+//
+// ==================== GET COMMENTS (GET /achievements/:id/comments) ======================
+// Komentar prestasi dalam bentuk thread (balasan di dalam komentar induknya)
+//
+
+func (s *AchievementService) GetComments(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Get reference
+	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Prestasi yang di-soft delete hanya terlihat oleh admin
+	actor := s.policy.Actor(claims)
+	if !actor.CanSeeAchievement(reference) {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Check authorization
+	if !actor.CanReadAchievement(reference) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	comments, err := s.commentRepo.GetByAchievementRefID(reference.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get comments",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"achievement_id": reference.ID,
+			"total":          len(comments),
+			"comments":       buildCommentThreads(comments),
+		},
+	})
+}
+
+//
+// ==================== CREATE COMMENT (POST /achievements/:id/comments) ======================
+// Siapa pun yang bisa melihat prestasi bisa berkomentar atau membalas (parent_id).
+// @username yang dikenali disimpan sebagai mention dan mendapat notifikasi.
+//
+
+func (s *AchievementService) CreateComment(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Parse request
+	req := new(model.AchievementCommentRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	// Validasi
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "body is required (max 2000 characters)",
+		})
+	}
+
+	// Get reference
+	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Prestasi yang di-soft delete hanya terlihat oleh admin
+	actor := s.policy.Actor(claims)
+	if !actor.CanSeeAchievement(reference) {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Check authorization
+	if !actor.CanReadAchievement(reference) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	// Balasan harus ke komentar pada prestasi yang sama
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.FindByID(*req.ParentID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to get parent comment",
+			})
+		}
+		if parent == nil || parent.AchievementRefID != reference.ID {
+			return c.Status(422).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "parent comment not found on this achievement",
+			})
+		}
+	}
+
+	authorID := claims.UserID
+	comment := &model.AchievementComment{
+		AchievementRefID: reference.ID,
+		ParentID:         req.ParentID,
+		AuthorID:         &authorID,
+		AuthorRole:       claims.Role,
+		Body:             req.Body,
+		Mentions:         s.resolveMentions(req.Body, reference),
+	}
+
+	if err := s.commentRepo.Create(comment, mentionNotifications(comment, claims)); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create comment",
+		})
+	}
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "comment added",
+		Data:    comment,
+	})
+}
+
+//
+// ==================== HELPER: COMMENTS ======================
+//
+
+// resolveMentions - @username yang merujuk user aktif yang boleh membaca prestasi;
+// sisanya dibiarkan sebagai teks biasa
+func (s *AchievementService) resolveMentions(body string, reference *model.AchievementReference) []model.CommentMention {
+	mentions := []model.CommentMention{}
+	seen := map[string]bool{}
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := match[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		user, err := s.userRepo.FindByUsername(username)
+		if err != nil || user == nil || !user.IsActive || user.DeletedAt != nil {
+			continue
+		}
+		if !s.canMentionedUserRead(user, reference) {
+			continue
+		}
+		mentions = append(mentions, model.CommentMention{UserID: user.ID, Username: user.Username})
+	}
+	return mentions
+}
+
+// canMentionedUserRead - Aturan CanReadAchievement untuk user yang di-mention,
+// dengan permission role-nya saat ini
+func (s *AchievementService) canMentionedUserRead(user *model.User, reference *model.AchievementReference) bool {
+	if s.permissionRepo == nil {
+		return false
+	}
+	permissions, err := s.permissionRepo.GetPermissionsByRoleID(user.RoleID)
+	if err != nil {
+		return false
+	}
+	mentioned := s.policy.Actor(&model.JWTClaims{UserID: user.ID, Permissions: permissions})
+	return mentioned.CanReadAchievement(reference)
+}
+
+// mentionNotifications - Satu notifikasi per user yang di-mention, kecuali penulis sendiri
+func mentionNotifications(comment *model.AchievementComment, claims *model.JWTClaims) []model.Notification {
+	notifications := []model.Notification{}
+	for _, mention := range comment.Mentions {
+		if mention.UserID == claims.UserID {
+			continue
+		}
+		notifications = append(notifications, model.Notification{
+			UserID:           mention.UserID,
+			Type:             model.NotificationCommentMention,
+			AchievementRefID: &comment.AchievementRefID,
+			Message:          fmt.Sprintf("%s mentioned you in a comment on an achievement", claims.Username),
+		})
+	}
+	return notifications
+}
+
+// buildCommentThreads - Susun komentar datar menjadi pohon; urutan tetap kronologis
+func buildCommentThreads(comments []model.AchievementComment) []model.AchievementComment {
+	children := map[string][]model.AchievementComment{}
+	roots := []model.AchievementComment{}
+	ids := map[string]bool{}
+	for _, comment := range comments {
+		ids[comment.ID] = true
+	}
+
+	for _, comment := range comments {
+		if comment.ParentID != nil && ids[*comment.ParentID] {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	var attach func(list []model.AchievementComment) []model.AchievementComment
+	attach = func(list []model.AchievementComment) []model.AchievementComment {
+		for i := range list {
+			if replies, ok := children[list[i].ID]; ok {
+				list[i].Replies = attach(replies)
+			}
+		}
+		return list
+	}
+	return attach(roots)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"UASBE/app/model"
+	"UASBE/app/repository"
+	"UASBE/config"
+)
+
+// AchievementPurgeService - Menghapus permanen prestasi yang di-soft delete lebih lama
+// dari config.DeletedAchievementRetention: reference PostgreSQL (riwayat, snapshot dan
+// komentar ikut terhapus), file lampiran dan dokumen MongoDB.
+type AchievementPurgeService struct {
+	achievementRepo repository.AchievementRepository
+	uploadsDir      string
+}
+
+func NewAchievementPurgeService(achievementRepo repository.AchievementRepository, uploadsDir string) *AchievementPurgeService {
+	return &AchievementPurgeService{
+		achievementRepo: achievementRepo,
+		uploadsDir:      uploadsDir,
+	}
+}
+
+//
+// ==================== SCHEDULER ======================
+// Purge berjalan di goroutine setiap interval (sekali saat start).
+// Reference di-klaim (dihapus dalam transaksi yang belum di-commit) sebelum file dan
+// dokumen MongoDB disentuh, sehingga prestasi yang di-restore admin tidak ikut terhapus
+// dan instance lain yang menjalankan purge bersamaan melewati reference yang sama.
+//
+
+// Start - Jalankan purge berkala sampai ctx selesai
+func (s *AchievementPurgeService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			result, err := s.RunPurge(time.Now())
+			if err != nil {
+				log.Println("Achievement purge failed:", err)
+			} else if result.Purged > 0 {
+				log.Printf("Achievement purge: %d expired, %d purged, %d files removed", result.Checked, result.Purged, result.FilesRemoved)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunPurge - Satu kali purge: semua prestasi yang dihapus sebelum now - retensi
+func (s *AchievementPurgeService) RunPurge(now time.Time) (model.AchievementPurgeResult, error) {
+	result := model.AchievementPurgeResult{}
+
+	references, err := s.achievementRepo.GetPurgeableReferences(now.Add(-config.AppConfig.DeletedAchievementRetention))
+	if err != nil {
+		return result, err
+	}
+	result.Checked = len(references)
+
+	for _, ref := range references {
+		removed, err := s.purge(ref)
+		result.FilesRemoved += removed
+		if errors.Is(err, repository.ErrStatusChanged) {
+			continue // sudah di-restore admin atau di-purge instance lain
+		}
+		if err != nil {
+			log.Printf("Achievement purge: failed to purge achievement %s: %v", ref.ID, err)
+			continue
+		}
+		result.Purged++
+	}
+
+	return result, nil
+}
+
+// purge - Klaim reference lalu hapus file lampiran dan dokumen MongoDB. Jika salah satu
+// gagal, reference tetap ada dan purge diulang pada run berikutnya.
+func (s *AchievementPurgeService) purge(ref model.AchievementReference) (int, error) {
+	removed := 0
+
+	err := s.achievementRepo.PurgeReference(ref.ID, func() error {
+		achievement, err := s.achievementRepo.GetAchievementByID(ref.MongoAchievementID)
+		if errors.Is(err, repository.ErrAchievementNotFound) {
+			// Dokumen sudah terhapus oleh run sebelumnya
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, attachment := range achievement.Attachments {
+			path, ok := s.uploadPath(attachment.FileURL)
+			if !ok {
+				continue
+			}
+			if err := os.Remove(path); err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return err
+			}
+			removed++
+		}
+
+		return s.achievementRepo.PurgeAchievement(ref.MongoAchievementID)
+	})
+
+	return removed, err
+}
+
+// uploadPath - Lokasi file untuk URL lampiran "/uploads/<nama file>"; URL lain
+// (mis. file di luar server ini) tidak disentuh
+func (s *AchievementPurgeService) uploadPath(fileURL string) (string, bool) {
+	if !strings.HasPrefix(fileURL, "/uploads/") {
+		return "", false
+	}
+	name := filepath.Base(fileURL)
+	if name == "." || name == "/" || name == "uploads" {
+		return "", false
+	}
+	return filepath.Join(s.uploadsDir, name), true
+}
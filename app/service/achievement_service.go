@@ -1,13 +1,19 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"os"
 	"path/filepath"
 	"UASBE/app/model"
+	"UASBE/app/policy"
 	"UASBE/app/repository"
+	"UASBE/app/workflow"
+	"reflect"
+	"sort"
 	"strconv"
 	"time"
 
@@ -21,6 +27,11 @@ type AchievementService struct {
 	studentRepo     repository.StudentRepository
 	lecturerRepo    repository.LecturerRepository
 	userRepo        repository.UserRepository
+	commentRepo     repository.AchievementCommentRepository
+	permissionRepo  repository.PermissionRepository
+	policy          *policy.Policy
+	workflow        *workflow.Machine
+	approvals       *workflow.ApprovalChains
 	validate        *validator.Validate
 }
 
@@ -29,16 +40,37 @@ func NewAchievementService(
 	studentRepo repository.StudentRepository,
 	lecturerRepo repository.LecturerRepository,
 	userRepo repository.UserRepository,
+	commentRepo repository.AchievementCommentRepository,
 ) *AchievementService {
 	return &AchievementService{
 		achievementRepo: achievementRepo,
 		studentRepo:     studentRepo,
 		lecturerRepo:    lecturerRepo,
 		userRepo:        userRepo,
+		commentRepo:     commentRepo,
+		policy:          policy.New(studentRepo, lecturerRepo),
+		workflow:        workflow.Default(),
+		approvals:       workflow.DefaultApprovalChains(),
 		validate:        validator.New(),
 	}
 }
 
+// SetWorkflow - Ganti aturan transisi status (default: workflow.Default)
+func (s *AchievementService) SetWorkflow(machine *workflow.Machine) {
+	s.workflow = machine
+}
+
+// SetApprovalChains - Ganti rantai persetujuan (default: hanya dosen wali)
+func (s *AchievementService) SetApprovalChains(chains *workflow.ApprovalChains) {
+	s.approvals = chains
+}
+
+// SetPermissionRepository - Permission role user yang di-mention di komentar
+// (tanpa repository tidak ada @username yang dikenali sebagai mention)
+func (s *AchievementService) SetPermissionRepository(permissionRepo repository.PermissionRepository) {
+	s.permissionRepo = permissionRepo
+}
+
 //
 // ==================== CREATE ACHIEVEMENT (POST /achievements) ======================
 // FR-003: Mahasiswa dapat menambahkan laporan prestasi
@@ -108,8 +140,8 @@ func (s *AchievementService) CreateAchievement(c *fiber.Ctx) error {
 	}
 
 	if err := s.achievementRepo.CreateReference(reference); err != nil {
-		// Rollback: hapus achievement di MongoDB
-		s.achievementRepo.DeleteAchievement(mongoID)
+		// Rollback: hapus permanen achievement di MongoDB
+		s.achievementRepo.PurgeAchievement(mongoID)
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "failed to create achievement reference",
@@ -152,29 +184,39 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 		})
 	}
 
+	// Prestasi yang di-soft delete hanya terlihat oleh admin
+	actor := s.policy.Actor(claims)
+	if !actor.CanSeeAchievement(reference) {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
 	// Check authorization
-	if claims.Role == "Mahasiswa" {
-		student, _ := s.studentRepo.FindByUserID(claims.UserID)
-		if student == nil || student.ID != reference.StudentID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
-	} else if claims.Role == "Dosen Wali" {
-		lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		student, _ := s.studentRepo.FindByID(reference.StudentID)
-		if lecturer == nil || student == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
+	if !actor.CanReadAchievement(reference) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
 	}
-	// Admin dapat akses semua
 
-	// Build history
-	history := s.buildAchievementHistory(reference)
+	// Riwayat dari achievement_status_history
+	records, err := s.achievementRepo.GetStatusHistory(reference.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get achievement history",
+		})
+	}
+	comments, err := s.commentRepo.GetByAchievementRefID(reference.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get achievement history",
+		})
+	}
+	history := buildAchievementHistory(records, comments)
 
 	return c.JSON(model.APIResponse{
 		Status: "success",
@@ -191,103 +233,444 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 //
 
 type HistoryEntry struct {
-	Status    string  `json:"status"`
-	Timestamp string  `json:"timestamp"`
-	Actor     string  `json:"actor,omitempty"`
-	ActorID   *string `json:"actor_id,omitempty"`
-	Action    string  `json:"action"`
-	Notes     *string `json:"notes,omitempty"`
+	Type       string  `json:"type"`             // "status" atau "comment"
+	Status     string  `json:"status,omitempty"` // kosong untuk komentar
+	CommentID  *string `json:"comment_id,omitempty"`
+	FromStatus *string `json:"from_status,omitempty"`
+	Timestamp  string  `json:"timestamp"`
+	Actor      string  `json:"actor,omitempty"`
+	ActorID    *string `json:"actor_id,omitempty"`
+	ActorRole  string  `json:"actor_role,omitempty"`
+	OnBehalfOf *string `json:"on_behalf_of,omitempty"` // ID dosen wali yang diwakili (delegasi)
+	Stage      *string `json:"approval_stage,omitempty"` // tahap persetujuan yang diputuskan
+	Action     string  `json:"action"`
+	Notes      *string `json:"notes,omitempty"`
 }
 
-func (s *AchievementService) buildAchievementHistory(reference *model.AchievementReference) []HistoryEntry {
-	var history []HistoryEntry
+// historyActions - Label aksi berdasarkan status tujuan
+var historyActions = map[string]string{
+	"draft":     "Achievement created",
+	"submitted": "Submitted for verification",
+	"verified":  "Achievement verified",
+	"rejected":  "Achievement rejected",
+	"deleted":   "Achievement deleted",
 
-	// 1. Draft/Created
-	history = append(history, HistoryEntry{
-		Status:    "draft",
-		Timestamp: reference.CreatedAt.Format("2006-01-02 15:04:05"),
-		Action:    "Achievement created",
-		Notes:     nil,
-	})
+	"revision_requested": "Revision requested",
+}
 
-	// 2. Submitted (jika ada)
-	if reference.SubmittedAt != nil {
+// buildAchievementHistory - Transisi status dan komentar digabung, urut waktu
+func buildAchievementHistory(records []model.AchievementStatusHistory, comments []model.AchievementComment) []HistoryEntry {
+	history := make([]HistoryEntry, 0, len(records)+len(comments))
+	times := make([]time.Time, 0, len(records)+len(comments))
+
+	for _, record := range records {
+		action, ok := historyActions[record.ToStatus]
+		if !ok {
+			action = "Status changed to " + record.ToStatus
+		}
+		if record.ToStatus == model.AchievementStatusSubmitted && record.FromStatus != nil &&
+			(*record.FromStatus == model.AchievementStatusRejected || *record.FromStatus == model.AchievementStatusRevisionRequested) {
+			action = "Resubmitted after revision"
+		}
+		if record.ToStatus == model.AchievementStatusDraft && record.FromStatus != nil {
+			action = "Submission withdrawn"
+			if *record.FromStatus == model.AchievementStatusDeleted {
+				action = "Achievement restored"
+			}
+		}
+		if record.StageName != nil && record.FromStatus != nil && *record.FromStatus == record.ToStatus {
+			// Persetujuan tahap antara (status tetap submitted)
+			action = "Approved at stage " + *record.StageName
+		} else if record.StageName != nil && record.ApprovalStage != nil && *record.ApprovalStage > 0 {
+			// mis. "Achievement verified at stage kaprodi"
+			action += " at stage " + *record.StageName
+		}
+		if record.OnBehalfOf != nil {
+			// mis. "Achievement verified on behalf of Dr. Sari"
+			onBehalfOf := record.OnBehalfOfName
+			if onBehalfOf == "" {
+				onBehalfOf = "advisor"
+			}
+			action += " on behalf of " + onBehalfOf
+		}
+
+		actor := record.ActorName
+		if actor != "" && record.ActorRole != "" {
+			actor += " (" + record.ActorRole + ")"
+		}
+
+		times = append(times, record.CreatedAt)
 		history = append(history, HistoryEntry{
-			Status:    "submitted",
-			Timestamp: reference.SubmittedAt.Format("2006-01-02 15:04:05"),
-			Action:    "Submitted for verification",
-			Notes:     nil,
+			Type:       "status",
+			Status:     record.ToStatus,
+			FromStatus: record.FromStatus,
+			Timestamp:  record.CreatedAt.Format("2006-01-02 15:04:05"),
+			Actor:      actor,
+			ActorID:    record.ActorID,
+			ActorRole:  record.ActorRole,
+			OnBehalfOf: record.OnBehalfOf,
+			Stage:      record.StageName,
+			Action:     action,
+			Notes:      record.Note,
 		})
 	}
 
-	// 3. Verified (jika ada)
-	if reference.Status == "verified" && reference.VerifiedAt != nil {
-		var actorName string
-		var actorID *string
+	for _, comment := range comments {
+		action := "Comment added"
+		if comment.ParentID != nil {
+			action = "Reply added"
+		}
 
-		if reference.VerifiedBy != nil {
-			// Get verifier info
-			user, err := s.userRepo.FindByID(*reference.VerifiedBy)
-			if err == nil {
-				actorName = user.FullName + " (Dosen Wali)"
-				actorID = reference.VerifiedBy
-			}
+		actor := comment.AuthorName
+		if actor != "" && comment.AuthorRole != "" {
+			actor += " (" + comment.AuthorRole + ")"
 		}
 
+		body := comment.Body
+		commentID := comment.ID
+		times = append(times, comment.CreatedAt)
 		history = append(history, HistoryEntry{
-			Status:    "verified",
-			Timestamp: reference.VerifiedAt.Format("2006-01-02 15:04:05"),
-			Actor:     actorName,
-			ActorID:   actorID,
-			Action:    "Achievement verified",
-			Notes:     nil,
-		})
-	}
-
-	// 4. Rejected (jika ada)
-	if reference.Status == "rejected" {
-		var actorName string
-		var actorID *string
-
-		if reference.VerifiedBy != nil {
-			// Note: VerifiedBy juga dipakai untuk rejection
-			user, err := s.userRepo.FindByID(*reference.VerifiedBy)
-			if err == nil {
-				actorName = user.FullName + " (Dosen Wali)"
-				actorID = reference.VerifiedBy
+			Type:      "comment",
+			CommentID: &commentID,
+			Timestamp: comment.CreatedAt.Format("2006-01-02 15:04:05"),
+			Actor:     actor,
+			ActorID:   comment.AuthorID,
+			ActorRole: comment.AuthorRole,
+			Action:    action,
+			Notes:     &body,
+		})
+	}
+
+	// Stable: pada waktu yang sama transisi status tetap sebelum komentar
+	order := make([]int, len(history))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return times[order[a]].Before(times[order[b]])
+	})
+
+	sorted := make([]HistoryEntry, len(history))
+	for i, idx := range order {
+		sorted[i] = history[idx]
+	}
+	return sorted
+}
+
+//
+// ==================== GET ACHIEVEMENT CHANGES (GET /achievements/:id/changes) ======================
+// Perubahan isi prestasi sejak pengajuan sebelumnya, untuk dosen wali saat memeriksa revisi.
+// Selama diperiksa (submitted/verified) pembandingnya pengajuan sebelum pengajuan terakhir;
+// selama direvisi (draft/rejected/revision_requested) pembandingnya pengajuan terakhir.
+//
+
+func (s *AchievementService) GetAchievementChanges(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Get reference dari PostgreSQL
+	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Prestasi yang di-soft delete hanya terlihat oleh admin
+	actor := s.policy.Actor(claims)
+	if !actor.CanSeeAchievement(reference) {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Check authorization
+	if !actor.CanReadAchievement(reference) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	submissions, err := s.achievementRepo.GetSubmissions(reference.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get achievement submissions",
+		})
+	}
+
+	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement detail not found",
+		})
+	}
+
+	response := model.AchievementChangesResponse{
+		AchievementID:   reference.ID,
+		Status:          reference.Status,
+		SubmissionCount: reference.SubmissionCount,
+		Changes:         []model.FieldChange{},
+	}
+
+	base := len(submissions) - 1
+	if reference.Status == model.AchievementStatusSubmitted || reference.Status == model.AchievementStatusVerified {
+		base--
+	}
+	if base >= 0 {
+		previous := submissions[base]
+		response.ComparedToSubmission = &previous.SubmissionNumber
+		response.Changes = diffSnapshots(&previous.Snapshot, model.NewAchievementSnapshot(achievement))
+
+		// Alasan penolakan / permintaan revisi pengajuan pembanding
+		// (catatan di reference bisa sudah lebih baru)
+		response.PreviousRejectionNote = rejectionNoteAfter(s.rejectionNotes(reference.ID), previous.CreatedAt)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   response,
+	})
+}
+
+// rejectionNotes - Riwayat penolakan dan permintaan revisi (error diabaikan, hanya pelengkap)
+func (s *AchievementService) rejectionNotes(refID string) []model.AchievementStatusHistory {
+	records, err := s.achievementRepo.GetStatusHistory(refID)
+	if err != nil {
+		return nil
+	}
+
+	var rejections []model.AchievementStatusHistory
+	for _, record := range records {
+		if record.ToStatus == model.AchievementStatusRejected || record.ToStatus == model.AchievementStatusRevisionRequested {
+			rejections = append(rejections, record)
+		}
+	}
+	return rejections
+}
+
+// rejectionNoteAfter - Catatan penolakan pertama setelah waktu pengajuan
+func rejectionNoteAfter(rejections []model.AchievementStatusHistory, submittedAt time.Time) *string {
+	for _, rejection := range rejections {
+		if !rejection.CreatedAt.Before(submittedAt) {
+			return rejection.Note
+		}
+	}
+	return nil
+}
+
+//
+// ==================== HELPER: DIFF SNAPSHOTS ======================
+// Bandingkan dua snapshot lewat bentuk JSON-nya supaya tipe dari MongoDB
+// (primitive.A, int32, ...) dan dari JSONB (float64, []interface{}) setara.
+// Details dibandingkan per key ("details.<key>").
+//
+
+func diffSnapshots(previous, current *model.AchievementSnapshot) []model.FieldChange {
+	prev, curr := snapshotFields(previous), snapshotFields(current)
+	changes := []model.FieldChange{}
+
+	fields := []string{"achievement_type", "title", "description", "points", "tags", "attachments"}
+	for _, field := range fields {
+		if !reflect.DeepEqual(prev[field], curr[field]) {
+			changes = append(changes, model.FieldChange{Field: field, Previous: prev[field], Current: curr[field]})
+		}
+	}
+
+	prevDetails, _ := prev["details"].(map[string]interface{})
+	currDetails, _ := curr["details"].(map[string]interface{})
+	keys := make([]string, 0, len(prevDetails)+len(currDetails))
+	for key := range prevDetails {
+		keys = append(keys, key)
+	}
+	for key := range currDetails {
+		if _, ok := prevDetails[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !reflect.DeepEqual(prevDetails[key], currDetails[key]) {
+			changes = append(changes, model.FieldChange{Field: "details." + key, Previous: prevDetails[key], Current: currDetails[key]})
+		}
+	}
+
+	return changes
+}
+
+func snapshotFields(snapshot *model.AchievementSnapshot) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(data, &fields)
+	return fields
+}
+
+//
+// ==================== HELPER: CHANGE STATUS ======================
+// Ubah status reference dan catat transisinya di achievement_status_history.
+// Field lain di reference (submitted_at, verified_by, dst.) diisi Effect workflow;
+// actor, catatan dan on_behalf_of riwayat diambil dari context workflow yang sama.
+//
+
+func (s *AchievementService) changeStatus(wctx *workflow.Context, toStatus string) error {
+	reference := wctx.Reference
+	fromStatus := reference.Status
+	reference.Status = toStatus
+
+	if err := s.achievementRepo.TransitionReference(reference, statusEntry(fromStatus, wctx)); err != nil {
+		reference.Status = fromStatus
+		return err
+	}
+	return nil
+}
+
+// submitReference - changeStatus ke 'submitted' + simpan snapshot isi prestasi
+// sebagai pengajuan ke-reference.SubmissionCount
+func (s *AchievementService) submitReference(wctx *workflow.Context, toStatus string, achievement *model.Achievement) error {
+	reference := wctx.Reference
+	fromStatus := reference.Status
+	reference.Status = toStatus
+
+	snapshot := model.NewAchievementSnapshot(achievement)
+	if err := s.achievementRepo.SubmitReference(reference, statusEntry(fromStatus, wctx), snapshot); err != nil {
+		reference.Status = fromStatus
+		return err
+	}
+	return nil
+}
+
+func statusEntry(fromStatus string, wctx *workflow.Context) *model.AchievementStatusHistory {
+	actorID := wctx.Claims.UserID
+	entry := &model.AchievementStatusHistory{
+		FromStatus: &fromStatus,
+		ActorID:    &actorID,
+		ActorRole:  wctx.Claims.Role,
+		OnBehalfOf: wctx.OnBehalfOf,
+		Note:       wctx.Note,
+	}
+	if wctx.Stage != nil {
+		// Tahap yang diputuskan; sekaligus syarat update di repository
+		stageIndex, stageName := wctx.StageIndex, wctx.Stage.Name
+		entry.ApprovalStage = &stageIndex
+		entry.StageName = &stageName
+	} else if wctx.RequiredStage != nil {
+		// Tahap pengajuan saat aksi terjadi, tanpa nama tahap (bukan keputusan persetujuan)
+		entry.ApprovalStage = wctx.RequiredStage
+	}
+	return entry
+}
+
+func (s *AchievementService) workflowContext(reference *model.AchievementReference, claims *model.JWTClaims, note *string) *workflow.Context {
+	return &workflow.Context{
+		Reference: reference,
+		Actor:     s.policy.Actor(claims),
+		Claims:    claims,
+		Note:      note,
+		Chain:     s.approvals.Chain(reference.ApprovalChain),
+	}
+}
+
+// loadEarlierDeciders - Isi wctx.EarlierDeciders dari riwayat pengajuan saat ini: actor dan
+// dosen wali yang diwakili pada setiap persetujuan tahap sebelum tahap reference sekarang
+func (s *AchievementService) loadEarlierDeciders(wctx *workflow.Context) error {
+	reference := wctx.Reference
+	if reference.Status != model.AchievementStatusSubmitted || reference.ApprovalStage == 0 {
+		return nil
+	}
+
+	records, err := s.achievementRepo.GetStatusHistory(reference.ID)
+	if err != nil {
+		return err
+	}
+
+	var deciders []string
+	for _, record := range records {
+		switch {
+		case record.ToStatus == model.AchievementStatusSubmitted &&
+			(record.FromStatus == nil || *record.FromStatus != model.AchievementStatusSubmitted):
+			// Pengajuan (ulang): persetujuan pengajuan sebelumnya tidak dihitung
+			deciders = nil
+		case record.ToStatus == model.AchievementStatusSubmitted && record.ApprovalStage != nil &&
+			*record.ApprovalStage < reference.ApprovalStage:
+			if record.ActorID != nil {
+				deciders = append(deciders, *record.ActorID)
+			}
+			if record.OnBehalfOf != nil {
+				deciders = append(deciders, *record.OnBehalfOf)
 			}
 		}
+	}
 
-		history = append(history, HistoryEntry{
-			Status:    "rejected",
-			Timestamp: reference.UpdatedAt.Format("2006-01-02 15:04:05"),
-			Actor:     actorName,
-			ActorID:   actorID,
-			Action:    "Achievement rejected",
-			Notes:     reference.RejectionNote,
+	wctx.EarlierDeciders = deciders
+	return nil
+}
+
+// workflowError - 403 jika actor tidak berhak, 409 + status berikutnya yang valid
+// jika aksi tidak berlaku untuk status saat ini
+func workflowError(c *fiber.Ctx, err error) error {
+	var forbidden *workflow.ForbiddenError
+	if errors.As(err, &forbidden) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  forbidden.Message,
 		})
 	}
 
-	// 5. Deleted (jika ada)
-	if reference.Status == "deleted" {
-		history = append(history, HistoryEntry{
-			Status:    "deleted",
-			Timestamp: reference.UpdatedAt.Format("2006-01-02 15:04:05"),
-			Action:    "Achievement deleted",
-			Notes:     nil,
+	var invalid *workflow.TransitionError
+	if errors.As(err, &invalid) {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  invalid.Error(),
+			Code:   model.ErrCodeInvalidTransition,
+			Data: fiber.Map{
+				"current_status":      invalid.Status,
+				"allowed_next_states": invalid.Allowed,
+			},
 		})
 	}
 
-	return history
+	return c.Status(500).JSON(model.APIResponse{
+		Status: "error",
+		Error:  err.Error(),
+	})
 }
 
+// statusChangeError - 409 jika status sudah diubah request lain, selain itu 500
+func statusChangeError(c *fiber.Ctx, err error, message string) error {
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement status was changed by another request, please reload",
+		})
+	}
+	return c.Status(500).JSON(model.APIResponse{
+		Status: "error",
+		Error:  message,
+	})
+}
 
 //
 // ==================== GET ACHIEVEMENTS (GET /achievements) ======================
-// Filtered by role:
-// - Mahasiswa: hanya prestasi sendiri
-// - Dosen Wali: prestasi mahasiswa bimbingannya
-// - Admin: semua prestasi
+// Filtered by access scope (app/policy):
+// - profil mahasiswa: hanya prestasi sendiri
+// - profil dosen: prestasi mahasiswa bimbingannya (+ satu program studi dengan achievement:read_department)
+// - achievement:read_all: semua prestasi
 //
 
 func (s *AchievementService) GetAchievements(c *fiber.Ctx) error {
@@ -318,83 +701,31 @@ func (s *AchievementService) GetAchievements(c *fiber.Ctx) error {
 	var total int
 	var err error
 
-	// Filter berdasarkan role
-	if claims.Role == "Mahasiswa" {
-		// Get student profile
-		student, err := s.studentRepo.FindByUserID(claims.UserID)
-		if err != nil {
-			return c.Status(404).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "student profile not found",
-			})
-		}
-
-		references, err = s.achievementRepo.GetReferencesByStudentID(student.ID, status, pageSize, offset)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to fetch achievements",
-			})
-		}
-
-		total, err = s.achievementRepo.CountReferencesByStudentID(student.ID, status)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to count achievements",
-			})
-		}
-
-	} else if claims.Role == "Dosen Wali" {
-		// Get lecturer profile
-		lecturer, err := s.lecturerRepo.FindByUserID(claims.UserID)
-		if err != nil {
-			return c.Status(404).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "lecturer profile not found",
-			})
-		}
-
-		references, err = s.achievementRepo.GetReferencesByAdvisorID(lecturer.ID, status, pageSize, offset)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to fetch achievements",
-			})
-		}
-
-		total, err = s.achievementRepo.CountReferencesByAdvisorID(lecturer.ID, status)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to count achievements",
-			})
-		}
-
-	} else if claims.Role == "Admin" {
-		// Admin dapat melihat semua
-		references, err = s.achievementRepo.GetAllReferences(status, pageSize, offset)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to fetch achievements",
-			})
-		}
-
-		total, err = s.achievementRepo.CountAllReferences(status)
-		if err != nil {
-			return c.Status(500).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "failed to count achievements",
-			})
-		}
-	} else {
+	// Filter berdasarkan jangkauan akses actor
+	scope := s.policy.Actor(claims).ReadScope()
+	if scope.Kind == model.ScopeNone {
 		return c.Status(403).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "forbidden",
 		})
 	}
 
+	references, err = s.achievementRepo.GetReferencesByScope(scope, status, pageSize, offset)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch achievements",
+		})
+	}
+
+	total, err = s.achievementRepo.CountReferencesByScope(scope, status)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to count achievements",
+		})
+	}
+
 	// Fetch details dari MongoDB
 	var achievements []model.AchievementResponse
 	for _, ref := range references {
@@ -448,26 +779,22 @@ func (s *AchievementService) GetAchievementByID(c *fiber.Ctx) error {
 		})
 	}
 
+	// Prestasi yang di-soft delete hanya terlihat oleh admin
+	actor := s.policy.Actor(claims)
+	if !actor.CanSeeAchievement(reference) {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
 	// Check authorization
-	if claims.Role == "Mahasiswa" {
-		student, _ := s.studentRepo.FindByUserID(claims.UserID)
-		if student == nil || student.ID != reference.StudentID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
-	} else if claims.Role == "Dosen Wali" {
-		lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		student, _ := s.studentRepo.FindByID(reference.StudentID)
-		if lecturer == nil || student == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden",
-			})
-		}
+	if !actor.CanReadAchievement(reference) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
 	}
-	// Admin dapat akses semua
 
 	// Get detail dari MongoDB
 	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
@@ -488,7 +815,8 @@ func (s *AchievementService) GetAchievementByID(c *fiber.Ctx) error {
 
 //
 // ==================== UPDATE ACHIEVEMENT (PUT /achievements/:id) ======================
-// Hanya mahasiswa pemilik yang bisa update, dan hanya jika status = draft
+// Hanya mahasiswa pemilik yang bisa update, dan hanya jika status = draft, rejected atau
+// revision_requested (revisi)
 //
 
 func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
@@ -512,21 +840,9 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization (hanya mahasiswa pemilik)
-	student, _ := s.studentRepo.FindByUserID(claims.UserID)
-	if student == nil || student.ID != reference.StudentID {
-		return c.Status(403).JSON(model.APIResponse{
-			Status: "error",
-			Error:  "forbidden",
-		})
-	}
-
-	// Hanya bisa update jika status = draft
-	if reference.Status != "draft" {
-		return c.Status(400).JSON(model.APIResponse{
-			Status: "error",
-			Error:  "can only update achievement with status 'draft'",
-		})
+	// Check authorization + status (hanya mahasiswa pemilik, status = draft/rejected/revision_requested)
+	if _, err := s.workflow.Apply(workflow.ActionEdit, s.workflowContext(reference, claims, nil)); err != nil {
+		return workflowError(c, err)
 	}
 
 	// Parse request
@@ -588,9 +904,10 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 // ==================== DELETE ACHIEVEMENT (DELETE /achievements/:id) ======================
 // FR-005: Mahasiswa dapat menghapus prestasi draft
 // Flow SRS:
-// 1. Soft delete data di MongoDB
-// 2. Update reference di PostgreSQL
+// 1. Soft delete data di MongoDB (deletedAt diisi)
+// 2. Update reference di PostgreSQL (status 'deleted', deleted_at diisi)
 // 3. Return success message
+// Data baru dihapus permanen oleh AchievementPurgeService setelah masa retensi.
 //
 
 func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
@@ -614,45 +931,157 @@ func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization (hanya mahasiswa pemilik)
-	student, _ := s.studentRepo.FindByUserID(claims.UserID)
-	if student == nil || student.ID != reference.StudentID {
-		return c.Status(403).JSON(model.APIResponse{
+	// Check authorization + precondition (hanya mahasiswa pemilik, status = draft)
+	wctx := s.workflowContext(reference, claims, nil)
+	transition, err := s.workflow.Apply(workflow.ActionDelete, wctx)
+	if err != nil {
+		return workflowError(c, err)
+	}
+
+	// FR-005: Soft delete sesuai SRS
+	// 1. Soft delete data di MongoDB
+	if err := s.achievementRepo.DeleteAchievement(reference.MongoAchievementID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "forbidden",
+			Error:  "failed to delete achievement from MongoDB",
 		})
 	}
 
-	// Precondition: Hanya bisa delete jika status = draft
-	if reference.Status != "draft" {
-		return c.Status(400).JSON(model.APIResponse{
+	// 2. Update reference di PostgreSQL dengan status 'deleted'
+	if err := s.changeStatus(wctx, transition.To); err != nil {
+		// Rollback: reference tetap hidup (mis. diajukan bersamaan), batalkan soft delete MongoDB
+		s.achievementRepo.RestoreAchievement(reference.MongoAchievementID)
+		return statusChangeError(c, err, "failed to update reference status")
+	}
+
+	// 3. Return success message
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "achievement deleted successfully",
+	})
+}
+
+//
+// ==================== GET DELETED ACHIEVEMENTS (GET /achievements/deleted) ======================
+// Admin: daftar prestasi yang di-soft delete dan belum di-purge, terbaru dihapus dulu
+//
+
+func (s *AchievementService) GetDeletedAchievements(c *fiber.Ctx) error {
+	// Parse query params
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	references, err := s.achievementRepo.GetDeletedReferences(pageSize, offset)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "can only delete achievement with status 'draft'",
+			Error:  "failed to fetch deleted achievements",
 		})
 	}
 
-	// FR-005: Soft delete sesuai SRS
-	// 1. Soft delete data di MongoDB
-	if err := s.achievementRepo.DeleteAchievement(reference.MongoAchievementID); err != nil {
+	total, err := s.achievementRepo.CountDeletedReferences()
+	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to delete achievement from MongoDB",
+			Error:  "failed to count deleted achievements",
 		})
 	}
 
-	// 2. Update reference di PostgreSQL dengan status 'deleted'
-	reference.Status = "deleted"
-	if err := s.achievementRepo.UpdateReference(reference); err != nil {
+	// Fetch details dari MongoDB
+	achievements := []model.AchievementResponse{}
+	for _, ref := range references {
+		achievement, err := s.achievementRepo.GetAchievementByID(ref.MongoAchievementID)
+		if err != nil {
+			continue // Skip jika tidak ditemukan
+		}
+
+		response := s.buildAchievementResponse(achievement, &ref, ref.MongoAchievementID)
+		achievements = append(achievements, *response)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(pageSize)))
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: model.AchievementListResponse{
+			Achievements: achievements,
+			Total:        total,
+			Page:         page,
+			PageSize:     pageSize,
+			TotalPages:   totalPages,
+		},
+	})
+}
+
+//
+// ==================== RESTORE ACHIEVEMENT (POST /achievements/:id/restore) ======================
+// Admin: kembalikan prestasi yang di-soft delete ke draft milik mahasiswa
+//
+
+func (s *AchievementService) RestoreAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Get reference
+	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
+
+	// Check authorization + status (user:manage, status = deleted); mengosongkan deleted_at
+	wctx := s.workflowContext(reference, claims, nil)
+	transition, err := s.workflow.Apply(workflow.ActionRestore, wctx)
+	if err != nil {
+		return workflowError(c, err)
+	}
+
+	// 1. Batalkan soft delete di MongoDB
+	if err := s.achievementRepo.RestoreAchievement(reference.MongoAchievementID); err != nil {
+		if errors.Is(err, repository.ErrAchievementNotFound) {
+			return c.Status(404).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "achievement detail not found",
+			})
+		}
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to update reference status",
+			Error:  "failed to restore achievement in MongoDB",
 		})
 	}
 
-	// 3. Return success message
+	// 2. Update reference di PostgreSQL kembali menjadi 'draft'
+	if err := s.changeStatus(wctx, transition.To); err != nil {
+		// Rollback: reference masih deleted, tandai lagi dokumen MongoDB sebagai terhapus
+		s.achievementRepo.DeleteAchievement(reference.MongoAchievementID)
+		return statusChangeError(c, err, "failed to restore achievement")
+	}
+
 	return c.JSON(model.APIResponse{
 		Status:  "success",
-		Message: "achievement deleted successfully",
+		Message: "achievement restored to draft",
+		Data: fiber.Map{
+			"status": reference.Status,
+		},
 	})
 }
 
@@ -682,51 +1111,51 @@ func (s *AchievementService) SubmitForVerification(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization
-	student, _ := s.studentRepo.FindByUserID(claims.UserID)
-	if student == nil || student.ID != reference.StudentID {
-		return c.Status(403).JSON(model.APIResponse{
-			Status: "error",
-			Error:  "forbidden",
-		})
+	// Check authorization + status (hanya pemilik, status = draft/rejected/revision_requested);
+	// mengisi submitted_at dan menaikkan submission_count
+	wctx := s.workflowContext(reference, claims, nil)
+	transition, err := s.workflow.Apply(workflow.ActionSubmit, wctx)
+	if err != nil {
+		return workflowError(c, err)
 	}
 
-	// Hanya bisa submit jika status = draft
-	if reference.Status != "draft" {
-		return c.Status(400).JSON(model.APIResponse{
+	// Isi prestasi saat ini, disimpan sebagai snapshot pengajuan
+	achievement, err := s.achievementRepo.GetAchievementByID(reference.MongoAchievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "achievement already submitted or processed",
+			Error:  "achievement detail not found",
 		})
 	}
 
-	// Update status menjadi 'submitted'
-	now := time.Now()
-	reference.Status = "submitted"
-	reference.SubmittedAt = &now
+	// Rantai persetujuan dipilih dari isi prestasi saat diajukan (tipe, poin, tingkat kompetisi)
+	chain := s.approvals.ChainFor(achievement)
+	reference.ApprovalChain = &chain.Name
 
-	if err := s.achievementRepo.UpdateReference(reference); err != nil {
-		return c.Status(500).JSON(model.APIResponse{
-			Status: "error",
-			Error:  "failed to submit achievement",
-		})
+	// Update status menjadi 'submitted'
+	if err := s.submitReference(wctx, transition.To, achievement); err != nil {
+		return statusChangeError(c, err, "failed to submit achievement")
 	}
 
 	return c.JSON(model.APIResponse{
 		Status:  "success",
 		Message: "achievement submitted for verification",
 		Data: fiber.Map{
-			"status":       reference.Status,
-			"submitted_at": reference.SubmittedAt.Format("2006-01-02 15:04:05"),
+			"status":           reference.Status,
+			"submitted_at":     reference.SubmittedAt.Format("2006-01-02 15:04:05"),
+			"submission_count": reference.SubmissionCount,
+			"approval":         chain.Progress(reference),
 		},
 	})
 }
 
 //
-// ==================== VERIFY ACHIEVEMENT (POST /achievements/:id/verify) ======================
-// FR-007: Dosen wali memverifikasi prestasi mahasiswa
+// ==================== WITHDRAW SUBMISSION (POST /achievements/:id/withdraw) ======================
+// Mahasiswa menarik kembali pengajuan ke draft selama belum mulai direview
+// (belum ada tahap persetujuan yang diputuskan dan belum ada komentar reviewer)
 //
 
-func (s *AchievementService) VerifyAchievement(c *fiber.Ctx) error {
+func (s *AchievementService) WithdrawSubmission(c *fiber.Ctx) error {
 	achievementID := c.Params("id")
 
 	// Get user dari context
@@ -746,36 +1175,126 @@ func (s *AchievementService) VerifyAchievement(c *fiber.Ctx) error {
 			Error:  "achievement not found",
 		})
 	}
+	submittedAt := reference.SubmittedAt
 
-	// Check authorization (hanya dosen wali dari mahasiswa tersebut)
-	lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-	student, _ := s.studentRepo.FindByID(reference.StudentID)
+	// Check authorization + status (hanya pemilik, status = submitted); mengosongkan submitted_at
+	wctx := s.workflowContext(reference, claims, nil)
+	transition, err := s.workflow.Apply(workflow.ActionWithdraw, wctx)
+	if err != nil {
+		return workflowError(c, err)
+	}
 
-	if lecturer == nil || student == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-		return c.Status(403).JSON(model.APIResponse{
+	started, err := s.reviewStarted(reference, submittedAt)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "forbidden: you are not the advisor of this student",
+			Error:  "failed to check review progress",
+		})
+	}
+	if started {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement is already under review and can no longer be withdrawn",
 		})
 	}
 
-	// Hanya bisa verify jika status = submitted
-	if reference.Status != "submitted" {
-		return c.Status(400).JSON(model.APIResponse{
+	// Update status kembali menjadi 'draft'
+	if err := s.changeStatus(wctx, transition.To); err != nil {
+		return statusChangeError(c, err, "failed to withdraw achievement")
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "submission withdrawn, achievement is back to draft",
+		Data: fiber.Map{
+			"status":           reference.Status,
+			"submission_count": reference.SubmissionCount,
+		},
+	})
+}
+
+// reviewStarted - Pengajuan sudah diambil reviewer: ada tahap persetujuan yang
+// sudah diputuskan, atau ada komentar selain dari mahasiswa pemilik sejak diajukan
+func (s *AchievementService) reviewStarted(reference *model.AchievementReference, submittedAt *time.Time) (bool, error) {
+	if reference.ApprovalStage > 0 {
+		return true, nil
+	}
+
+	comments, err := s.commentRepo.GetByAchievementRefID(reference.ID)
+	if err != nil {
+		return false, err
+	}
+	return hasReviewerComment(comments, reference.StudentID, submittedAt), nil
+}
+
+func hasReviewerComment(comments []model.AchievementComment, ownerID string, since *time.Time) bool {
+	for _, comment := range comments {
+		byOwner := comment.AuthorID != nil && *comment.AuthorID == ownerID
+		if !byOwner && (since == nil || !comment.CreatedAt.Before(*since)) {
+			return true
+		}
+		if hasReviewerComment(comment.Replies, ownerID, since) {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// ==================== VERIFY ACHIEVEMENT (POST /achievements/:id/verify) ======================
+// FR-007: Dosen wali memverifikasi prestasi mahasiswa.
+// Di rantai persetujuan bertahap, approver tahap selain terakhir hanya memajukan
+// tahap (status tetap submitted); tahap terakhir mengubah status menjadi verified.
+//
+
+func (s *AchievementService) VerifyAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "achievement must be in 'submitted' status",
+			Error:  "unauthorized",
 		})
 	}
 
-	// Update status menjadi 'verified'
-	now := time.Now()
-	reference.Status = "verified"
-	reference.VerifiedAt = &now
-	reference.VerifiedBy = &claims.UserID
+	// Get reference
+	reference, err := s.achievementRepo.GetReferenceByID(achievementID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "achievement not found",
+		})
+	}
 
-	if err := s.achievementRepo.UpdateReference(reference); err != nil {
+	// Check authorization + status (approver tahap saat ini, status = submitted);
+	// tahap terakhir mengisi verified_at/by, tahap lain memajukan approval_stage
+	wctx := s.workflowContext(reference, claims, nil)
+	if err := s.loadEarlierDeciders(wctx); err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to verify achievement",
+			Error:  "failed to get achievement history",
+		})
+	}
+	transition, err := s.workflow.Apply(workflow.VerifyAction(wctx), wctx)
+	if err != nil {
+		return workflowError(c, err)
+	}
+
+	// Update status menjadi 'verified' (atau tetap 'submitted' di tahap berikutnya)
+	if err := s.changeStatus(wctx, transition.To); err != nil {
+		return statusChangeError(c, err, "failed to verify achievement")
+	}
+
+	if transition.Action == workflow.ActionApprove {
+		return c.JSON(model.APIResponse{
+			Status:  "success",
+			Message: "stage '" + wctx.Stage.Name + "' approved, waiting for the next approval stage",
+			Data: fiber.Map{
+				"status":   reference.Status,
+				"approval": wctx.Chain.Progress(reference),
+			},
 		})
 	}
 
@@ -833,34 +1352,22 @@ func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization
-	lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-	student, _ := s.studentRepo.FindByID(reference.StudentID)
-
-	if lecturer == nil || student == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-		return c.Status(403).JSON(model.APIResponse{
+	// Check authorization + status (approver tahap saat ini, status = submitted); mengisi rejection_note
+	wctx := s.workflowContext(reference, claims, &req.RejectionNote)
+	if err := s.loadEarlierDeciders(wctx); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "forbidden: you are not the advisor of this student",
+			Error:  "failed to get achievement history",
 		})
 	}
-
-	// Hanya bisa reject jika status = submitted
-	if reference.Status != "submitted" {
-		return c.Status(400).JSON(model.APIResponse{
-			Status: "error",
-			Error:  "achievement must be in 'submitted' status",
-		})
+	transition, err := s.workflow.Apply(workflow.ActionReject, wctx)
+	if err != nil {
+		return workflowError(c, err)
 	}
 
 	// Update status menjadi 'rejected'
-	reference.Status = "rejected"
-	reference.RejectionNote = &req.RejectionNote
-
-	if err := s.achievementRepo.UpdateReference(reference); err != nil {
-		return c.Status(500).JSON(model.APIResponse{
-			Status: "error",
-			Error:  "failed to reject achievement",
-		})
+	if err := s.changeStatus(wctx, transition.To); err != nil {
+		return statusChangeError(c, err, "failed to reject achievement")
 	}
 
 	return c.JSON(model.APIResponse{
@@ -901,21 +1408,9 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check authorization (hanya mahasiswa pemilik)
-	student, _ := s.studentRepo.FindByUserID(claims.UserID)
-	if student == nil || student.ID != reference.StudentID {
-		return c.Status(403).JSON(model.APIResponse{
-			Status: "error",
-			Error:  "forbidden",
-		})
-	}
-
-	// Hanya bisa upload jika status = draft atau submitted
-	if reference.Status != "draft" && reference.Status != "submitted" {
-		return c.Status(400).JSON(model.APIResponse{
-			Status: "error",
-			Error:  "can only upload attachments for draft or submitted achievements",
-		})
+	// Check authorization + status (hanya mahasiswa pemilik, status = draft atau submitted)
+	if _, err := s.workflow.Apply(workflow.ActionAttach, s.workflowContext(reference, claims, nil)); err != nil {
+		return workflowError(c, err)
 	}
 
 	// Parse multipart file
@@ -1044,6 +1539,7 @@ func (s *AchievementService) buildAchievementResponse(
 		Tags:            achievement.Tags,
 		Points:          achievement.Points,
 		Status:          reference.Status,
+		SubmissionCount: reference.SubmissionCount,
 		CreatedAt:       achievement.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:       achievement.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
@@ -1061,5 +1557,15 @@ func (s *AchievementService) buildAchievementResponse(
 	response.VerifiedBy = reference.VerifiedBy
 	response.RejectionNote = reference.RejectionNote
 
+	if reference.DeletedAt != nil {
+		deletedAt := reference.DeletedAt.Format("2006-01-02 15:04:05")
+		response.DeletedAt = &deletedAt
+	}
+
+	// Posisi di rantai persetujuan, hanya untuk prestasi yang pernah diajukan
+	if reference.ApprovalChain != nil {
+		response.Approval = s.approvals.Chain(reference.ApprovalChain).Progress(reference)
+	}
+
 	return response
 }
\ No newline at end of file
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"UASBE/app/model"
+	"UASBE/app/repository"
+	"UASBE/utils"
+)
+
+type APITokenService struct {
+	tokenRepo repository.APITokenRepository
+	validate  *validator.Validate
+}
+
+func NewAPITokenService(tokenRepo repository.APITokenRepository) *APITokenService {
+	return &APITokenService{
+		tokenRepo: tokenRepo,
+		validate:  validator.New(),
+	}
+}
+
+//
+// ==================== GET API TOKENS (GET /auth/tokens) ======================
+// Token asli tidak pernah ditampilkan lagi, hanya prefix-nya
+//
+
+func (s *APITokenService) GetTokens(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	tokens, err := s.tokenRepo.GetByUserID(claims.UserID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get api tokens",
+		})
+	}
+
+	res := make([]model.APITokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		res = append(res, toAPITokenResponse(token))
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   res,
+	})
+}
+
+//
+// ==================== CREATE API TOKEN (POST /auth/tokens) ======================
+// Scopes harus subset permission user saat ini. Token asli hanya dikirim sekali.
+//
+
+func (s *APITokenService) CreateToken(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	req := new(model.APITokenCreateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	owned := make(map[string]bool, len(claims.Permissions))
+	for _, perm := range claims.Permissions {
+		owned[perm] = true
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !owned[scope] {
+			return c.Status(400).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "scope not allowed: " + scope,
+			})
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	raw, prefix, hash, err := utils.GenerateAPIToken()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate api token",
+		})
+	}
+
+	token := &model.APIToken{
+		UserID:      claims.UserID,
+		Name:        req.Name,
+		TokenHash:   hash,
+		TokenPrefix: prefix,
+		Scopes:      scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create api token",
+		})
+	}
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "api token created, copy it now because it will not be shown again",
+		Data: model.APITokenCreatedResponse{
+			APITokenResponse: toAPITokenResponse(*token),
+			Token:            raw,
+		},
+	})
+}
+
+//
+// ==================== REVOKE API TOKEN (DELETE /auth/tokens/:id) ======================
+//
+
+func (s *APITokenService) RevokeToken(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	// token milik user lain diperlakukan sama dengan token yang tidak ada
+	token, err := s.tokenRepo.FindByID(c.Params("id"))
+	if err != nil || token.UserID != claims.UserID || token.RevokedAt != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "api token not found",
+		})
+	}
+
+	if err := s.tokenRepo.Revoke(token.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke api token",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "api token revoked",
+	})
+}
+
+//
+// ==================== HELPER: TO API TOKEN RESPONSE ======================
+//
+
+func toAPITokenResponse(token model.APIToken) model.APITokenResponse {
+	res := model.APITokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.Scopes,
+		CreatedAt:   token.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if token.ExpiresAt != nil {
+		res.ExpiresAt = token.ExpiresAt.Format("2006-01-02 15:04:05")
+	}
+	if token.LastUsedAt != nil {
+		res.LastUsedAt = token.LastUsedAt.Format("2006-01-02 15:04:05")
+	}
+	return res
+}
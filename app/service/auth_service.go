@@ -1,29 +1,76 @@
 package service
 
 import (
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
+	"UASBE/app/identity"
 	"UASBE/app/model"
 	"UASBE/app/repository"
+	"UASBE/config"
 	"UASBE/utils"
 )
 
 type AuthService struct {
-	userRepo repository.UserRepository
-	roleRepo repository.RoleRepository
-	permRepo repository.PermissionRepository
+	userRepo    repository.UserRepository
+	roleRepo    repository.RoleRepository
+	permRepo    repository.PermissionRepository
+	refreshRepo repository.RefreshTokenRepository
+	revokeRepo  repository.TokenRevocationRepository
+	resetRepo   repository.PasswordResetRepository
+	mfaRepo     repository.MFARepository
+	sessionRepo repository.SessionRepository
+
+	// identity provider eksternal (opsional, lihat SetIdentityProviders)
+	provisioner      *identity.Provisioner
+	passwordProvider identity.PasswordProvider
+	redirectProvider identity.RedirectProvider
 }
 
+// oidcStateCookie - Cookie berisi state + nonce selama redirect ke IdP
+const (
+	oidcStateCookie = "oidc_state"
+	oidcStateTTL    = 10 * time.Minute
+)
+
 func NewAuthService(
 	user repository.UserRepository,
 	role repository.RoleRepository,
 	perm repository.PermissionRepository,
+	refresh repository.RefreshTokenRepository,
+	revoke repository.TokenRevocationRepository,
+	reset repository.PasswordResetRepository,
+	mfa repository.MFARepository,
+	session repository.SessionRepository,
 ) *AuthService {
 	return &AuthService{
-		userRepo: user,
-		roleRepo: role,
-		permRepo: perm,
+		userRepo:    user,
+		roleRepo:    role,
+		permRepo:    perm,
+		refreshRepo: refresh,
+		revokeRepo:  revoke,
+		resetRepo:   reset,
+		mfaRepo:     mfa,
+		sessionRepo: session,
+	}
+}
+
+// SetIdentityProviders - Aktifkan login lewat direktori kampus. Provider LDAP
+// (PasswordProvider) dipakai oleh POST /auth/login, provider OIDC
+// (RedirectProvider) oleh /auth/oidc/*. Tanpa provider hanya login lokal.
+func (s *AuthService) SetIdentityProviders(provisioner *identity.Provisioner, providers ...identity.Provider) {
+	s.provisioner = provisioner
+	for _, provider := range providers {
+		if p, ok := provider.(identity.PasswordProvider); ok {
+			s.passwordProvider = p
+		}
+		if p, ok := provider.(identity.RedirectProvider); ok {
+			s.redirectProvider = p
+		}
 	}
 }
 
@@ -45,119 +92,262 @@ func (s *AuthService) Login(c *fiber.Ctx) error {
 	// cek username
 	user, err := s.userRepo.FindByUsername(req.Username)
 	if err != nil {
+		// belum ada user lokal: mungkin akun direktori yang belum pernah login
+		if s.passwordProvider != nil {
+			return s.loginWithDirectory(c, req, nil)
+		}
 		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "invalid username or password",
+			Code:   model.ErrCodeInvalidCredentials,
 		})
 	}
 
-	// cek password
-	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
+	// akun terkunci: tolak sebelum bcrypt supaya brute-force tidak menghabiskan CPU
+	if user.AccountStatus() == model.AccountStatusLocked {
+		return s.rejectAccountStatus(c, user)
+	}
+
+	// cek password (user hasil provisioning direktori tidak punya password lokal)
+	if user.PasswordHash == "" || !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
+		if s.passwordProvider != nil {
+			return s.loginWithDirectory(c, req, user)
+		}
+		if s.recordFailedLogin(user) {
+			return s.rejectAccountStatus(c, user)
+		}
 		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "invalid username or password",
+			Code:   model.ErrCodeInvalidCredentials,
 		})
 	}
 
-	// ambil role
-	role, _ := s.roleRepo.GetRoleByID(user.RoleID)
+	return s.continueLogin(c, user)
+}
 
-	// ambil permission by role
-	perms, _ := s.permRepo.GetPermissionsByRoleID(role.ID)
+//
+// ==================== OIDC LOGIN (GET /auth/oidc/login) ======================
+// Redirect ke halaman login IdP kampus (authorization code flow)
+//
 
-	// response user
-userRes := model.UserResponse{
-    ID:          user.ID,
-    Username:    user.Username,
-    Email:       user.Email,
-    FullName:    user.FullName,
-    Role:        role.Name,
-    IsActive:    user.IsActive,  // ⭐ SUDAH ADA
-    CreatedAt:   user.CreatedAt.Format("2006-01-02 15:04:05"), // ⭐ TAMBAHKAN FORMAT
-    Permissions: perms,
-}
+func (s *AuthService) OIDCLogin(c *fiber.Ctx) error {
+	if s.redirectProvider == nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "OIDC login is not configured",
+		})
+	}
 
-	// generate token
-	access, _ := utils.GenerateJWT(userRes)
-	refresh, _ := utils.GenerateRefreshToken(user.ID)
+	state := uuid.New().String()
+	nonce := uuid.New().String()
 
-	return c.JSON(model.APIResponse{
-		Status: "success",
-		Data: model.LoginResponse{
-			Token:        access,
-			RefreshToken: refresh,
-			User:         userRes,
-		},
+	authURL, err := s.redirectProvider.AuthCodeURL(c.UserContext(), state, nonce)
+	if err != nil {
+		return c.Status(503).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "identity provider unavailable",
+		})
+	}
+
+	stateToken, err := utils.GenerateOIDCStateToken(state, nonce, oidcStateTTL)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate token",
+		})
+	}
+
+	// state terikat ke browser lewat cookie supaya callback tidak bisa
+	// dipicu dari browser lain (login CSRF)
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    stateToken,
+		Path:     "/api/v1/auth/oidc",
+		Expires:  time.Now().Add(oidcStateTTL),
+		HTTPOnly: true,
+		Secure:   !config.AppConfig.IsDevelopment(),
+		SameSite: fiber.CookieSameSiteLaxMode,
 	})
+
+	return c.Redirect(authURL, fiber.StatusFound)
 }
 
 //
-// ==================== REFRESH TOKEN ======================
+// ==================== OIDC CALLBACK (GET /auth/oidc/callback) ======================
+// IdP mengembalikan code; ditukar dengan ID token lalu user lokal dibuat/di-link
 //
 
-func (s *AuthService) Refresh(c *fiber.Ctx) error {
+func (s *AuthService) OIDCCallback(c *fiber.Ctx) error {
+	if s.redirectProvider == nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "OIDC login is not configured",
+		})
+	}
 
-	req := new(model.RefreshTokenRequest)
-	_ = c.BodyParser(req)
+	stateToken := c.Cookies(oidcStateCookie)
+	c.ClearCookie(oidcStateCookie)
 
-	claims := &model.RefreshTokenClaims{}
+	// user membatalkan login / IdP menolak
+	if idpErr := c.Query("error"); idpErr != "" {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "identity provider login failed: " + idpErr,
+			Code:   model.ErrCodeInvalidCredentials,
+		})
+	}
 
-	// validasi refresh token
-	token, err := jwt.ParseWithClaims(req.RefreshToken, claims, func(t *jwt.Token) (interface{}, error) {
-		return utils.JwtKey, nil
-	})
+	stateClaims, err := utils.ValidateOIDCStateToken(stateToken)
+	if err != nil || c.Query("state") == "" || stateClaims.ID != c.Query("state") || c.Query("code") == "" {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid or expired login state",
+		})
+	}
 
-	if err != nil || !token.Valid {
+	ext, err := s.redirectProvider.Exchange(c.UserContext(), c.Query("code"), stateClaims.Nonce)
+	if errors.Is(err, identity.ErrInvalidCredentials) || errors.Is(err, identity.ErrInvalidToken) {
 		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "invalid refresh token",
+			Error:  "identity provider login failed",
+			Code:   model.ErrCodeInvalidCredentials,
+		})
+	}
+	if err != nil {
+		return c.Status(503).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "identity provider unavailable",
+		})
+	}
+
+	return s.loginWithIdentity(c, ext)
+}
+
+//
+// ==================== MFA VERIFY (POST /auth/mfa/verify) ======================
+// Langkah kedua login: tukar challenge token + kode TOTP / recovery code dengan token
+//
+
+func (s *AuthService) VerifyMFA(c *fiber.Ctx) error {
+
+	req := new(model.MFAVerifyRequest)
+	if err := c.BodyParser(req); err != nil || req.ChallengeToken == "" || req.Code == "" {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "challengeToken and code are required",
+		})
+	}
+
+	claims, err := s.validateChallenge(req.ChallengeToken)
+	if err != nil {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid or expired challenge token",
 		})
 	}
 
-	// cari user
 	user, err := s.userRepo.FindByID(claims.UserID)
 	if err != nil {
-		return c.Status(404).JSON(model.APIResponse{
+		return c.Status(401).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "user not found",
+			Error:  "invalid or expired challenge token",
 		})
 	}
 
-	// ambil role
-	role, _ := s.roleRepo.GetRoleByID(user.RoleID)
+	if user.AccountStatus() != model.AccountStatusActive {
+		return s.rejectAccountStatus(c, user)
+	}
 
-	// ambil permission
-	perms, _ := s.permRepo.GetPermissionsByRoleID(role.ID)
+	// enrollment saat login: secret harus dibuat dulu lewat /auth/mfa/challenge/setup
+	if user.MFASecret == nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "MFA setup required",
+			Code:   model.ErrCodeMFARequired,
+		})
+	}
 
-	userRes := model.UserResponse{
-    ID:          user.ID,
-    Username:    user.Username,
-    Email:       user.Email,
-    FullName:    user.FullName,
-    Role:        role.Name,
-    IsActive:    user.IsActive,                            // ✅ tambahkan
-    CreatedAt:   user.CreatedAt.Format("2006-01-02 15:04:05"), // ✅ tambahkan
-    Permissions: perms,
+	// kode salah dihitung sebagai login gagal (lockout yang sama dengan password)
+	if !s.verifyMFACode(user, req.Code) {
+		if s.recordFailedLogin(user) {
+			return s.rejectAccountStatus(c, user)
+		}
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid MFA code",
+			Code:   model.ErrCodeInvalidCredentials,
+		})
+	}
+
+	// challenge token hanya boleh dipakai sekali
+	if err := s.revokeRepo.DenyToken(claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to complete login",
+		})
+	}
+
+	// enrollment selesai: aktifkan MFA dan tampilkan recovery code sekali
+	var recoveryCodes []string
+	if !user.MFAEnabled {
+		recoveryCodes, err = s.enableMFA(user.ID)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to enable MFA",
+			})
+		}
+	}
+
+	role, err := s.roleRepo.GetRoleByID(user.RoleID)
+	if err != nil {
+		return userRoleError(c, err)
+	}
+
+	return s.completeLogin(c, user, role, recoveryCodes)
 }
-	// generate new tokens
-	access, _ := utils.GenerateJWT(userRes)
-	refresh, _ := utils.GenerateRefreshToken(user.ID)
 
-	return c.JSON(model.APIResponse{
-		Status: "success",
-		Data: model.LoginResponse{
-			Token:        access,
-			RefreshToken: refresh,
-			User:         userRes,
-		},
-	})
+//
+// ==================== MFA CHALLENGE SETUP (POST /auth/mfa/challenge/setup) ======================
+// Enrollment saat login untuk user yang role-nya mewajibkan MFA tapi belum punya TOTP
+//
+
+func (s *AuthService) SetupMFAChallenge(c *fiber.Ctx) error {
+
+	req := new(model.MFAChallengeSetupRequest)
+	if err := c.BodyParser(req); err != nil || req.ChallengeToken == "" {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "challengeToken is required",
+		})
+	}
+
+	claims, err := s.validateChallenge(req.ChallengeToken)
+	if err != nil {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid or expired challenge token",
+		})
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid or expired challenge token",
+		})
+	}
+
+	return s.setupMFA(c, user)
 }
 
 //
-// ==================== PROFILE ======================
+// ==================== MFA SETUP (POST /auth/mfa/setup) ======================
+// Enrollment opsional oleh user yang sudah login
 //
 
-func (s *AuthService) Profile(c *fiber.Ctx) error {
+func (s *AuthService) SetupMFA(c *fiber.Ctx) error {
 
 	claims := c.Locals("user").(*model.JWTClaims)
 
@@ -169,29 +359,1028 @@ func (s *AuthService) Profile(c *fiber.Ctx) error {
 		})
 	}
 
-	res := model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Role:      claims.Role,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+	return s.setupMFA(c, user)
+}
+
+//
+// ==================== MFA ACTIVATE (POST /auth/mfa/activate) ======================
+// Konfirmasi enrollment dengan kode TOTP pertama
+//
+
+func (s *AuthService) ActivateMFA(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	req := new(model.MFAActivateRequest)
+	if err := c.BodyParser(req); err != nil || req.Code == "" {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "code is required",
+		})
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	if user.MFAEnabled {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "MFA is already enabled",
+		})
+	}
+
+	if user.MFASecret == nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "MFA setup has not been started",
+		})
+	}
+
+	if !utils.VerifyTOTP(*user.MFASecret, req.Code, time.Now()) {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid MFA code",
+			Code:   model.ErrCodeInvalidCredentials,
+		})
+	}
+
+	recoveryCodes, err := s.enableMFA(user.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to enable MFA",
+		})
 	}
 
 	return c.JSON(model.APIResponse{
-		Status: "success",
-		Data:   res,
+		Status:  "success",
+		Message: "MFA enabled, store the recovery codes in a safe place",
+		Data:    model.MFARecoveryCodesResponse{RecoveryCodes: recoveryCodes},
 	})
 }
 
 //
-// ==================== LOGOUT ======================
+// ==================== MFA DISABLE (POST /auth/mfa/disable) ======================
+// Butuh password + kode MFA; ditolak jika role mewajibkan MFA
 //
 
-func (s *AuthService) Logout(c *fiber.Ctx) error {
+func (s *AuthService) DisableMFA(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	req := new(model.MFADisableRequest)
+	if err := c.BodyParser(req); err != nil || req.Password == "" || req.Code == "" {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "password and code are required",
+		})
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	if !user.MFAEnabled {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "MFA is not enabled",
+		})
+	}
+
+	if role, _ := s.roleRepo.GetRoleByID(user.RoleID); role != nil && role.MFARequired {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "MFA is required for role " + role.Name,
+		})
+	}
+
+	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) || !s.verifyMFACode(user, req.Code) {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid password or MFA code",
+			Code:   model.ErrCodeInvalidCredentials,
+		})
+	}
+
+	if err := s.mfaRepo.Disable(user.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to disable MFA",
+		})
+	}
+
 	return c.JSON(model.APIResponse{
 		Status:  "success",
-		Message: "logout successful",
+		Message: "MFA disabled",
 	})
 }
+
+//
+// ==================== REFRESH TOKEN ======================
+//
+
+func (s *AuthService) Refresh(c *fiber.Ctx) error {
+
+	req := new(model.RefreshTokenRequest)
+	_ = c.BodyParser(req)
+
+	// validasi refresh token
+	claims, err := utils.ValidateRefreshToken(req.RefreshToken)
+	if err != nil {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid refresh token",
+		})
+	}
+
+	// token harus tercatat di database
+	stored, err := s.refreshRepo.FindByID(claims.ID)
+	if err != nil || stored.UserID != claims.UserID {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid refresh token",
+		})
+	}
+
+	// token yang sudah dirotasi dipakai lagi = kemungkinan dicuri,
+	// revoke seluruh family supaya pencuri dan pemilik sama-sama harus login ulang
+	if stored.RevokedAt != nil {
+		if stored.ReplacedBy != nil {
+			s.refreshRepo.RevokeFamily(stored.FamilyID)
+			return c.Status(401).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "refresh token reuse detected",
+			})
+		}
+
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "refresh token revoked",
+		})
+	}
+
+	// cari user
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	// akun yang sudah tidak aktif tidak boleh memperpanjang sesi
+	if user.AccountStatus() != model.AccountStatusActive {
+		s.refreshRepo.RevokeFamily(stored.FamilyID)
+		return s.rejectAccountStatus(c, user)
+	}
+
+	// ambil role
+	role, err := s.roleRepo.GetRoleByID(user.RoleID)
+	if err != nil {
+		return userRoleError(c, err)
+	}
+
+	// ambil permission
+	perms, err := s.permRepo.GetPermissionsByRoleID(role.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get user permissions",
+		})
+	}
+
+	userRes := model.UserResponse{
+    ID:          user.ID,
+    Username:    user.Username,
+    Email:       user.Email,
+    FullName:    user.FullName,
+    Role:        role.Name,
+    RoleID:      role.ID,
+    IsActive:    user.IsActive,                            // ✅ tambahkan
+    CreatedAt:   user.CreatedAt.Format("2006-01-02 15:04:05"), // ✅ tambahkan
+    Permissions: perms,
+}
+
+	// rotasi: token lama langsung tidak berlaku
+	newTokenID := uuid.New().String()
+	rotated, err := s.refreshRepo.MarkRotated(stored.ID, newTokenID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to rotate refresh token",
+		})
+	}
+	if !rotated {
+		// kalah balapan dengan request lain yang memakai token yang sama
+		s.refreshRepo.RevokeFamily(stored.FamilyID)
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "refresh token reuse detected",
+		})
+	}
+
+	// catat pemakaian sesi; family dari sebelum ada user_sessions dibuatkan sesinya sekarang
+	if err := s.touchSession(c, stored.FamilyID, user.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update session",
+		})
+	}
+
+	// generate new tokens (tetap di family yang sama)
+	loginRes, err := s.issueTokensWithID(userRes, stored.FamilyID, newTokenID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate token",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   loginRes,
+	})
+}
+
+//
+// ==================== PROFILE ======================
+//
+
+func (s *AuthService) Profile(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	res := model.UserResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		FullName:  user.FullName,
+		Role:          claims.Role,
+		IsActive:      user.IsActive,
+		AccountStatus: user.AccountStatus(),
+		MFAEnabled:    user.MFAEnabled,
+		CreatedAt:     user.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if claims.Impersonator != nil {
+		res.ImpersonatedBy = claims.Impersonator.Username
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   res,
+	})
+}
+
+//
+// ==================== LOGOUT ======================
+//
+
+func (s *AuthService) Logout(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	if err := s.denyCurrentToken(claims); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke access token",
+		})
+	}
+
+	// impersonation berakhir: sesi milik user yang di-impersonate tidak disentuh
+	if claims.Impersonator != nil {
+		return c.JSON(model.APIResponse{
+			Status:  "success",
+			Message: "impersonation ended",
+		})
+	}
+
+	// refresh token opsional; tanpa refresh token sesi dari access token (sid) yang di-logout.
+	// Logout semua device lewat POST /auth/logout/all.
+	req := new(model.RefreshTokenRequest)
+	_ = c.BodyParser(req)
+
+	if req.RefreshToken == "" {
+		if claims.SessionID != "" {
+			if err := s.sessionRepo.Revoke(claims.SessionID); err != nil {
+				return c.Status(500).JSON(model.APIResponse{
+					Status: "error",
+					Error:  "failed to revoke session",
+				})
+			}
+		}
+
+		return c.JSON(model.APIResponse{
+			Status:  "success",
+			Message: "logout successful",
+		})
+	}
+
+	refreshClaims, err := utils.ValidateRefreshToken(req.RefreshToken)
+	if err != nil || refreshClaims.UserID != claims.UserID {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid refresh token",
+		})
+	}
+
+	stored, err := s.refreshRepo.FindByID(refreshClaims.ID)
+	if err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid refresh token",
+		})
+	}
+
+	// revoke seluruh family = sesi login ini
+	if err := s.refreshRepo.RevokeFamily(stored.FamilyID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke refresh token",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "logout successful",
+	})
+}
+
+//
+// ==================== LOGOUT ALL (POST /auth/logout/all) ======================
+// Logout dari semua device: semua sesi login dan refresh token user di-revoke
+//
+
+func (s *AuthService) LogoutAll(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	if err := s.denyCurrentToken(claims); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke access token",
+		})
+	}
+
+	if err := s.refreshRepo.RevokeAllByUserID(claims.UserID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke refresh tokens",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "logged out from all devices",
+	})
+}
+
+// denyCurrentToken - access token yang sedang dipakai langsung tidak berlaku
+func (s *AuthService) denyCurrentToken(claims *model.JWTClaims) error {
+	if claims.ExpiresAt == nil {
+		return nil
+	}
+	return s.revokeRepo.DenyToken(claims.ID, claims.UserID, claims.ExpiresAt.Time)
+}
+
+//
+// ==================== SESSIONS (GET /auth/sessions) ======================
+// Daftar sesi login aktif milik user (device, IP, waktu login & pemakaian terakhir)
+//
+
+func (s *AuthService) GetSessions(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	sessions, err := s.sessionRepo.GetActiveByUserID(claims.UserID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get sessions",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   toSessionResponses(sessions, claims.SessionID),
+	})
+}
+
+//
+// ==================== REVOKE SESSION (DELETE /auth/sessions/:id) ======================
+// Logout satu device: refresh token sesi di-revoke dan access token-nya langsung ditolak
+//
+
+func (s *AuthService) RevokeSession(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	// sesi milik user lain diperlakukan sama dengan sesi yang tidak ada
+	session, err := s.sessionRepo.FindByID(c.Params("id"))
+	if err != nil || session.UserID != claims.UserID || session.RevokedAt != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "session not found",
+		})
+	}
+
+	if err := s.sessionRepo.Revoke(session.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke session",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "session revoked",
+	})
+}
+
+//
+// ==================== JWKS (GET /.well-known/jwks.json) ======================
+// Public key untuk service lain yang perlu memverifikasi access token
+//
+
+func (s *AuthService) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(utils.JWKS())
+}
+
+//
+// ==================== CHANGE PASSWORD (POST /auth/password) ======================
+//
+
+func (s *AuthService) ChangePassword(c *fiber.Ctx) error {
+
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	req := new(model.ChangePasswordRequest)
+	if err := c.BodyParser(req); err != nil || req.OldPassword == "" || req.NewPassword == "" {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "old_password and new_password are required",
+		})
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	// password lama wajib benar
+	if !utils.CheckPasswordHash(req.OldPassword, user.PasswordHash) {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "old password is incorrect",
+			Code:   model.ErrCodeInvalidCredentials,
+		})
+	}
+
+	if utils.CheckPasswordHash(req.NewPassword, user.PasswordHash) {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "new password must be different from the old password",
+		})
+	}
+
+	if err := utils.ValidatePasswordStrength(req.NewPassword); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if err := s.setPassword(user.ID, req.NewPassword); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to change password",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "password changed successfully, please login again",
+	})
+}
+
+//
+// ==================== RESET PASSWORD (POST /auth/password/reset) ======================
+// Tukar token reset dari admin dengan password baru
+//
+
+func (s *AuthService) ResetPassword(c *fiber.Ctx) error {
+
+	req := new(model.ResetPasswordRequest)
+	if err := c.BodyParser(req); err != nil || req.Token == "" || req.NewPassword == "" {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "token and new_password are required",
+		})
+	}
+
+	stored, err := s.resetRepo.FindByTokenHash(utils.HashResetToken(req.Token))
+	if err != nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid or expired reset token",
+		})
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil || user.DeletedAt != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid or expired reset token",
+		})
+	}
+
+	if err := utils.ValidatePasswordStrength(req.NewPassword); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	// token sekali pakai: hanya request pertama yang berhasil
+	used, err := s.resetRepo.MarkUsed(stored.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to reset password",
+		})
+	}
+	if !used {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid or expired reset token",
+		})
+	}
+
+	if err := s.setPassword(user.ID, req.NewPassword); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to reset password",
+		})
+	}
+
+	// reset oleh admin sekaligus membuka kunci akun
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		s.userRepo.ResetFailedLogins(user.ID)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "password reset successfully, please login with the new password",
+	})
+}
+
+//
+// ==================== HELPER: SET PASSWORD ======================
+//
+
+// setPassword - Simpan password baru lalu putus semua sesi user:
+// refresh token di-revoke, access token lama ditolak AuthRequired,
+// dan token reset lain yang masih berlaku dimatikan
+func (s *AuthService) setPassword(userID string, password string) error {
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(userID, hashed); err != nil {
+		return err
+	}
+
+	if err := s.refreshRepo.RevokeAllByUserID(userID); err != nil {
+		return err
+	}
+
+	if err := s.revokeRepo.SetTokensValidAfter(userID, time.Now()); err != nil {
+		return err
+	}
+
+	return s.resetRepo.InvalidateByUserID(userID)
+}
+
+//
+// ==================== HELPER: LOGIN LOCKOUT ======================
+//
+
+// recordFailedLogin - Catat login gagal dan kunci akun secara progresif.
+// Setelah LoginMaxAttempts kali gagal akun dikunci LoginLockoutBase, lalu
+// durasinya berlipat dua untuk setiap kegagalan berikutnya (maks LoginLockoutMax).
+// Return true jika akun baru saja dikunci.
+func (s *AuthService) recordFailedLogin(user *model.User) bool {
+	attempts, err := s.userRepo.IncrementFailedLogins(user.ID)
+	if err != nil {
+		return false
+	}
+
+	maxAttempts := config.AppConfig.LoginMaxAttempts
+	if maxAttempts <= 0 || attempts < maxAttempts {
+		return false
+	}
+
+	lockout := config.AppConfig.LoginLockoutBase
+	for i := maxAttempts; i < attempts && lockout < config.AppConfig.LoginLockoutMax; i++ {
+		lockout *= 2
+	}
+	if lockout > config.AppConfig.LoginLockoutMax {
+		lockout = config.AppConfig.LoginLockoutMax
+	}
+
+	lockedUntil := time.Now().Add(lockout)
+	if err := s.userRepo.LockUntil(user.ID, lockedUntil); err != nil {
+		return false
+	}
+
+	user.FailedLoginAttempts = attempts
+	user.LockedUntil = &lockedUntil
+	return true
+}
+
+//
+// ==================== HELPER: ACCOUNT STATUS ======================
+//
+
+// rejectAccountStatus - Response untuk akun yang tidak boleh login (lihat model.ErrCode*)
+func (s *AuthService) rejectAccountStatus(c *fiber.Ctx, user *model.User) error {
+	switch user.AccountStatus() {
+	case model.AccountStatusDeleted:
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "account has been deleted",
+			Code:   model.ErrCodeAccountDeleted,
+		})
+	case model.AccountStatusLocked:
+		return c.Status(423).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "account is temporarily locked",
+			Code:   model.ErrCodeAccountLocked,
+			Data: fiber.Map{
+				"locked_until": user.LockedUntil.Format("2006-01-02 15:04:05"),
+			},
+		})
+	default:
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "account is inactive",
+			Code:   model.ErrCodeAccountInactive,
+		})
+	}
+}
+
+// userRoleError - Response jika role user tidak bisa dibaca: role sudah tidak ada
+// (user tanpa role tidak bisa login) → 401, selain itu → 500
+func userRoleError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user has no role",
+		})
+	}
+	return c.Status(500).JSON(model.APIResponse{
+		Status: "error",
+		Error:  "failed to get user role",
+	})
+}
+
+//
+// ==================== HELPER: DIRECTORY LOGIN ======================
+//
+
+// continueLogin - Setelah password (lokal atau direktori) terverifikasi:
+// cek status akun lalu MFA atau langsung terbitkan token
+func (s *AuthService) continueLogin(c *fiber.Ctx, user *model.User) error {
+	// cek status akun (inactive / locked / deleted)
+	if user.AccountStatus() != model.AccountStatusActive {
+		return s.rejectAccountStatus(c, user)
+	}
+
+	// ambil role (gagal baca role tidak boleh melewati kewajiban MFA)
+	role, err := s.roleRepo.GetRoleByID(user.RoleID)
+	if err != nil {
+		return userRoleError(c, err)
+	}
+
+	// MFA aktif (atau diwajibkan role): password benar baru langkah pertama
+	if user.MFAEnabled || role.MFARequired {
+		return s.issueMFAChallenge(c, user)
+	}
+
+	return s.completeLogin(c, user, role, nil)
+}
+
+// loginWithDirectory - Verifikasi username/password ke LDAP.
+// local = user lokal dengan username yang sama (nil jika belum ada); login gagal
+// tetap dihitung ke user lokal supaya lockout berlaku.
+func (s *AuthService) loginWithDirectory(c *fiber.Ctx, req *model.LoginRequest, local *model.User) error {
+	ext, err := s.passwordProvider.Authenticate(c.UserContext(), req.Username, req.Password)
+	if errors.Is(err, identity.ErrInvalidCredentials) || errors.Is(err, identity.ErrUserNotFound) {
+		if local != nil && s.recordFailedLogin(local) {
+			return s.rejectAccountStatus(c, local)
+		}
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid username or password",
+			Code:   model.ErrCodeInvalidCredentials,
+		})
+	}
+	if err != nil {
+		return c.Status(503).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "identity provider unavailable",
+		})
+	}
+
+	return s.loginWithIdentity(c, ext)
+}
+
+// loginWithIdentity - Petakan identitas direktori ke user lokal lalu lanjutkan login
+func (s *AuthService) loginWithIdentity(c *fiber.Ctx, ext *model.ExternalIdentity) error {
+	user, err := s.provisioner.Resolve(ext)
+	switch {
+	case errors.Is(err, identity.ErrNoRoleMapped):
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "directory account is not mapped to any role",
+		})
+	case errors.Is(err, identity.ErrAccountConflict):
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "directory account conflicts with an existing local account",
+		})
+	case err != nil:
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to provision user",
+		})
+	}
+
+	return s.continueLogin(c, user)
+}
+
+//
+// ==================== HELPER: COMPLETE LOGIN ======================
+// Dipanggil setelah semua faktor terverifikasi (password, dan kode MFA jika perlu)
+//
+
+func (s *AuthService) completeLogin(c *fiber.Ctx, user *model.User, role *model.Role, recoveryCodes []string) error {
+	// login berhasil: reset counter login gagal
+	if user.FailedLoginAttempts > 0 {
+		s.userRepo.ResetFailedLogins(user.ID)
+	}
+
+	// ambil permission by role
+	perms, err := s.permRepo.GetPermissionsByRoleID(role.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get user permissions",
+		})
+	}
+
+	// response user
+	userRes := model.UserResponse{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		FullName:    user.FullName,
+		Role:        role.Name,
+		RoleID:      role.ID,
+		IsActive:    user.IsActive,
+		MFAEnabled:  user.MFAEnabled || len(recoveryCodes) > 0,
+		CreatedAt:   user.CreatedAt.Format("2006-01-02 15:04:05"),
+		Permissions: perms,
+	}
+
+	// login baru = sesi baru = family refresh token baru
+	session := &model.UserSession{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create session",
+		})
+	}
+
+	// generate token
+	loginRes, err := s.issueTokens(userRes, session.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate token",
+		})
+	}
+	loginRes.RecoveryCodes = recoveryCodes
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   loginRes,
+	})
+}
+
+//
+// ==================== HELPER: MFA ======================
+//
+
+// issueMFAChallenge - Response login langkah pertama: challenge token, belum ada access token
+func (s *AuthService) issueMFAChallenge(c *fiber.Ctx, user *model.User) error {
+	token, expiresAt, err := utils.GenerateMFAChallengeToken(user.ID, config.AppConfig.MFAChallengeTTL)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate token",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "MFA verification required",
+		Data: model.MFAChallengeResponse{
+			MFARequired:        true,
+			EnrollmentRequired: !user.MFAEnabled,
+			ChallengeToken:     token,
+			ExpiresAt:          expiresAt.Format("2006-01-02 15:04:05"),
+		},
+	})
+}
+
+// validateChallenge - Challenge token valid, belum kadaluarsa dan belum dipakai
+func (s *AuthService) validateChallenge(token string) (*model.JWTClaims, error) {
+	claims, err := utils.ValidateMFAChallengeToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	denied, err := s.revokeRepo.IsTokenDenied(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if denied {
+		return nil, errors.New("challenge token already used")
+	}
+
+	return claims, nil
+}
+
+// setupMFA - Buat secret TOTP baru (MFA belum aktif sampai kode pertama dikonfirmasi)
+func (s *AuthService) setupMFA(c *fiber.Ctx, user *model.User) error {
+	if user.MFAEnabled {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "MFA is already enabled",
+		})
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate MFA secret",
+		})
+	}
+
+	if err := s.mfaRepo.SetSecret(user.ID, secret); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to store MFA secret",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: model.MFASetupResponse{
+			Secret:     secret,
+			OTPAuthURL: utils.TOTPAuthURL(config.AppConfig.MFAIssuer, user.Username, secret),
+		},
+	})
+}
+
+// enableMFA - Aktifkan MFA dengan recovery code baru, return kode asli
+func (s *AuthService) enableMFA(userID string) ([]string, error) {
+	codes, hashes, err := utils.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.Enable(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verifyMFACode - Kode TOTP, atau recovery code (sekali pakai) jika MFA sudah aktif
+func (s *AuthService) verifyMFACode(user *model.User, code string) bool {
+	if user.MFASecret != nil && utils.VerifyTOTP(*user.MFASecret, code, time.Now()) {
+		return true
+	}
+
+	if !user.MFAEnabled {
+		return false
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(user.ID, utils.HashRecoveryCode(code))
+	return err == nil && used
+}
+
+//
+// ==================== HELPER: SESSIONS ======================
+//
+
+// touchSession - Perbarui last_used_at sesi saat refresh
+func (s *AuthService) touchSession(c *fiber.Ctx, sessionID string, userID string) error {
+	_, err := s.sessionRepo.FindByID(sessionID)
+	if err == sql.ErrNoRows {
+		return s.sessionRepo.Create(&model.UserSession{
+			ID:        sessionID,
+			UserID:    userID,
+			UserAgent: c.Get(fiber.HeaderUserAgent),
+			IPAddress: c.IP(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sessionRepo.Touch(sessionID)
+	return err
+}
+
+// toSessionResponses - currentSessionID = claim "sid" dari request (boleh kosong)
+func toSessionResponses(sessions []model.UserSession, currentSessionID string) []model.SessionResponse {
+	res := make([]model.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		res = append(res, model.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			IssuedAt:   session.IssuedAt.Format("2006-01-02 15:04:05"),
+			LastUsedAt: session.LastUsedAt.Format("2006-01-02 15:04:05"),
+			Current:    currentSessionID != "" && session.ID == currentSessionID,
+		})
+	}
+	return res
+}
+
+//
+// ==================== HELPER: ISSUE TOKENS ======================
+//
+
+func (s *AuthService) issueTokens(userRes model.UserResponse, familyID string) (*model.LoginResponse, error) {
+	return s.issueTokensWithID(userRes, familyID, uuid.New().String())
+}
+
+// issueTokensWithID - Generate access token + refresh token dan simpan refresh token ke database
+// familyID sekaligus ID sesi (claim "sid" di access token)
+func (s *AuthService) issueTokensWithID(userRes model.UserResponse, familyID string, tokenID string) (*model.LoginResponse, error) {
+	access, err := utils.GenerateSessionJWT(userRes, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(utils.RefreshTokenTTL)
+	refresh, err := utils.GenerateRefreshToken(userRes.ID, tokenID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshRepo.Create(&model.RefreshToken{
+		ID:        tokenID,
+		UserID:    userRes.ID,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResponse{
+		Token:        access,
+		RefreshToken: refresh,
+		User:         userRes,
+	}, nil
+}
@@ -3,8 +3,10 @@ package service
 import (
 	"math"
 	"strconv"
+	"time"
 
 	"UASBE/app/model"
+	"UASBE/app/policy"
 	"UASBE/app/repository"
 
 	"github.com/go-playground/validator/v10"
@@ -16,6 +18,7 @@ type LecturerService struct {
 	studentRepo     repository.StudentRepository
 	achievementRepo repository.AchievementRepository
 	userRepo        repository.UserRepository
+	policy          *policy.Policy
 	validate        *validator.Validate
 }
 
@@ -30,6 +33,7 @@ func NewLecturerService(
 		studentRepo:     studentRepo,
 		achievementRepo: achievementRepo,
 		userRepo:        userRepo,
+		policy:          policy.New(studentRepo, lecturerRepo),
 		validate:        validator.New(),
 	}
 }
@@ -126,19 +130,6 @@ func (s *LecturerService) GetLecturerAdvisees(c *fiber.Ctx) error {
 		})
 	}
 
-	// Authorization check:
-	// Dosen Wali hanya bisa lihat advisees sendiri
-	// Admin bisa lihat semua
-	if claims.Role == "Dosen Wali" {
-		lecturer, err := s.lecturerRepo.FindByUserID(claims.UserID)
-		if err != nil || lecturer.ID != lecturerID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: you can only view your own advisees",
-			})
-		}
-	}
-
 	// Verify lecturer exists
 	lecturer, err := s.lecturerRepo.FindByID(lecturerID)
 	if err != nil {
@@ -148,6 +139,14 @@ func (s *LecturerService) GetLecturerAdvisees(c *fiber.Ctx) error {
 		})
 	}
 
+	// Authorization check: dosen itu sendiri, satu departemen (read_department), atau read_all
+	if !s.policy.Actor(claims).CanViewAdvisees(lecturer) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden: you can only view your own advisees",
+		})
+	}
+
 	// Parse query params
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
@@ -260,4 +259,211 @@ func (s *LecturerService) GetLecturerAdvisees(c *fiber.Ctx) error {
 			"total_pages": totalPages,
 		},
 	})
-}
\ No newline at end of file
+}
+//
+// ==================== GET DELEGATIONS (GET /lecturers/:id/delegations) ======================
+// Delegasi hak verifikasi yang diberikan atau diterima dosen.
+// Actor: dosen itu sendiri atau admin.
+//
+
+func (s *LecturerService) GetDelegations(c *fiber.Ctx) error {
+	lecturerID := c.Params("id")
+
+	// Get user dari context untuk authorization
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Verify lecturer exists
+	lecturer, err := s.lecturerRepo.FindByID(lecturerID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "lecturer not found",
+		})
+	}
+
+	if !s.policy.Actor(claims).CanManageDelegations(lecturer) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden: you can only view your own delegations",
+		})
+	}
+
+	delegations, err := s.lecturerRepo.GetDelegations(lecturer.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to fetch delegations",
+		})
+	}
+
+	today := time.Now().Format("2006-01-02")
+	for i := range delegations {
+		delegations[i].Active = delegations[i].IsActiveOn(today)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   delegations,
+	})
+}
+
+//
+// ==================== CREATE DELEGATION (POST /lecturers/:id/delegations) ======================
+// Dosen wali (:id) memberi dosen lain hak verify/reject atas mahasiswa bimbingannya
+// selama starts_on..ends_on. Actor: dosen wali itu sendiri atau admin.
+//
+
+func (s *LecturerService) CreateDelegation(c *fiber.Ctx) error {
+	lecturerID := c.Params("id")
+
+	// Get user dari context untuk authorization
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Parse request
+	req := new(model.CreateDelegationRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	// Validasi
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "delegate_id is required, starts_on and ends_on must be dates (YYYY-MM-DD)",
+		})
+	}
+	if req.EndsOn < req.StartsOn {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "ends_on must not be before starts_on",
+		})
+	}
+	if req.EndsOn < time.Now().Format("2006-01-02") {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "ends_on must not be in the past",
+		})
+	}
+
+	// Verify lecturer exists
+	advisor, err := s.lecturerRepo.FindByID(lecturerID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "lecturer not found",
+		})
+	}
+
+	if !s.policy.Actor(claims).CanManageDelegations(advisor) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden: you can only delegate your own advisees",
+		})
+	}
+
+	// Delegasi harus dosen lain
+	if req.DelegateID == advisor.ID {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "cannot delegate to yourself",
+		})
+	}
+	delegate, err := s.lecturerRepo.FindByID(req.DelegateID)
+	if err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "delegate lecturer not found",
+		})
+	}
+
+	createdBy := claims.UserID
+	delegation := &model.VerifierDelegation{
+		AdvisorID:  advisor.ID,
+		DelegateID: delegate.ID,
+		StartsOn:   req.StartsOn,
+		EndsOn:     req.EndsOn,
+		Reason:     req.Reason,
+		CreatedBy:  &createdBy,
+	}
+	if err := s.lecturerRepo.CreateDelegation(delegation); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create delegation",
+		})
+	}
+	delegation.Active = delegation.IsActiveOn(time.Now().Format("2006-01-02"))
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "delegation created",
+		Data:    delegation,
+	})
+}
+
+//
+// ==================== REVOKE DELEGATION (DELETE /lecturers/:id/delegations/:delegationId) ======================
+// Cabut delegasi lebih awal (mis. dosen wali kembali dari cuti)
+//
+
+func (s *LecturerService) RevokeDelegation(c *fiber.Ctx) error {
+	lecturerID := c.Params("id")
+
+	// Get user dari context untuk authorization
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	// Delegasi harus milik dosen wali pada URL
+	delegation, err := s.lecturerRepo.FindDelegationByID(c.Params("delegationId"))
+	if err != nil || delegation.AdvisorID != lecturerID {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "delegation not found",
+		})
+	}
+
+	if !s.policy.Actor(claims).CanManageDelegations(&model.Lecturer{ID: delegation.AdvisorID}) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden: you can only revoke your own delegations",
+		})
+	}
+
+	if delegation.RevokedAt != nil {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "delegation already revoked",
+		})
+	}
+
+	if err := s.lecturerRepo.RevokeDelegation(delegation.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke delegation",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "delegation revoked",
+	})
+}
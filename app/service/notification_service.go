@@ -0,0 +1,82 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"UASBE/app/model"
+	"UASBE/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+//
+// ==================== GET NOTIFICATIONS (GET /notifications) ======================
+// Notifikasi milik user yang login, terbaru dulu. Query: unread=true
+//
+
+func (s *NotificationService) GetNotifications(c *fiber.Ctx) error {
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	notifications, err := s.notificationRepo.GetByUserID(claims.UserID, c.QueryBool("unread"))
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get notifications",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   notifications,
+	})
+}
+
+//
+// ==================== MARK NOTIFICATION READ (POST /notifications/:id/read) ======================
+//
+
+func (s *NotificationService) MarkNotificationRead(c *fiber.Ctx) error {
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	if err := s.notificationRepo.MarkRead(c.Params("id"), claims.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(404).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "notification not found",
+			})
+		}
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update notification",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "notification marked as read",
+	})
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"UASBE/app/model"
+	"UASBE/app/policy"
 	"UASBE/app/repository"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,6 +14,7 @@ type ReportService struct {
 	studentRepo     repository.StudentRepository
 	lecturerRepo    repository.LecturerRepository
 	userRepo        repository.UserRepository
+	policy          *policy.Policy
 }
 
 func NewReportService(
@@ -28,13 +30,14 @@ func NewReportService(
 		studentRepo:     studentRepo,
 		lecturerRepo:    lecturerRepo,
 		userRepo:        userRepo,
+		policy:          policy.New(studentRepo, lecturerRepo),
 	}
 }
 
 //
 // ==================== GET STATISTICS (GET /reports/statistics) ======================
 // FR-011: Achievement Statistics
-// Actor: Mahasiswa (own), Dosen Wali (advisee), Admin (all)
+// Scope ditentukan oleh policy: own, advisee, department, atau all
 //
 
 func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
@@ -47,39 +50,20 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 		})
 	}
 
-	var studentID *string
-	var advisorID *string
-
-	// Determine scope based on role
-	if claims.Role == "Mahasiswa" {
-		// Mahasiswa: hanya statistik sendiri
-		student, err := s.studentRepo.FindByUserID(claims.UserID)
-		if err != nil {
-			return c.Status(404).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "student profile not found",
-			})
-		}
-		studentID = &student.ID
-
-	} else if claims.Role == "Dosen Wali" {
-		// Dosen Wali: statistik mahasiswa bimbingannya
-		lecturer, err := s.lecturerRepo.FindByUserID(claims.UserID)
-		if err != nil {
-			return c.Status(404).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "lecturer profile not found",
-			})
-		}
-		advisorID = &lecturer.ID
+	// Determine scope berdasarkan permission & relasi actor
+	scope := s.policy.Actor(claims).ReadScope()
+	if scope.Kind == model.ScopeNone {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
 	}
-	// Admin: studentID & advisorID tetap nil (semua data)
 
 	// Get statistics
 	stats := &model.AchievementStatistics{}
 
 	// 1. Total by type
-	totalByType, err := s.reportRepo.GetTotalByType(studentID, advisorID)
+	totalByType, err := s.reportRepo.GetTotalByType(scope)
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -89,7 +73,7 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 	stats.TotalByType = totalByType
 
 	// 2. Total by period
-	totalByPeriod, err := s.reportRepo.GetTotalByPeriod(studentID, advisorID)
+	totalByPeriod, err := s.reportRepo.GetTotalByPeriod(scope)
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
@@ -98,22 +82,22 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 	}
 	stats.TotalByPeriod = totalByPeriod
 
-	// 3. Top students (kecuali untuk Mahasiswa)
-	if claims.Role != "Mahasiswa" {
-		topStudents, err := s.reportRepo.GetTopStudents(10, advisorID)
+	// 3. Top students (kecuali untuk statistik milik sendiri)
+	if scope.Kind != model.ScopeOwn {
+		topStudents, err := s.reportRepo.GetTopStudents(10, scope)
 		if err == nil {
 			stats.TopStudents = topStudents
 		}
 	}
 
 	// 4. Competition level distribution
-	competitionDist, err := s.reportRepo.GetCompetitionLevelDistribution(studentID, advisorID)
+	competitionDist, err := s.reportRepo.GetCompetitionLevelDistribution(scope)
 	if err == nil {
 		stats.CompetitionLevelDistribution = competitionDist
 	}
 
 	// 5. Status breakdown
-	statusBreakdown, err := s.reportRepo.GetStatusBreakdown(studentID, advisorID)
+	statusBreakdown, err := s.reportRepo.GetStatusBreakdown(scope)
 	if err == nil {
 		stats.StatusBreakdown = statusBreakdown
 	}
@@ -134,7 +118,7 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 //
 // ==================== GET STUDENT REPORT (GET /reports/student/:id) ======================
 // FR-011: Detail report untuk satu mahasiswa
-// Actor: Mahasiswa (own), Dosen Wali (advisee), Admin (all)
+// Scope ditentukan oleh policy: own, advisee, department, atau all
 //
 
 func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
@@ -159,26 +143,12 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 	}
 
 	// Authorization check
-	if claims.Role == "Mahasiswa" {
-		// Mahasiswa hanya bisa lihat report sendiri
-		currentStudent, _ := s.studentRepo.FindByUserID(claims.UserID)
-		if currentStudent == nil || currentStudent.ID != studentID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: you can only view your own report",
-			})
-		}
-	} else if claims.Role == "Dosen Wali" {
-		// Dosen wali hanya bisa lihat report advisees
-		lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		if lecturer == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: you can only view reports of your advisees",
-			})
-		}
+	if !s.policy.Actor(claims).CanReadStudent(student) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden: you can only view reports of students within your access scope",
+		})
 	}
-	// Admin bisa lihat semua
 
 	// Build student info
 	user, err := s.userRepo.FindByID(student.ID)
@@ -288,6 +258,7 @@ func (s *ReportService) buildAchievementResponse(
 		Tags:            achievement.Tags,
 		Points:          achievement.Points,
 		Status:          reference.Status,
+		SubmissionCount: reference.SubmissionCount,
 		CreatedAt:       achievement.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:       achievement.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
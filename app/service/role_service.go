@@ -0,0 +1,416 @@
+package service
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"UASBE/app/model"
+	"UASBE/app/repository"
+)
+
+type RoleService struct {
+	roleRepo repository.RoleRepository
+	permRepo repository.PermissionRepository
+	validate *validator.Validate
+}
+
+func NewRoleService(
+	roleRepo repository.RoleRepository,
+	permRepo repository.PermissionRepository,
+) *RoleService {
+	return &RoleService{
+		roleRepo: roleRepo,
+		permRepo: permRepo,
+		validate: validator.New(),
+	}
+}
+
+//
+// ==================== GET ROLES (GET /roles) ======================
+//
+
+func (s *RoleService) GetRoles(c *fiber.Ctx) error {
+	roles, err := s.roleRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get roles",
+		})
+	}
+
+	responses := make([]model.RoleResponse, 0, len(roles))
+	for i := range roles {
+		response, err := s.buildRoleResponse(&roles[i])
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to get role permissions",
+			})
+		}
+		responses = append(responses, *response)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   responses,
+	})
+}
+
+//
+// ==================== GET ROLE BY ID (GET /roles/:id) ======================
+//
+
+func (s *RoleService) GetRoleByID(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	response, err := s.buildRoleResponse(role)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get role permissions",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   response,
+	})
+}
+
+//
+// ==================== CREATE ROLE (POST /roles) ======================
+//
+
+func (s *RoleService) CreateRole(c *fiber.Ctx) error {
+	req := new(model.RoleCreateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if existing, _ := s.roleRepo.GetRoleByName(req.Name); existing != nil {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role name already exists",
+		})
+	}
+
+	// Validasi semua permission dulu sebelum role dibuat
+	permissionIDs := make([]string, 0, len(req.Permissions))
+	for _, name := range req.Permissions {
+		perm, err := s.permRepo.FindByName(name)
+		if err != nil {
+			return c.Status(404).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "permission not found: " + name,
+			})
+		}
+		permissionIDs = append(permissionIDs, perm.ID)
+	}
+
+	role := &model.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		MFARequired: req.MFARequired,
+	}
+	if err := s.roleRepo.Create(role, permissionIDs); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to create role",
+		})
+	}
+
+	response, _ := s.buildRoleResponse(role)
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "role created successfully",
+		Data:    response,
+	})
+}
+
+//
+// ==================== UPDATE ROLE (PUT /roles/:id) ======================
+// Role bawaan hanya boleh diubah deskripsi dan kewajiban MFA-nya
+//
+
+func (s *RoleService) UpdateRole(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	req := new(model.RoleUpdateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if req.Name != nil && *req.Name != role.Name {
+		if model.IsBuiltInRole(role.Name) {
+			return c.Status(409).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "built-in role cannot be renamed",
+			})
+		}
+
+		if existing, _ := s.roleRepo.GetRoleByName(*req.Name); existing != nil {
+			return c.Status(409).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "role name already exists",
+			})
+		}
+		role.Name = *req.Name
+	}
+
+	if req.Description != nil {
+		role.Description = *req.Description
+	}
+
+	// Berlaku di login berikutnya; user tanpa TOTP diminta enrollment saat login
+	if req.MFARequired != nil {
+		role.MFARequired = *req.MFARequired
+	}
+
+	if err := s.roleRepo.Update(role); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to update role",
+		})
+	}
+
+	response, _ := s.buildRoleResponse(role)
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "role updated successfully",
+		Data:    response,
+	})
+}
+
+//
+// ==================== DELETE ROLE (DELETE /roles/:id) ======================
+//
+
+func (s *RoleService) DeleteRole(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	if model.IsBuiltInRole(role.Name) {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "built-in role cannot be deleted",
+		})
+	}
+
+	// Role yang masih dipakai user tidak boleh dihapus (role_id user akan jadi NULL)
+	count, err := s.roleRepo.CountUsers(role.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to delete role",
+		})
+	}
+	if count > 0 {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role is still assigned to users",
+		})
+	}
+
+	if err := s.roleRepo.Delete(role.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to delete role",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "role deleted successfully",
+	})
+}
+
+//
+// ==================== ATTACH PERMISSION (POST /roles/:id/permissions) ======================
+//
+
+func (s *RoleService) AttachPermission(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	req := new(model.RolePermissionRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	perm, err := s.permRepo.FindByName(req.Permission)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "permission not found",
+		})
+	}
+
+	if err := s.permRepo.AttachToRole(role.ID, perm.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to attach permission",
+		})
+	}
+
+	response, _ := s.buildRoleResponse(role)
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "permission attached successfully",
+		Data:    response,
+	})
+}
+
+//
+// ==================== DETACH PERMISSION (DELETE /roles/:id/permissions/:permission) ======================
+// Berlaku langsung untuk semua token aktif (AuthRequired membaca permission dari database)
+//
+
+func (s *RoleService) DetachPermission(c *fiber.Ctx) error {
+	role, err := s.roleRepo.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "role not found",
+		})
+	}
+
+	perm, err := s.permRepo.FindByName(c.Params("permission"))
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "permission not found",
+		})
+	}
+
+	// Admin tidak boleh kehilangan akses ke manajemen user/role
+	if role.Name == model.RoleAdmin && perm.Name == model.PermissionUserManage {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user:manage cannot be removed from the Admin role",
+		})
+	}
+
+	if err := s.permRepo.DetachFromRole(role.ID, perm.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to detach permission",
+		})
+	}
+
+	response, _ := s.buildRoleResponse(role)
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "permission detached successfully",
+		Data:    response,
+	})
+}
+
+//
+// ==================== GET PERMISSIONS (GET /permissions) ======================
+//
+
+func (s *RoleService) GetPermissions(c *fiber.Ctx) error {
+	permissions, err := s.permRepo.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get permissions",
+		})
+	}
+
+	responses := make([]model.PermissionResponse, 0, len(permissions))
+	for _, perm := range permissions {
+		responses = append(responses, model.PermissionResponse{
+			ID:          perm.ID,
+			Name:        perm.Name,
+			Resource:    perm.Resource,
+			Action:      perm.Action,
+			Description: perm.Description,
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   responses,
+	})
+}
+
+//
+// ==================== HELPER: BUILD ROLE RESPONSE ======================
+//
+
+func (s *RoleService) buildRoleResponse(role *model.Role) (*model.RoleResponse, error) {
+	permissions, err := s.permRepo.GetPermissionsByRoleID(role.ID)
+	if err != nil {
+		return nil, err
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return &model.RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		BuiltIn:     model.IsBuiltInRole(role.Name),
+		MFARequired: role.MFARequired,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt.Format("2006-01-02 15:04:05"),
+	}, nil
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"UASBE/app/model"
+	"UASBE/app/policy"
 	"UASBE/app/repository"
 )
 
@@ -15,6 +16,7 @@ type StudentService struct {
 	lecturerRepo    repository.LecturerRepository
 	achievementRepo repository.AchievementRepository
 	userRepo        repository.UserRepository
+	policy          *policy.Policy
 	validate        *validator.Validate
 }
 
@@ -29,6 +31,7 @@ func NewStudentService(
 		lecturerRepo:    lecturerRepo,
 		achievementRepo: achievementRepo,
 		userRepo:        userRepo,
+		policy:          policy.New(studentRepo, lecturerRepo),
 		validate:        validator.New(),
 	}
 }
@@ -61,16 +64,17 @@ func (s *StudentService) GetAllStudents(c *fiber.Ctx) error {
 
 	offset := (page - 1) * pageSize
 
-	// Authorization: Dosen Wali hanya bisa lihat advisees
-	if claims.Role == "Dosen Wali" {
-		lecturer, err := s.lecturerRepo.FindByUserID(claims.UserID)
-		if err != nil {
-			return c.Status(404).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "lecturer profile not found",
-			})
-		}
+	// Authorization: selain scope "all", hanya mahasiswa dalam jangkauan akses actor
+	actor := s.policy.Actor(claims)
+	scope := actor.ReadScope()
+	if scope.Kind == model.ScopeNone {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
 
+	if scope.Kind != model.ScopeAll {
 		// Get all students
 		allStudents, err := s.studentRepo.GetAll(1000, 0)
 		if err != nil {
@@ -80,11 +84,11 @@ func (s *StudentService) GetAllStudents(c *fiber.Ctx) error {
 			})
 		}
 
-		// Filter students yang advisornya adalah lecturer ini
+		// Filter students sesuai scope (advisee, departemen, atau diri sendiri)
 		var advisees []model.Student
-		for _, student := range allStudents {
-			if student.AdvisorID != nil && *student.AdvisorID == lecturer.ID {
-				advisees = append(advisees, student)
+		for i := range allStudents {
+			if actor.CanReadStudent(&allStudents[i]) {
+				advisees = append(advisees, allStudents[i])
 			}
 		}
 
@@ -136,7 +140,7 @@ func (s *StudentService) GetAllStudents(c *fiber.Ctx) error {
 		})
 	}
 
-	// Admin & Mahasiswa bisa lihat semua
+	// Scope all: lihat semua
 	students, err := s.studentRepo.GetAll(pageSize, offset)
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{
@@ -233,26 +237,12 @@ func (s *StudentService) GetStudentByID(c *fiber.Ctx) error {
 	}
 
 	// Authorization check
-	if claims.Role == "Mahasiswa" {
-		// Mahasiswa hanya bisa lihat profile sendiri
-		currentStudent, _ := s.studentRepo.FindByUserID(claims.UserID)
-		if currentStudent == nil || currentStudent.ID != studentID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: you can only view your own profile",
-			})
-		}
-	} else if claims.Role == "Dosen Wali" {
-		// Dosen wali hanya bisa lihat advisees
-		lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		if lecturer == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: you can only view your advisees",
-			})
-		}
+	if !s.policy.Actor(claims).CanReadStudent(student) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden: you can only view students within your access scope",
+		})
 	}
-	// Admin bisa lihat semua
 
 	// Get user details
 	user, err := s.userRepo.FindByID(student.ID)
@@ -326,26 +316,12 @@ func (s *StudentService) GetStudentAchievements(c *fiber.Ctx) error {
 	}
 
 	// Authorization check
-	if claims.Role == "Mahasiswa" {
-		// Mahasiswa hanya bisa lihat achievements sendiri
-		currentStudent, _ := s.studentRepo.FindByUserID(claims.UserID)
-		if currentStudent == nil || currentStudent.ID != studentID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: you can only view your own achievements",
-			})
-		}
-	} else if claims.Role == "Dosen Wali" {
-		// Dosen wali hanya bisa lihat achievements advisees
-		lecturer, _ := s.lecturerRepo.FindByUserID(claims.UserID)
-		if lecturer == nil || student.AdvisorID == nil || *student.AdvisorID != lecturer.ID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "forbidden: you can only view achievements of your advisees",
-			})
-		}
+	if !s.policy.Actor(claims).CanReadStudent(student) {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden: you can only view achievements of students within your access scope",
+		})
 	}
-	// Admin bisa lihat semua
 
 	// Parse query params
 	page, _ := strconv.Atoi(c.Query("page", "1"))
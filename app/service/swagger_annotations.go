@@ -7,26 +7,59 @@ package service
 
 // Login godoc
 // @Summary Login to the system
-// @Description Authenticate user with username/email and password
+// @Description Authenticate user with username/email and password. Rejected logins carry a machine-readable "code": INVALID_CREDENTIALS, ACCOUNT_INACTIVE, ACCOUNT_LOCKED or ACCOUNT_DELETED. If the user has MFA enabled, or the role requires MFA, no tokens are issued: the response contains an MFA challenge token (data=model.MFAChallengeResponse) that must be exchanged at /auth/mfa/verify. When LDAP is configured, unknown usernames and users without a local password are authenticated against the directory; first-time directory users are provisioned automatically.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body model.LoginRequest true "Login credentials"
-// @Success 200 {object} model.APIResponse{data=model.LoginResponse} "Login successful"
+// @Success 200 {object} model.APIResponse{data=model.LoginResponse} "Login successful (or MFA challenge, see description)"
 // @Failure 400 {object} model.APIResponse "Invalid request body"
-// @Failure 401 {object} model.APIResponse "Invalid username or password"
+// @Failure 401 {object} model.APIResponse "Invalid username or password (INVALID_CREDENTIALS)"
+// @Failure 403 {object} model.APIResponse "Account inactive or deleted (ACCOUNT_INACTIVE, ACCOUNT_DELETED), or directory account not mapped to a role"
+// @Failure 409 {object} model.APIResponse "Directory account conflicts with an existing local account"
+// @Failure 423 {object} model.APIResponse "Account temporarily locked after too many failed logins (ACCOUNT_LOCKED)"
+// @Failure 429 {object} model.APIResponse "Too many login attempts, see Retry-After header (TOO_MANY_REQUESTS)"
+// @Failure 503 {object} model.APIResponse "Identity provider unavailable"
 // @Router /auth/login [post]
 func (s *AuthService) LoginSwagger() {}
 
+// OIDCLogin godoc
+// @Summary Start campus SSO login (OIDC)
+// @Description Redirect the browser to the campus identity provider. A short-lived state cookie binds the callback to this browser.
+// @Tags Authentication
+// @Success 302 "Redirect to the identity provider"
+// @Failure 404 {object} model.APIResponse "OIDC login is not configured"
+// @Failure 503 {object} model.APIResponse "Identity provider unavailable"
+// @Router /auth/oidc/login [get]
+func (s *AuthService) OIDCLoginSwagger() {}
+
+// OIDCCallback godoc
+// @Summary Campus SSO callback (OIDC)
+// @Description Exchange the authorization code for an ID token, then log in the linked local user. First-time users are provisioned just-in-time: the role comes from the directory groups, and existing students/lecturers are linked by NIM/NIP. The response is the same as /auth/login (tokens, or an MFA challenge).
+// @Tags Authentication
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from /auth/oidc/login"
+// @Success 200 {object} model.APIResponse{data=model.LoginResponse} "Login successful (or MFA challenge)"
+// @Failure 400 {object} model.APIResponse "Invalid or expired login state"
+// @Failure 401 {object} model.APIResponse "Identity provider login failed (INVALID_CREDENTIALS)"
+// @Failure 403 {object} model.APIResponse "Account inactive/deleted or not mapped to a role"
+// @Failure 409 {object} model.APIResponse "Directory account conflicts with an existing local account"
+// @Failure 503 {object} model.APIResponse "Identity provider unavailable"
+// @Router /auth/oidc/callback [get]
+func (s *AuthService) OIDCCallbackSwagger() {}
+
 // Refresh godoc
 // @Summary Refresh access token
-// @Description Get new access token using refresh token
+// @Description Get new access token using refresh token. The refresh token is rotated: the old one stops working, and reusing an already-rotated token revokes the whole token family.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body model.RefreshTokenRequest true "Refresh token"
 // @Success 200 {object} model.APIResponse{data=model.LoginResponse} "Token refreshed"
-// @Failure 401 {object} model.APIResponse "Invalid refresh token"
+// @Failure 401 {object} model.APIResponse "Invalid, revoked or reused refresh token"
+// @Failure 403 {object} model.APIResponse "Account inactive or deleted (ACCOUNT_INACTIVE, ACCOUNT_DELETED)"
+// @Failure 423 {object} model.APIResponse "Account temporarily locked (ACCOUNT_LOCKED)"
 // @Failure 404 {object} model.APIResponse "User not found"
 // @Router /auth/refresh [post]
 func (s *AuthService) RefreshSwagger() {}
@@ -46,16 +79,198 @@ func (s *AuthService) ProfileSwagger() {}
 
 // Logout godoc
 // @Summary Logout from system
-// @Description Logout current user (invalidate token on client side)
+// @Description Logout current user. The current access token is revoked immediately. Revokes the session of the given refresh token; without a refresh token the session of the current access token is revoked. Use /auth/logout/all to log out from every device.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param request body model.RefreshTokenRequest false "Refresh token of the session to revoke"
 // @Success 200 {object} model.APIResponse "Logout successful"
+// @Failure 400 {object} model.APIResponse "Invalid refresh token"
 // @Failure 401 {object} model.APIResponse "Unauthorized"
 // @Router /auth/logout [post]
 func (s *AuthService) LogoutSwagger() {}
 
+// LogoutAll godoc
+// @Summary Logout from all devices
+// @Description Revoke the current access token, every login session and every refresh token of the current user.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse "Logged out from all devices"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Router /auth/logout/all [post]
+func (s *AuthService) LogoutAllSwagger() {}
+
+// ChangePassword godoc
+// @Summary Change own password
+// @Description Change the password of the current user. The old password is required and the new one must satisfy the password policy. All existing sessions (access and refresh tokens) are revoked, so the user has to login again.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ChangePasswordRequest true "Old and new password"
+// @Success 200 {object} model.APIResponse "Password changed"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Old password is incorrect (INVALID_CREDENTIALS)"
+// @Failure 422 {object} model.APIResponse "New password violates the password policy"
+// @Router /auth/password [post]
+func (s *AuthService) ChangePasswordSwagger() {}
+
+// ResetPassword godoc
+// @Summary Reset password with a one-time token
+// @Description Redeem a password reset token issued by an admin. The token can be used once and expires after PASSWORD_RESET_TOKEN_TTL. All existing sessions of the user are revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} model.APIResponse "Password reset"
+// @Failure 400 {object} model.APIResponse "Invalid, used or expired reset token"
+// @Failure 422 {object} model.APIResponse "New password violates the password policy"
+// @Router /auth/password/reset [post]
+func (s *AuthService) ResetPasswordSwagger() {}
+
+// VerifyMFA godoc
+// @Summary Complete login with an MFA code
+// @Description Second login step. Exchange the challenge token from /auth/login and a TOTP code (or a one-time recovery code) for access and refresh tokens. The challenge token can be used once. Wrong codes count as failed logins. When MFA is being enrolled during login, the response also contains the recovery codes.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.MFAVerifyRequest true "Challenge token and code"
+// @Success 200 {object} model.APIResponse{data=model.LoginResponse} "Login successful"
+// @Failure 400 {object} model.APIResponse "Invalid request body, or TOTP not set up yet (MFA_REQUIRED)"
+// @Failure 401 {object} model.APIResponse "Invalid or used challenge token, or invalid code (INVALID_CREDENTIALS)"
+// @Failure 403 {object} model.APIResponse "Account inactive or deleted (ACCOUNT_INACTIVE, ACCOUNT_DELETED)"
+// @Failure 423 {object} model.APIResponse "Account temporarily locked (ACCOUNT_LOCKED)"
+// @Failure 429 {object} model.APIResponse "Too many attempts (TOO_MANY_REQUESTS)"
+// @Router /auth/mfa/verify [post]
+func (s *AuthService) VerifyMFASwagger() {}
+
+// SetupMFAChallenge godoc
+// @Summary Set up TOTP during login
+// @Description For users whose role requires MFA but who have not enrolled yet (enrollmentRequired=true in the login response). Returns a new TOTP secret; confirm it by calling /auth/mfa/verify with the first code.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.MFAChallengeSetupRequest true "Challenge token"
+// @Success 200 {object} model.APIResponse{data=model.MFASetupResponse} "TOTP secret and otpauth URL"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Invalid or used challenge token"
+// @Failure 409 {object} model.APIResponse "MFA is already enabled"
+// @Router /auth/mfa/challenge/setup [post]
+func (s *AuthService) SetupMFAChallengeSwagger() {}
+
+// SetupMFA godoc
+// @Summary Start TOTP enrollment
+// @Description Generate a new TOTP secret for the current user. MFA stays disabled until the first code is confirmed at /auth/mfa/activate.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse{data=model.MFASetupResponse} "TOTP secret and otpauth URL"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 409 {object} model.APIResponse "MFA is already enabled"
+// @Router /auth/mfa/setup [post]
+func (s *AuthService) SetupMFASwagger() {}
+
+// ActivateMFA godoc
+// @Summary Confirm TOTP enrollment
+// @Description Enable MFA by confirming a code from the authenticator app. Returns one-time recovery codes, shown only once.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.MFAActivateRequest true "TOTP code"
+// @Success 200 {object} model.APIResponse{data=model.MFARecoveryCodesResponse} "MFA enabled"
+// @Failure 400 {object} model.APIResponse "Invalid request body or setup not started"
+// @Failure 401 {object} model.APIResponse "Invalid code (INVALID_CREDENTIALS)"
+// @Failure 409 {object} model.APIResponse "MFA is already enabled"
+// @Router /auth/mfa/activate [post]
+func (s *AuthService) ActivateMFASwagger() {}
+
+// DisableMFA godoc
+// @Summary Disable MFA
+// @Description Disable MFA for the current user. Requires the password and a TOTP or recovery code. Not allowed when the role requires MFA.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.MFADisableRequest true "Password and code"
+// @Success 200 {object} model.APIResponse "MFA disabled"
+// @Failure 400 {object} model.APIResponse "Invalid request body or MFA not enabled"
+// @Failure 401 {object} model.APIResponse "Invalid password or code (INVALID_CREDENTIALS)"
+// @Failure 409 {object} model.APIResponse "MFA is required for the role"
+// @Router /auth/mfa/disable [post]
+func (s *AuthService) DisableMFASwagger() {}
+
+// GetSessions godoc
+// @Summary List active sessions
+// @Description List the current user's active login sessions (device/user-agent, IP, login time, last use). The session used by this request is marked as current.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse{data=[]model.SessionResponse} "Active sessions"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Router /auth/sessions [get]
+func (s *AuthService) GetSessionsSwagger() {}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description Log out one of the current user's sessions. Its refresh token is revoked and its access token is rejected immediately.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID (UUID)"
+// @Success 200 {object} model.APIResponse "Session revoked"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 404 {object} model.APIResponse "Session not found"
+// @Router /auth/sessions/{id} [delete]
+func (s *AuthService) RevokeSessionSwagger() {}
+
+// ==================== API TOKEN SERVICE ANNOTATIONS ======================
+
+// GetTokens godoc
+// @Summary List API tokens
+// @Description List the current user's personal API tokens that are not revoked. Only the token prefix is shown. Not available when authenticated with an API token.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse{data=[]model.APITokenResponse} "API tokens"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Authenticated with an API token"
+// @Router /auth/tokens [get]
+func (s *APITokenService) GetTokensSwagger() {}
+
+// CreateToken godoc
+// @Summary Create API token
+// @Description Create a personal API token for scripts and integrations. Scopes must be a subset of the current user's permissions; expires_in_days 0 means no expiry. The token is returned only once. Use it as "Authorization: Bearer <token>".
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.APITokenCreateRequest true "Token name, scopes and optional expiry"
+// @Success 201 {object} model.APIResponse{data=model.APITokenCreatedResponse} "API token created"
+// @Failure 400 {object} model.APIResponse "Invalid request body or scope not allowed"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Authenticated with an API token"
+// @Failure 422 {object} model.APIResponse "Validation error"
+// @Router /auth/tokens [post]
+func (s *APITokenService) CreateTokenSwagger() {}
+
+// RevokeToken godoc
+// @Summary Revoke API token
+// @Description Revoke one of the current user's API tokens. It is rejected immediately.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API token ID"
+// @Success 200 {object} model.APIResponse "API token revoked"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Authenticated with an API token"
+// @Failure 404 {object} model.APIResponse "API token not found"
+// @Router /auth/tokens/{id} [delete]
+func (s *APITokenService) RevokeTokenSwagger() {}
+
 // ==================== USER SERVICE ANNOTATIONS ======================
 
 // CreateUser godoc
@@ -72,7 +287,7 @@ func (s *AuthService) LogoutSwagger() {}
 // @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
 // @Failure 404 {object} model.APIResponse "Role not found"
 // @Failure 409 {object} model.APIResponse "Username/Email/StudentID/LecturerID already exists"
-// @Failure 422 {object} model.APIResponse "Validation error"
+// @Failure 422 {object} model.APIResponse "Validation failed or password violates the password policy"
 // @Router /users [post]
 func (s *UserService) CreateUserSwagger() {}
 
@@ -121,13 +336,13 @@ func (s *UserService) GetUserByIDSwagger() {}
 // @Failure 401 {object} model.APIResponse "Unauthorized"
 // @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
 // @Failure 404 {object} model.APIResponse "User not found"
-// @Failure 409 {object} model.APIResponse "Email already used by another user"
+// @Failure 409 {object} model.APIResponse "Email already used by another user, or deactivating own account / last active user with user:manage"
 // @Router /users/{id} [put]
 func (s *UserService) UpdateUserSwagger() {}
 
 // DeleteUser godoc
 // @Summary Delete user (Admin only)
-// @Description Delete user and associated profile (student/lecturer). This will also cascade delete related data.
+// @Description Soft delete user: the account is marked deleted (account_status = "deleted"), existing tokens stop working immediately, and the student/lecturer profile is kept.
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -137,6 +352,7 @@ func (s *UserService) UpdateUserSwagger() {}
 // @Failure 401 {object} model.APIResponse "Unauthorized"
 // @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
 // @Failure 404 {object} model.APIResponse "User not found"
+// @Failure 409 {object} model.APIResponse "Cannot delete own account or last active user with user:manage"
 // @Router /users/{id} [delete]
 func (s *UserService) DeleteUserSwagger() {}
 
@@ -154,9 +370,217 @@ func (s *UserService) DeleteUserSwagger() {}
 // @Failure 401 {object} model.APIResponse "Unauthorized"
 // @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
 // @Failure 404 {object} model.APIResponse "User or role not found"
+// @Failure 409 {object} model.APIResponse "Cannot demote own account or last active user with user:manage"
 // @Router /users/{id}/role [put]
 func (s *UserService) AssignRoleSwagger() {}
 
+// UnlockUser godoc
+// @Summary Unlock user account (Admin only)
+// @Description Clear the failed login counter and lift a lockout caused by too many failed logins.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} model.APIResponse{data=model.UserResponse} "User unlocked successfully"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "User not found"
+// @Router /users/{id}/unlock [put]
+func (s *UserService) UnlockUserSwagger() {}
+
+// IssuePasswordReset godoc
+// @Summary Issue password reset token (Admin only)
+// @Description Issue a one-time, time-limited password reset token for a user. Previously issued tokens for the user stop working. The token is redeemed via POST /auth/password/reset.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 201 {object} model.APIResponse{data=model.PasswordResetTokenResponse} "Reset token issued"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "User not found"
+// @Router /users/{id}/password-reset [post]
+func (s *UserService) IssuePasswordResetSwagger() {}
+
+// GetUserSessions godoc
+// @Summary List a user's active sessions (Admin only)
+// @Description List the active login sessions of a user
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} model.APIResponse{data=[]model.SessionResponse} "Active sessions"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "User not found"
+// @Router /users/{id}/sessions [get]
+func (s *UserService) GetUserSessionsSwagger() {}
+
+// RevokeUserSession godoc
+// @Summary Revoke a user's session (Admin only)
+// @Description Log out one session of a user. Its refresh token is revoked and its access token is rejected immediately.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param sessionId path string true "Session ID (UUID)"
+// @Success 200 {object} model.APIResponse "Session revoked"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "Session not found"
+// @Router /users/{id}/sessions/{sessionId} [delete]
+func (s *UserService) RevokeUserSessionSwagger() {}
+
+// Impersonate godoc
+// @Summary Impersonate a user (Admin only)
+// @Description Issue a short-lived access token acting as another user, for support. The token carries both the admin (claim "act") and the user. Every response made with it has the X-Impersonated-By header, and every request is written to the audit log. By default only GET requests are allowed (IMPERSONATION_ALLOW_WRITE). No refresh token is issued; POST /auth/logout ends the impersonation. Administrators cannot be impersonated.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param request body model.ImpersonateRequest true "Reason (recorded in the audit log)"
+// @Success 201 {object} model.APIResponse{data=model.ImpersonationResponse} "Impersonation started"
+// @Failure 400 {object} model.APIResponse "Invalid request body or impersonating yourself"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only, target is an administrator, or already impersonating"
+// @Failure 404 {object} model.APIResponse "User not found"
+// @Failure 409 {object} model.APIResponse "User is inactive"
+// @Failure 422 {object} model.APIResponse "Validation error"
+// @Router /users/{id}/impersonate [post]
+func (s *UserService) ImpersonateSwagger() {}
+
+// ==================== ROLE SERVICE ANNOTATIONS ======================
+
+// GetRoles godoc
+// @Summary List roles (Admin only)
+// @Description Get all roles with their permissions. Built-in roles (Admin, Mahasiswa, Dosen Wali) are flagged with built_in=true.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse{data=[]model.RoleResponse} "List of roles"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Router /roles [get]
+func (s *RoleService) GetRolesSwagger() {}
+
+// GetRoleByID godoc
+// @Summary Get role detail (Admin only)
+// @Description Get a role with its permissions
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID (UUID)"
+// @Success 200 {object} model.APIResponse{data=model.RoleResponse} "Role detail"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "Role not found"
+// @Router /roles/{id} [get]
+func (s *RoleService) GetRoleByIDSwagger() {}
+
+// CreateRole godoc
+// @Summary Create role (Admin only)
+// @Description Create a new role, optionally with an initial list of permission names.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.RoleCreateRequest true "Role data"
+// @Success 201 {object} model.APIResponse{data=model.RoleResponse} "Role created"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "Permission not found"
+// @Failure 409 {object} model.APIResponse "Role name already exists"
+// @Failure 422 {object} model.APIResponse "Validation error"
+// @Router /roles [post]
+func (s *RoleService) CreateRoleSwagger() {}
+
+// UpdateRole godoc
+// @Summary Update role (Admin only)
+// @Description Update role name and/or description. Built-in roles cannot be renamed.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID (UUID)"
+// @Param request body model.RoleUpdateRequest true "Fields to update"
+// @Success 200 {object} model.APIResponse{data=model.RoleResponse} "Role updated"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "Role not found"
+// @Failure 409 {object} model.APIResponse "Built-in role cannot be renamed or name already exists"
+// @Router /roles/{id} [put]
+func (s *RoleService) UpdateRoleSwagger() {}
+
+// DeleteRole godoc
+// @Summary Delete role (Admin only)
+// @Description Delete a custom role. Built-in roles and roles still assigned to users cannot be deleted.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID (UUID)"
+// @Success 200 {object} model.APIResponse "Role deleted"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "Role not found"
+// @Failure 409 {object} model.APIResponse "Built-in role or role still assigned to users"
+// @Router /roles/{id} [delete]
+func (s *RoleService) DeleteRoleSwagger() {}
+
+// AttachPermission godoc
+// @Summary Attach permission to role (Admin only)
+// @Description Grant a permission to a role. Takes effect on the next request of every user with the role.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID (UUID)"
+// @Param request body model.RolePermissionRequest true "Permission name"
+// @Success 200 {object} model.APIResponse{data=model.RoleResponse} "Permission attached"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "Role or permission not found"
+// @Router /roles/{id}/permissions [post]
+func (s *RoleService) AttachPermissionSwagger() {}
+
+// DetachPermission godoc
+// @Summary Detach permission from role (Admin only)
+// @Description Revoke a permission from a role. Takes effect on the next request of every user with the role. user:manage cannot be removed from Admin.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID (UUID)"
+// @Param permission path string true "Permission name, e.g. achievement:verify"
+// @Success 200 {object} model.APIResponse{data=model.RoleResponse} "Permission detached"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Failure 404 {object} model.APIResponse "Role or permission not found"
+// @Failure 409 {object} model.APIResponse "Admin role must keep user:manage"
+// @Router /roles/{id}/permissions/{permission} [delete]
+func (s *RoleService) DetachPermissionSwagger() {}
+
+// GetPermissions godoc
+// @Summary List permissions (Admin only)
+// @Description Get all permissions that can be attached to roles
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.APIResponse{data=[]model.PermissionResponse} "List of permissions"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Admin only"
+// @Router /permissions [get]
+func (s *RoleService) GetPermissionsSwagger() {}
+
 // ==================== STUDENT SERVICE ANNOTATIONS ======================
 
 // GetAllStudents godoc
@@ -259,6 +683,56 @@ func (s *LecturerService) GetAllLecturersSwagger() {}
 // @Router /lecturers/{id}/advisees [get]
 func (s *LecturerService) GetLecturerAdviseesSwagger() {}
 
+// GetDelegations godoc
+// @Summary Get verifier delegations of a lecturer
+// @Description Delegations granted by or to the lecturer, newest first. active is true when the delegation is not revoked and covers today. Dosen Wali can only view own delegations, Admin can view all.
+// @Tags Lecturers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lecturer ID (UUID)"
+// @Success 200 {object} model.APIResponse{data=[]model.VerifierDelegation} "List of delegations"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not own delegations"
+// @Failure 404 {object} model.APIResponse "Lecturer not found"
+// @Router /lecturers/{id}/delegations [get]
+func (s *LecturerService) GetDelegationsSwagger() {}
+
+// CreateDelegation godoc
+// @Summary Delegate verification of advisees to another lecturer
+// @Description Grant another lecturer verify/reject rights over the advisees of lecturer {id} between starts_on and ends_on (inclusive). Verifications by the delegate are recorded in history as on behalf of the advisor. Dosen Wali can only delegate own advisees, Admin can delegate for any lecturer.
+// @Tags Lecturers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Advisor lecturer ID (UUID)"
+// @Param request body model.CreateDelegationRequest true "Delegate and date range (YYYY-MM-DD)"
+// @Success 201 {object} model.APIResponse{data=model.VerifierDelegation} "Delegation created"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not own advisees"
+// @Failure 404 {object} model.APIResponse "Lecturer not found"
+// @Failure 422 {object} model.APIResponse "Validation error, invalid date range, self delegation or delegate not found"
+// @Router /lecturers/{id}/delegations [post]
+func (s *LecturerService) CreateDelegationSwagger() {}
+
+// RevokeDelegation godoc
+// @Summary Revoke a verifier delegation
+// @Description Revoke a delegation before it ends. Dosen Wali can only revoke own delegations, Admin can revoke any.
+// @Tags Lecturers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Advisor lecturer ID (UUID)"
+// @Param delegationId path string true "Delegation ID (UUID)"
+// @Success 200 {object} model.APIResponse "Delegation revoked"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not own delegations"
+// @Failure 404 {object} model.APIResponse "Delegation not found"
+// @Failure 409 {object} model.APIResponse "Delegation already revoked"
+// @Router /lecturers/{id}/delegations/{delegationId} [delete]
+func (s *LecturerService) RevokeDelegationSwagger() {}
+
 // ==================== ACHIEVEMENT SERVICE ANNOTATIONS ======================
 
 // CreateAchievement godoc
@@ -311,8 +785,8 @@ func (s *AchievementService) GetAchievementsSwagger() {}
 func (s *AchievementService) GetAchievementByIDSwagger() {}
 
 // UpdateAchievement godoc
-// @Summary Update achievement (Mahasiswa only, draft status)
-// @Description Update achievement data. Can only update if status is 'draft' and you are the owner.
+// @Summary Update achievement (Mahasiswa only, draft or rejected status)
+// @Description Update achievement data. Can only update if status is 'draft', 'rejected' or 'revision_requested' and you are the owner.
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -320,64 +794,143 @@ func (s *AchievementService) GetAchievementByIDSwagger() {}
 // @Param id path string true "Achievement Reference ID (UUID)"
 // @Param request body model.AchievementUpdateRequest true "Update data (all fields optional)"
 // @Success 200 {object} model.APIResponse{data=model.AchievementResponse} "Achievement updated successfully"
-// @Failure 400 {object} model.APIResponse "Can only update achievements with status 'draft'"
 // @Failure 401 {object} model.APIResponse "Unauthorized"
 // @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
 // @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Failure 409 {object} model.APIResponse "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states)"
 // @Router /achievements/{id} [put]
 func (s *AchievementService) UpdateAchievementSwagger() {}
 
 // DeleteAchievement godoc
 // @Summary Delete achievement (Mahasiswa only, draft status)
-// @Description Soft delete achievement. Can only delete if status is 'draft' and you are the owner.
+// @Description Soft delete achievement. Can only delete if status is 'draft' and you are the owner. The achievement can be restored by an admin until it is purged permanently (together with its attachment files) after the retention period (DELETED_ACHIEVEMENT_RETENTION, default 30 days).
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Achievement Reference ID (UUID)"
 // @Success 200 {object} model.APIResponse "Achievement deleted successfully"
-// @Failure 400 {object} model.APIResponse "Can only delete achievements with status 'draft'"
 // @Failure 401 {object} model.APIResponse "Unauthorized"
 // @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
 // @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Failure 409 {object} model.APIResponse "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request"
 // @Router /achievements/{id} [delete]
 func (s *AchievementService) DeleteAchievementSwagger() {}
 
+// GetDeletedAchievements godoc
+// @Summary Get deleted achievements (Admin only)
+// @Description List soft-deleted achievements that have not been purged yet, most recently deleted first. Each item includes deleted_at.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size (max 100)" default(10)
+// @Success 200 {object} model.APIResponse{data=model.AchievementListResponse} "List of deleted achievements"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - requires user:manage"
+// @Router /achievements/deleted [get]
+func (s *AchievementService) GetDeletedAchievementsSwagger() {}
+
+// RestoreAchievement godoc
+// @Summary Restore deleted achievement (Admin only)
+// @Description Undo a soft delete: the achievement goes back to 'draft' for its owner and the restore is recorded in history. Not possible once the achievement has been purged.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement Reference ID (UUID)"
+// @Success 200 {object} model.APIResponse{data=object} "Achievement restored to draft"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - requires user:manage"
+// @Failure 404 {object} model.APIResponse "Achievement not found or already purged"
+// @Failure 409 {object} model.APIResponse "Achievement is not deleted (code INVALID_TRANSITION) or status was changed by another request"
+// @Router /achievements/{id}/restore [post]
+func (s *AchievementService) RestoreAchievementSwagger() {}
+
 // SubmitForVerification godoc
 // @Summary Submit achievement for verification (Mahasiswa only)
-// @Description Submit draft achievement to advisor for verification. Changes status from 'draft' to 'submitted'.
+// @Description Submit a draft achievement, or resubmit a rejected / revision_requested one after revision, to the advisor. Changes status to 'submitted', increments submission_count and stores a snapshot of the content. The previous rejection note is kept.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Achievement Reference ID (UUID)"
 // @Success 200 {object} model.APIResponse{data=object} "Achievement submitted with new status and timestamp"
-// @Failure 400 {object} model.APIResponse "Achievement already submitted or processed"
 // @Failure 401 {object} model.APIResponse "Unauthorized"
 // @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
 // @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Failure 409 {object} model.APIResponse "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request"
 // @Router /achievements/{id}/submit [post]
 func (s *AchievementService) SubmitForVerificationSwagger() {}
 
+// WithdrawSubmission godoc
+// @Summary Withdraw a submitted achievement back to draft (Mahasiswa only)
+// @Description Pull a submitted achievement back to 'draft' so it can be corrected. Only allowed while no reviewer has picked it up: no approval stage has been decided and nobody other than the student has commented since it was submitted. Clears submitted_at and records the withdrawal in history. submission_count is kept.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement Reference ID (UUID)"
+// @Success 200 {object} model.APIResponse{data=object} "Submission withdrawn, status is 'draft'"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
+// @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Failure 409 {object} model.APIResponse "Not submitted (code INVALID_TRANSITION), already under review, or status was changed by another request"
+// @Router /achievements/{id}/withdraw [post]
+func (s *AchievementService) WithdrawSubmissionSwagger() {}
+
 // VerifyAchievement godoc
 // @Summary Verify achievement (Dosen Wali only)
-// @Description Approve submitted achievement. Can only verify if you are the advisor of the student and status is 'submitted'.
+// @Description Approve submitted achievement. Can only verify if you are the approver of the current approval stage (default chain: the advisor of the student, or an active delegate) and status is 'submitted'. Achievements matching a multi-stage approval chain (APPROVAL_CHAINS_FILE, keyed by achievement_type, min_points and details.competitionLevel) stay 'submitted' after non-final stages; the response then contains the approval progress. Only the final stage sets status 'verified'.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Achievement Reference ID (UUID)"
 // @Success 200 {object} model.APIResponse{data=object} "Achievement verified with timestamp and verifier info"
-// @Failure 400 {object} model.APIResponse "Achievement must be in 'submitted' status"
 // @Failure 401 {object} model.APIResponse "Unauthorized"
-// @Failure 403 {object} model.APIResponse "Forbidden - Not advisor of this student"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not the approver of the current stage"
 // @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Failure 409 {object} model.APIResponse "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request"
 // @Router /achievements/{id}/verify [post]
 func (s *AchievementService) VerifyAchievementSwagger() {}
 
+// BatchVerify godoc
+// @Summary Verify many achievements at once (Dosen Wali only)
+// @Description Verify up to 100 achievements. Each item gets the same checks as POST /achievements/{id}/verify (approver of the current approval stage, status 'submitted'). Items at a non-final stage advance to the next stage and stay 'submitted'. mode 'atomic' (default): if any item fails nothing is changed and the response is 409; otherwise all items are saved in one transaction. mode 'partial': valid items are processed even if others fail. The response always lists a result per item, in request order.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.BatchVerifyRequest true "Achievement IDs and mode"
+// @Success 200 {object} model.APIResponse{data=model.BatchResult} "Batch processed, per-item results"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 409 {object} model.APIResponse{data=model.BatchResult} "Atomic batch aborted, nothing was changed"
+// @Failure 422 {object} model.APIResponse "Validation error"
+// @Router /achievements/batch/verify [post]
+func (s *AchievementService) BatchVerifySwagger() {}
+
+// BatchReject godoc
+// @Summary Reject many achievements at once (Dosen Wali only)
+// @Description Reject up to 100 achievements. Each item uses its own rejection_note, or the shared rejection_note if empty; every item must end up with a note. Checks, modes and response are the same as batch verify.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.BatchRejectRequest true "Items with rejection notes and mode"
+// @Success 200 {object} model.APIResponse{data=model.BatchResult} "Batch processed, per-item results"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 409 {object} model.APIResponse{data=model.BatchResult} "Atomic batch aborted, nothing was changed"
+// @Failure 422 {object} model.APIResponse "Validation error - rejection note required"
+// @Router /achievements/batch/reject [post]
+func (s *AchievementService) BatchRejectSwagger() {}
+
 // RejectAchievement godoc
 // @Summary Reject achievement (Dosen Wali only)
-// @Description Reject submitted achievement with mandatory rejection note. Can only reject if you are the advisor and status is 'submitted'.
+// @Description Reject submitted achievement with mandatory rejection note. Can only reject if you are the approver of the current approval stage and status is 'submitted'.
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -385,17 +938,69 @@ func (s *AchievementService) VerifyAchievementSwagger() {}
 // @Param id path string true "Achievement Reference ID (UUID)"
 // @Param request body model.RejectAchievementRequest true "Rejection note (required)"
 // @Success 200 {object} model.APIResponse{data=object} "Achievement rejected with note"
-// @Failure 400 {object} model.APIResponse "Achievement must be in 'submitted' status"
 // @Failure 401 {object} model.APIResponse "Unauthorized"
-// @Failure 403 {object} model.APIResponse "Forbidden - Not advisor of this student"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not the approver of the current stage"
 // @Failure 404 {object} model.APIResponse "Achievement not found"
 // @Failure 422 {object} model.APIResponse "Validation error - rejection note required"
+// @Failure 409 {object} model.APIResponse "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request"
 // @Router /achievements/{id}/reject [post]
 func (s *AchievementService) RejectAchievementSwagger() {}
 
+// RequestRevision godoc
+// @Summary Request changes on an achievement (Dosen Wali only)
+// @Description Send a submitted achievement back to the student for revision (status 'revision_requested'). Unlike reject, this does not set rejection_note; the note is recorded in the history. The student can edit and resubmit.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement Reference ID (UUID)"
+// @Param request body model.RequestRevisionRequest true "What needs to be changed (required)"
+// @Success 200 {object} model.APIResponse{data=object} "Revision requested"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not the approver of the current stage"
+// @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Failure 409 {object} model.APIResponse "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request"
+// @Failure 422 {object} model.APIResponse "Validation error - note required"
+// @Router /achievements/{id}/request-revision [post]
+func (s *AchievementService) RequestRevisionSwagger() {}
+
+// GetComments godoc
+// @Summary Get achievement comments
+// @Description Comment threads on the achievement, oldest first. Replies are nested under their parent comment.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement Reference ID (UUID)"
+// @Success 200 {object} model.APIResponse{data=object} "Comment threads (data.comments) and total"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not authorized to view this achievement"
+// @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Router /achievements/{id}/comments [get]
+func (s *AchievementService) GetCommentsSwagger() {}
+
+// CreateComment godoc
+// @Summary Add a comment or reply
+// @Description Add a comment to the achievement, or reply to another comment with parent_id. Anyone who can view the achievement can comment. @username mentions of active users who can view the achievement are stored with the comment and notify the mentioned user. Comments also appear in the achievement history.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement Reference ID (UUID)"
+// @Param request body model.AchievementCommentRequest true "Comment body (max 2000 characters) and optional parent_id"
+// @Success 201 {object} model.APIResponse{data=model.AchievementComment} "Comment added"
+// @Failure 400 {object} model.APIResponse "Invalid request body"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not authorized to view this achievement"
+// @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Failure 422 {object} model.APIResponse "Validation error or parent comment not on this achievement"
+// @Router /achievements/{id}/comments [post]
+func (s *AchievementService) CreateCommentSwagger() {}
+
 // UploadAttachment godoc
 // @Summary Upload attachment file (Mahasiswa only)
-// @Description Upload file attachment to achievement. Accepts PDF, JPG, PNG with max size 5MB. Can upload for 'draft' or 'submitted' status.
+// @Description Upload file attachment to achievement. Accepts PDF, JPG, PNG with max size 5MB. Can upload for 'draft', 'submitted', 'rejected' or 'revision_requested' status.
 // @Tags Achievements
 // @Accept multipart/form-data
 // @Produce json
@@ -407,12 +1012,13 @@ func (s *AchievementService) RejectAchievementSwagger() {}
 // @Failure 401 {object} model.APIResponse "Unauthorized"
 // @Failure 403 {object} model.APIResponse "Forbidden - Not your achievement"
 // @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Failure 409 {object} model.APIResponse "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states)"
 // @Router /achievements/{id}/attachments [post]
 func (s *AchievementService) UploadAttachmentSwagger() {}
 
 // GetAchievementHistory godoc
 // @Summary Get achievement status history
-// @Description Get timeline of achievement status changes (draft → submitted → verified/rejected). Includes actor info and notes.
+// @Description Get every recorded status transition of the achievement, oldest first (including repeated submissions and rejections). Each entry has the from/to status, actor, actor role, note and timestamp. Comments are merged into the timeline as entries with type 'comment'.
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -425,6 +1031,21 @@ func (s *AchievementService) UploadAttachmentSwagger() {}
 // @Router /achievements/{id}/history [get]
 func (s *AchievementService) GetAchievementHistorySwagger() {}
 
+// GetAchievementChanges godoc
+// @Summary Get changes since the previous submission
+// @Description Field-level changes of the achievement compared to the previous submission, so advisors can review a revised achievement. While submitted/verified the current content is compared to the submission before the latest one; while draft/rejected it is compared to the latest submission. Includes the rejection note given for the compared submission.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement Reference ID (UUID)"
+// @Success 200 {object} model.APIResponse{data=model.AchievementChangesResponse} "Changes since the previous submission (empty if there is none)"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden - Not authorized to view this achievement"
+// @Failure 404 {object} model.APIResponse "Achievement not found"
+// @Router /achievements/{id}/changes [get]
+func (s *AchievementService) GetAchievementChangesSwagger() {}
+
 // ==================== REPORT SERVICE ANNOTATIONS ======================
 
 // GetStatistics godoc
@@ -454,4 +1075,46 @@ func (s *ReportService) GetStatisticsSwagger() {}
 // @Failure 403 {object} model.APIResponse "Forbidden - Not authorized for this student"
 // @Failure 404 {object} model.APIResponse "Student not found"
 // @Router /reports/student/{id} [get]
-func (s *ReportService) GetStudentReportSwagger() {}
\ No newline at end of file
+func (s *ReportService) GetStudentReportSwagger() {}
+// GetOverdueReport godoc
+// @Summary Get overdue verification report
+// @Description List submitted achievements waiting longer than the reminder threshold (VERIFICATION_REMINDER_AFTER), oldest first. Items past VERIFICATION_ESCALATE_AFTER are in stage "escalation". Dosen Wali sees advisees, department reviewers see their program study, Admin sees all.
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param stage query string false "Filter by SLA stage" Enums(reminder, escalation)
+// @Success 200 {object} model.APIResponse{data=model.OverdueReport} "Overdue achievements"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 403 {object} model.APIResponse "Forbidden"
+// @Failure 422 {object} model.APIResponse "Invalid stage"
+// @Router /reports/overdue [get]
+func (s *VerificationSLAService) GetOverdueReportSwagger() {}
+
+// ==================== NOTIFICATION SERVICE ANNOTATIONS ======================
+
+// GetNotifications godoc
+// @Summary Get my notifications
+// @Description In-app notifications of the current user, newest first (max 100), e.g. verification reminders and escalations
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only unread notifications"
+// @Success 200 {object} model.APIResponse{data=[]model.Notification} "Notifications"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Router /notifications [get]
+func (s *NotificationService) GetNotificationsSwagger() {}
+
+// MarkNotificationRead godoc
+// @Summary Mark notification as read
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID (UUID)"
+// @Success 200 {object} model.APIResponse "Notification marked as read"
+// @Failure 401 {object} model.APIResponse "Unauthorized"
+// @Failure 404 {object} model.APIResponse "Notification not found"
+// @Router /notifications/{id}/read [post]
+func (s *NotificationService) MarkNotificationReadSwagger() {}
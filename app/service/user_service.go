@@ -3,12 +3,14 @@ package service
 import (
 	"math"
 	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
 	"UASBE/app/model"
 	"UASBE/app/repository"
+	"UASBE/config"
 	"UASBE/utils"
 )
 
@@ -18,6 +20,10 @@ type UserService struct {
 	permRepo     repository.PermissionRepository
 	studentRepo  repository.StudentRepository
 	lecturerRepo repository.LecturerRepository
+	revokeRepo   repository.TokenRevocationRepository
+	resetRepo    repository.PasswordResetRepository
+	sessionRepo  repository.SessionRepository
+	auditRepo    repository.AuditLogRepository
 	validate     *validator.Validate
 }
 
@@ -27,6 +33,10 @@ func NewUserService(
 	permRepo repository.PermissionRepository,
 	studentRepo repository.StudentRepository,
 	lecturerRepo repository.LecturerRepository,
+	revokeRepo repository.TokenRevocationRepository,
+	resetRepo repository.PasswordResetRepository,
+	sessionRepo repository.SessionRepository,
+	auditRepo repository.AuditLogRepository,
 ) *UserService {
 	return &UserService{
 		userRepo:     userRepo,
@@ -34,6 +44,10 @@ func NewUserService(
 		permRepo:     permRepo,
 		studentRepo:  studentRepo,
 		lecturerRepo: lecturerRepo,
+		revokeRepo:   revokeRepo,
+		resetRepo:    resetRepo,
+		sessionRepo:  sessionRepo,
+		auditRepo:    auditRepo,
 		validate:     validator.New(),
 	}
 }
@@ -61,6 +75,14 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	// Validasi kekuatan password sesuai policy
+	if err := utils.ValidatePasswordStrength(req.Password); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
 	// Cek username sudah ada atau belum
 	existingUser, _ := s.userRepo.FindByUsername(req.Username)
 	if existingUser != nil {
@@ -115,7 +137,7 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 	}
 
 	// Create profile berdasarkan role
-	if role.Name == "Mahasiswa" && req.StudentProfile != nil {
+	if role.Name == model.RoleMahasiswa && req.StudentProfile != nil {
 		// Validasi student profile
 		if err := s.validate.Struct(req.StudentProfile); err != nil {
 			// Rollback user creation (delete user)
@@ -166,7 +188,7 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 		}
 	}
 
-	if role.Name == "Dosen Wali" && req.LecturerProfile != nil {
+	if role.Name == model.RoleDosenWali && req.LecturerProfile != nil {
 		// Validasi lecturer profile
 		if err := s.validate.Struct(req.LecturerProfile); err != nil {
 			s.userRepo.Delete(user.ID)
@@ -340,6 +362,23 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 		user.FullName = req.FullName
 	}
 
+	deactivated := false
+	if req.IsActive != nil {
+		deactivated = user.IsActive && !*req.IsActive
+	}
+	if deactivated {
+		if reason, err := s.userManagerLockout(c.Locals("user").(*model.JWTClaims), user); err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to check user management access",
+			})
+		} else if reason != "" {
+			return c.Status(409).JSON(model.APIResponse{
+				Status: "error",
+				Error:  reason,
+			})
+		}
+	}
 	if req.IsActive != nil {
 		user.IsActive = *req.IsActive
 	}
@@ -352,6 +391,16 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	// User dinonaktifkan: semua access token yang sudah terbit langsung tidak berlaku
+	if deactivated {
+		if err := s.revokeRepo.SetTokensValidAfter(user.ID, time.Now()); err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to revoke user tokens",
+			})
+		}
+	}
+
 	roleName, _ := s.userRepo.GetRoleName(user.RoleID)
 	userResponse := s.buildUserResponse(user, roleName)
 
@@ -369,37 +418,41 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 func (s *UserService) DeleteUser(c *fiber.Ctx) error {
 	userID := c.Params("id")
 
-	// Cari user dulu
+	// Cari user dulu (user yang sudah dihapus dianggap tidak ada)
 	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
+	if err != nil || user.DeletedAt != nil {
 		return c.Status(404).JSON(model.APIResponse{
 			Status: "error",
 			Error:  "user not found",
 		})
 	}
 
-	// Hapus profile dulu (jika ada) - CASCADE DELETE seharusnya handle ini
-	roleName, _ := s.userRepo.GetRoleName(user.RoleID)
-
-	if roleName == "Mahasiswa" {
-		student, _ := s.studentRepo.FindByUserID(userID)
-		if student != nil {
-			s.studentRepo.Delete(student.ID)
-		}
+	if reason, err := s.userManagerLockout(c.Locals("user").(*model.JWTClaims), user); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to check user management access",
+		})
+	} else if reason != "" {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  reason,
+		})
 	}
 
-	if roleName == "Dosen Wali" {
-		lecturer, _ := s.lecturerRepo.FindByUserID(userID)
-		if lecturer != nil {
-			s.lecturerRepo.Delete(lecturer.ID)
-		}
+	// Soft delete: baris user (dan profile student/lecturer) tetap ada
+	// supaya status "deleted" bisa dibaca saat login ditolak
+	if err := s.userRepo.SoftDelete(userID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to delete user",
+		})
 	}
 
-	// Hapus user
-	if err := s.userRepo.Delete(userID); err != nil {
+	// Access token milik user yang dihapus langsung tidak berlaku
+	if err := s.revokeRepo.SetTokensValidAfter(userID, time.Now()); err != nil {
 		return c.Status(500).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "failed to delete user",
+			Error:  "failed to revoke user tokens",
 		})
 	}
 
@@ -443,6 +496,31 @@ func (s *UserService) AssignRole(c *fiber.Ctx) error {
 		})
 	}
 
+	// Role baru tanpa user:manage: admin tidak boleh menurunkan dirinya sendiri
+	// atau admin aktif terakhir
+	if user.RoleID != role.ID {
+		perms, err := s.permRepo.GetPermissionsByRoleID(role.ID)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to get role permissions",
+			})
+		}
+		if !hasPermission(perms, model.PermissionUserManage) {
+			if reason, err := s.userManagerLockout(c.Locals("user").(*model.JWTClaims), user); err != nil {
+				return c.Status(500).JSON(model.APIResponse{
+					Status: "error",
+					Error:  "failed to check user management access",
+				})
+			} else if reason != "" {
+				return c.Status(409).JSON(model.APIResponse{
+					Status: "error",
+					Error:  reason,
+				})
+			}
+		}
+	}
+
 	// Update role
 	if err := s.userRepo.UpdateRole(userID, role.ID); err != nil {
 		return c.Status(500).JSON(model.APIResponse{
@@ -451,6 +529,16 @@ func (s *UserService) AssignRole(c *fiber.Ctx) error {
 		})
 	}
 
+	// Role berubah: access token lama masih membawa role & permission lama
+	if user.RoleID != role.ID {
+		if err := s.revokeRepo.SetTokensValidAfter(userID, time.Now()); err != nil {
+			return c.Status(500).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "failed to revoke user tokens",
+			})
+		}
+	}
+
 	// Refresh user data
 	user, _ = s.userRepo.FindByID(userID)
 	userResponse := s.buildUserResponse(user, role.Name)
@@ -462,6 +550,200 @@ func (s *UserService) AssignRole(c *fiber.Ctx) error {
 	})
 }
 
+//
+// ==================== UNLOCK USER (PUT /users/:id/unlock) ======================
+// Buka kunci akun yang terkunci karena terlalu banyak login gagal
+//
+
+func (s *UserService) UnlockUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.DeletedAt != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	if err := s.userRepo.ResetFailedLogins(userID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to unlock user",
+		})
+	}
+
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+
+	roleName, _ := s.userRepo.GetRoleName(user.RoleID)
+	userResponse := s.buildUserResponse(user, roleName)
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "user unlocked successfully",
+		Data:    userResponse,
+	})
+}
+
+//
+// ==================== ISSUE PASSWORD RESET (POST /users/:id/password-reset) ======================
+// Terbitkan token reset sekali pakai; token diserahkan admin ke user
+// lalu ditukar lewat POST /auth/password/reset
+//
+
+func (s *UserService) IssuePasswordReset(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.DeletedAt != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	token, tokenHash, err := utils.GenerateResetToken()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate reset token",
+		})
+	}
+
+	// hanya token terbaru yang berlaku
+	if err := s.resetRepo.InvalidateByUserID(user.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate reset token",
+		})
+	}
+
+	resetToken := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		CreatedBy: claims.UserID,
+		ExpiresAt: time.Now().Add(config.AppConfig.PasswordResetTokenTTL),
+	}
+	if err := s.resetRepo.Create(resetToken); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate reset token",
+		})
+	}
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "password reset token issued",
+		Data: model.PasswordResetTokenResponse{
+			Token:     token,
+			ExpiresAt: resetToken.ExpiresAt.Format("2006-01-02 15:04:05"),
+		},
+	})
+}
+
+//
+// ==================== GET USER SESSIONS (GET /users/:id/sessions) ======================
+//
+
+func (s *UserService) GetUserSessions(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.DeletedAt != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	sessions, err := s.sessionRepo.GetActiveByUserID(user.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get sessions",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   toSessionResponses(sessions, claims.SessionID),
+	})
+}
+
+//
+// ==================== REVOKE USER SESSION (DELETE /users/:id/sessions/:sessionId) ======================
+//
+
+func (s *UserService) RevokeUserSession(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	session, err := s.sessionRepo.FindByID(c.Params("sessionId"))
+	if err != nil || session.UserID != userID || session.RevokedAt != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "session not found",
+		})
+	}
+
+	if err := s.sessionRepo.Revoke(session.ID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to revoke session",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "session revoked",
+	})
+}
+
+//
+// ==================== HELPER: ADMIN LOCKOUT ======================
+// Demote, nonaktifkan atau hapus user mencabut akses user:manage-nya seketika
+// (token lama dicabut). Ditolak untuk akun admin sendiri dan untuk user aktif
+// terakhir yang memegang user:manage, supaya sistem tidak kehilangan admin.
+//
+
+// userManagerLockout - Alasan penolakan (409), atau "" jika perubahan boleh
+func (s *UserService) userManagerLockout(claims *model.JWTClaims, user *model.User) (string, error) {
+	if user.ID == claims.UserID {
+		return "you cannot demote, deactivate or delete your own account", nil
+	}
+	if !user.IsActive || user.DeletedAt != nil {
+		return "", nil
+	}
+
+	perms, err := s.permRepo.GetPermissionsByRoleID(user.RoleID)
+	if err != nil {
+		return "", err
+	}
+	if !hasPermission(perms, model.PermissionUserManage) {
+		return "", nil
+	}
+
+	count, err := s.userRepo.CountActiveWithPermission(model.PermissionUserManage)
+	if err != nil {
+		return "", err
+	}
+	if count <= 1 {
+		return "cannot remove the last active user with user:manage", nil
+	}
+	return "", nil
+}
+
+func hasPermission(perms []string, permission string) bool {
+	for _, perm := range perms {
+		if perm == permission {
+			return true
+		}
+	}
+	return false
+}
+
 //
 // ==================== HELPER: BUILD USER RESPONSE ======================
 //
@@ -472,13 +754,22 @@ func (s *UserService) buildUserResponse(user *model.User, roleName string) *mode
 		Username:  user.Username,
 		Email:     user.Email,
 		FullName:  user.FullName,
-		Role:      roleName,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		Role:          roleName,
+		RoleID:        user.RoleID,
+		IsActive:      user.IsActive,
+		AccountStatus: user.AccountStatus(),
+		CreatedAt:     user.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	if user.AccountStatus() == model.AccountStatusLocked {
+		lockedUntil := user.LockedUntil.Format("2006-01-02 15:04:05")
+		response.LockedUntil = &lockedUntil
 	}
+	response.FailedLogins = user.FailedLoginAttempts
+	response.MFAEnabled = user.MFAEnabled
 
 	// Load profile jika ada
-	if roleName == "Mahasiswa" {
+	if roleName == model.RoleMahasiswa {
 		student, err := s.studentRepo.FindByUserID(user.ID)
 		if err == nil {
 			response.StudentProfile = &model.StudentResponse{
@@ -492,7 +783,7 @@ func (s *UserService) buildUserResponse(user *model.User, roleName string) *mode
 		}
 	}
 
-	if roleName == "Dosen Wali" {
+	if roleName == model.RoleDosenWali {
 		lecturer, err := s.lecturerRepo.FindByUserID(user.ID)
 		if err == nil {
 			response.LecturerProfile = &model.LecturerResponse{
@@ -506,3 +797,140 @@ func (s *UserService) buildUserResponse(user *model.User, roleName string) *mode
 
 	return response
 }
+
+//
+// ==================== IMPERSONATE USER (POST /users/:id/impersonate) ======================
+// Token singkat atas nama user lain untuk support ("login as"). Token membawa
+// identitas admin (claim "act"), read-only secara default, dan setiap request-nya
+// dicatat di audit_logs oleh AuthRequired.
+//
+
+func (s *UserService) Impersonate(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	// tidak boleh berantai (impersonate dari token impersonation)
+	if claims.Impersonator != nil {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "cannot impersonate while impersonating",
+		})
+	}
+
+	req := new(model.ImpersonateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "invalid request body",
+		})
+	}
+
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+
+	if userID == claims.UserID {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "cannot impersonate yourself",
+		})
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.DeletedAt != nil {
+		return c.Status(404).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user not found",
+		})
+	}
+
+	if !user.IsActive {
+		return c.Status(409).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "user is inactive",
+		})
+	}
+
+	role, err := s.roleRepo.GetRoleByID(user.RoleID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get user role",
+		})
+	}
+
+	perms, err := s.permRepo.GetPermissionsByRoleID(role.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get user permissions",
+		})
+	}
+
+	// admin lain tidak bisa di-impersonate (mencegah eskalasi lewat akun admin lain)
+	for _, perm := range perms {
+		if perm == model.PermissionUserManage {
+			return c.Status(403).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "cannot impersonate an administrator",
+			})
+		}
+	}
+
+	userRes := model.UserResponse{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		FullName:    user.FullName,
+		Role:        role.Name,
+		RoleID:      role.ID,
+		IsActive:    user.IsActive,
+		MFAEnabled:  user.MFAEnabled,
+		CreatedAt:   user.CreatedAt.Format("2006-01-02 15:04:05"),
+		Permissions: perms,
+	}
+	actor := model.ImpersonationActor{UserID: claims.UserID, Username: claims.Username}
+
+	token, expiresAt, err := utils.GenerateImpersonationJWT(userRes, actor, config.AppConfig.ImpersonationTTL)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to generate token",
+		})
+	}
+
+	// token tidak diberikan jika awal impersonation gagal dicatat
+	entry := &model.AuditLog{
+		Action:     model.AuditActionImpersonationStart,
+		ActorID:    claims.UserID,
+		SubjectID:  user.ID,
+		Method:     c.Method(),
+		Path:       c.OriginalURL(),
+		StatusCode: 201,
+		IPAddress:  c.IP(),
+		Detail:     req.Reason,
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to write audit log",
+		})
+	}
+
+	userRes.ImpersonatedBy = actor.Username
+
+	return c.Status(201).JSON(model.APIResponse{
+		Status:  "success",
+		Message: "impersonation started",
+		Data: model.ImpersonationResponse{
+			Token:          token,
+			ExpiresAt:      expiresAt.Format("2006-01-02 15:04:05"),
+			User:           userRes,
+			ImpersonatedBy: actor.Username,
+			ReadOnly:       !config.AppConfig.ImpersonationAllowWrite,
+		},
+	})
+}
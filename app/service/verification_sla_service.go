@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"UASBE/app/model"
+	"UASBE/app/policy"
+	"UASBE/app/repository"
+	"UASBE/app/workflow"
+	"UASBE/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// VerificationSLAService - Memantau prestasi yang menunggu verifikasi terlalu lama.
+// Batas waktu diambil dari config: VerificationReminderAfter (pengingat ke approver
+// tahap persetujuan saat ini) dan VerificationEscalateAfter (eskalasi ke reviewer
+// departemen, atau antrian admin jika departemen tidak punya reviewer).
+type VerificationSLAService struct {
+	slaRepo   repository.VerificationSLARepository
+	policy    *policy.Policy
+	approvals *workflow.ApprovalChains
+}
+
+func NewVerificationSLAService(
+	slaRepo repository.VerificationSLARepository,
+	studentRepo repository.StudentRepository,
+	lecturerRepo repository.LecturerRepository,
+) *VerificationSLAService {
+	return &VerificationSLAService{
+		slaRepo:   slaRepo,
+		policy:    policy.New(studentRepo, lecturerRepo),
+		approvals: workflow.DefaultApprovalChains(),
+	}
+}
+
+// SetApprovalChains - Rantai persetujuan yang sama dengan AchievementService
+// (default: hanya dosen wali)
+func (s *VerificationSLAService) SetApprovalChains(chains *workflow.ApprovalChains) {
+	s.approvals = chains
+}
+
+//
+// ==================== SCHEDULER ======================
+// Pengecekan berjalan di goroutine setiap interval (sekali saat start).
+// Aman dijalankan di beberapa instance: penanda pengingat / eskalasi di-update
+// secara kondisional, sehingga notifikasi hanya dikirim sekali per pengajuan.
+//
+
+// Start - Jalankan pengecekan SLA berkala sampai ctx selesai
+func (s *VerificationSLAService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			result, err := s.RunChecks(time.Now())
+			if err != nil {
+				log.Println("Verification SLA check failed:", err)
+			} else if result.Reminded > 0 || result.Escalated > 0 {
+				log.Printf("Verification SLA: %d overdue, %d reminded, %d escalated", result.Checked, result.Reminded, result.Escalated)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunChecks - Satu kali pengecekan: kirim pengingat dan eskalasi yang jatuh tempo
+func (s *VerificationSLAService) RunChecks(now time.Time) (model.SLARunResult, error) {
+	cfg := config.AppConfig
+	result := model.SLARunResult{}
+
+	items, err := s.slaRepo.GetOverdue(now.Add(-cfg.VerificationReminderAfter), model.AccessScope{Kind: model.ScopeAll})
+	if err != nil {
+		return result, err
+	}
+	result.Checked = len(items)
+
+	for _, item := range items {
+		item = withSLAStage(item, now)
+		stage := s.approvalStage(&item)
+
+		switch {
+		case item.Stage == model.SLAStageEscalation && item.EscalatedAt == nil:
+			escalated, err := s.escalate(item, stage, now)
+			if err != nil {
+				log.Printf("Verification SLA: failed to escalate achievement %s: %v", item.ID, err)
+				continue
+			}
+			if escalated {
+				result.Escalated++
+			}
+
+		case item.Stage == model.SLAStageReminder && item.RemindedAt == nil:
+			reminded, err := s.remind(item, stage, now)
+			if err != nil {
+				log.Printf("Verification SLA: failed to remind approvers for achievement %s: %v", item.ID, err)
+				continue
+			}
+			if reminded {
+				result.Reminded++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// approvalStage - Tahap persetujuan item saat ini (mengisi item.ApprovalStageName)
+func (s *VerificationSLAService) approvalStage(item *model.OverdueAchievement) *workflow.ApprovalStage {
+	_, stage := s.approvals.Chain(item.ApprovalChain).StageAt(item.ApprovalStage)
+	item.ApprovalStageName = stage.Name
+	return stage
+}
+
+// remind - Pengingat ke approver tahap saat ini: dosen wali, reviewer departemen
+// (program studi mahasiswa) atau pemegang permission tahap. Tanpa penerima tidak ada
+// yang diingatkan; item menunggu tahap eskalasi.
+func (s *VerificationSLAService) remind(item model.OverdueAchievement, stage *workflow.ApprovalStage, now time.Time) (bool, error) {
+	var recipients []string
+	message := fmt.Sprintf("Achievement of %s (%s) has been waiting for your verification for %d days", item.StudentName, item.StudentNIM, item.WaitingDays)
+
+	switch stage.Approver {
+	case workflow.ApproverAdvisor:
+		if item.AdvisorID != nil {
+			recipients = []string{*item.AdvisorID}
+		}
+	case workflow.ApproverDepartment:
+		if item.ProgramStudy != "" {
+			reviewers, err := s.slaRepo.FindReviewers(model.PermissionAchievementReadDepartment, item.ProgramStudy)
+			if err != nil {
+				return false, err
+			}
+			recipients = withoutUser(reviewers, item.AdvisorID)
+		}
+	case workflow.ApproverPermission:
+		approvers, err := s.slaRepo.FindReviewers(stage.Permission, "")
+		if err != nil {
+			return false, err
+		}
+		recipients = approvers
+	}
+	if stage.Approver != workflow.ApproverAdvisor {
+		message = fmt.Sprintf("Achievement of %s (%s) has been waiting for your approval at stage '%s' for %d days", item.StudentName, item.StudentNIM, stage.Name, item.WaitingDays)
+	}
+
+	if len(recipients) == 0 {
+		return false, nil
+	}
+
+	notifications := make([]model.Notification, 0, len(recipients))
+	for _, userID := range recipients {
+		notifications = append(notifications, slaNotification(userID, model.NotificationVerificationReminder, item, message))
+	}
+	return s.slaRepo.MarkReminded(item.ID, now, notifications)
+}
+
+// escalate - Tahap dosen wali: notifikasi ke reviewer departemen (achievement:read_department,
+// departemen = program studi mahasiswa). Tahap lain (reviewer departemen sudah menjadi
+// approver), atau jika departemen tidak punya reviewer: ke antrian admin (achievement:read_all).
+func (s *VerificationSLAService) escalate(item model.OverdueAchievement, stage *workflow.ApprovalStage, now time.Time) (bool, error) {
+	var recipients []string
+	if stage.Approver == workflow.ApproverAdvisor && item.ProgramStudy != "" {
+		reviewers, err := s.slaRepo.FindReviewers(model.PermissionAchievementReadDepartment, item.ProgramStudy)
+		if err != nil {
+			return false, err
+		}
+		recipients = withoutUser(reviewers, item.AdvisorID)
+	}
+
+	if len(recipients) == 0 {
+		admins, err := s.slaRepo.FindReviewers(model.PermissionAchievementReadAll, "")
+		if err != nil {
+			return false, err
+		}
+		recipients = admins
+	}
+
+	message := fmt.Sprintf("Achievement of %s (%s) has not been verified by the advisor after %d days", item.StudentName, item.StudentNIM, item.WaitingDays)
+	if stage.Approver != workflow.ApproverAdvisor {
+		message = fmt.Sprintf("Achievement of %s (%s) has not been approved at stage '%s' after %d days", item.StudentName, item.StudentNIM, stage.Name, item.WaitingDays)
+	}
+	notifications := make([]model.Notification, 0, len(recipients))
+	for _, userID := range recipients {
+		notifications = append(notifications, slaNotification(userID, model.NotificationVerificationEscalated, item, message))
+	}
+
+	// Tetap ditandai walau tanpa penerima: item muncul di report overdue sebagai escalated
+	return s.slaRepo.MarkEscalated(item.ID, now, notifications)
+}
+
+//
+// ==================== OVERDUE REPORT (GET /reports/overdue) ======================
+// Prestasi yang menunggu verifikasi melewati batas pengingat, paling lama dulu.
+// Scope mengikuti policy (dosen wali: bimbingan, reviewer departemen: program studi, admin: semua).
+// Query: stage=reminder|escalation
+//
+
+func (s *VerificationSLAService) GetOverdueReport(c *fiber.Ctx) error {
+	// Get user dari context
+	claims, ok := c.Locals("user").(*model.JWTClaims)
+	if !ok {
+		return c.Status(401).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "unauthorized",
+		})
+	}
+
+	stage := c.Query("stage")
+	if stage != "" && stage != model.SLAStageReminder && stage != model.SLAStageEscalation {
+		return c.Status(422).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "stage must be 'reminder' or 'escalation'",
+		})
+	}
+
+	// Determine scope berdasarkan permission & relasi actor
+	scope := s.policy.Actor(claims).ReadScope()
+	if scope.Kind == model.ScopeNone {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "forbidden",
+		})
+	}
+
+	cfg := config.AppConfig
+	now := time.Now()
+	items, err := s.slaRepo.GetOverdue(now.Add(-cfg.VerificationReminderAfter), scope)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "failed to get overdue achievements",
+		})
+	}
+
+	report := model.OverdueReport{
+		ReminderAfterDays: cfg.VerificationReminderAfter.Hours() / 24,
+		EscalateAfterDays: cfg.VerificationEscalateAfter.Hours() / 24,
+		Items:             []model.OverdueAchievement{},
+	}
+	for _, item := range items {
+		item = withSLAStage(item, now)
+		s.approvalStage(&item)
+		if stage != "" && item.Stage != stage {
+			continue
+		}
+		if item.EscalatedAt != nil {
+			report.Escalated++
+		}
+		report.Items = append(report.Items, item)
+	}
+	report.Total = len(report.Items)
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   report,
+	})
+}
+
+//
+// ==================== HELPER: SLA ======================
+//
+
+// withSLAStage - Isi lama menunggu di tahap persetujuan saat ini (hari penuh) dan tahap SLA item
+func withSLAStage(item model.OverdueAchievement, now time.Time) model.OverdueAchievement {
+	since := item.SubmittedAt
+	if item.StageStartedAt != nil {
+		since = *item.StageStartedAt
+	}
+	waiting := now.Sub(since)
+	item.WaitingDays = int(waiting.Hours() / 24)
+	item.Stage = model.SLAStageReminder
+	if waiting >= config.AppConfig.VerificationEscalateAfter {
+		item.Stage = model.SLAStageEscalation
+	}
+	return item
+}
+
+func slaNotification(userID string, notificationType string, item model.OverdueAchievement, message string) model.Notification {
+	refID := item.ID
+	return model.Notification{
+		UserID:           userID,
+		Type:             notificationType,
+		AchievementRefID: &refID,
+		Message:          message,
+	}
+}
+
+func withoutUser(userIDs []string, exclude *string) []string {
+	if exclude == nil {
+		return userIDs
+	}
+	filtered := userIDs[:0:0]
+	for _, id := range userIDs {
+		if id != *exclude {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered
+}
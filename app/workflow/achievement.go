@@ -0,0 +1,237 @@
+package workflow
+
+import "UASBE/app/model"
+
+// Aksi pada prestasi
+const (
+	ActionEdit   = "edit"
+	ActionAttach = "attach"
+	ActionSubmit = "submit"
+	ActionVerify = "verify"
+	ActionReject = "reject"
+	ActionDelete = "delete"
+
+	ActionWithdraw = "withdraw"
+	ActionRestore  = "restore"
+
+	ActionRequestRevision = "request_revision"
+
+	// Persetujuan tahap yang bukan tahap terakhir rantai (lihat VerifyAction)
+	ActionApprove = "approve"
+)
+
+// Guard yang dipakai transisi bawaan
+func isOwner(ctx *Context) bool {
+	return ctx.Actor.IsOwner(ctx.Reference)
+}
+
+// isAdvisor - Dosen wali mahasiswa pemilik, atau delegasi aktifnya (mengisi ctx.OnBehalfOf)
+func isAdvisor(ctx *Context) bool {
+	onBehalfOf, ok := ctx.Actor.VerifierFor(ctx.Reference)
+	ctx.OnBehalfOf = onBehalfOf
+	return ok
+}
+
+// isStageApprover - Actor memenuhi aturan approver tahap persetujuan reference saat ini
+// dan belum memutuskan tahap sebelumnya (mengisi ctx.Stage / ctx.StageIndex)
+func isStageApprover(ctx *Context) bool {
+	index, stage := chainOf(ctx).StageAt(ctx.Reference.ApprovalStage)
+	ctx.Stage, ctx.StageIndex = stage, index
+
+	var ok bool
+	switch stage.Approver {
+	case ApproverAdvisor:
+		if !isAdvisor(ctx) {
+			return false
+		}
+		ok = true
+	case ApproverDepartment:
+		ok = ctx.Actor.ReviewsDepartmentOf(ctx.Reference)
+	case ApproverPermission:
+		ok = ctx.Actor.Can(stage.Permission)
+	}
+	if !ok {
+		ctx.DenyMessage = "forbidden: you are not an approver for stage '" + stage.Name + "' of this achievement"
+		return false
+	}
+
+	// Setiap tahap harus diputuskan orang yang berbeda, termasuk delegasi atas nama dosen wali
+	if decidedEarlierStage(ctx) {
+		ctx.DenyMessage = "forbidden: you already decided an earlier approval stage of this submission"
+		return false
+	}
+	return true
+}
+
+// decidedEarlierStage - Actor, atau dosen wali yang diwakilinya, ada di ctx.EarlierDeciders
+func decidedEarlierStage(ctx *Context) bool {
+	if len(ctx.EarlierDeciders) == 0 {
+		return false
+	}
+
+	identities := []string{ctx.Actor.UserID}
+	if lecturer := ctx.Actor.Lecturer(); lecturer != nil {
+		identities = append(identities, lecturer.ID)
+	}
+	if ctx.OnBehalfOf != nil {
+		identities = append(identities, *ctx.OnBehalfOf)
+	}
+
+	for _, decider := range ctx.EarlierDeciders {
+		for _, id := range identities {
+			if decider == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isFinalApprover - isStageApprover di tahap terakhir rantai
+func isFinalApprover(ctx *Context) bool {
+	if !isStageApprover(ctx) {
+		return false
+	}
+	if !chainOf(ctx).IsFinalStage(ctx.StageIndex) {
+		ctx.DenyMessage = "forbidden: stage '" + ctx.Stage.Name + "' is not the final approval stage"
+		return false
+	}
+	return true
+}
+
+// Default - Alur prestasi sesuai SRS, ditambah revisi setelah ditolak:
+//
+//	draft ──submit──▶ submitted ──verify──▶ verified
+//	  │                 ▲   ├──────reject──────────▶ rejected
+//	  │                 │   ├──request_revision──▶ revision_requested
+//	  │                 │   └──withdraw──▶ draft        │
+//	  │                 └──────submit (revisi)──────────┘
+//	  └──delete──▶ deleted ──restore──▶ draft
+//
+// Mahasiswa pemilik mengedit selama draft/rejected/revision_requested dan menambah
+// lampiran selama draft/submitted/rejected/revision_requested. Setiap pengajuan
+// menaikkan submission_count.
+//
+// verify/reject/request_revision diputuskan approver tahap persetujuan saat ini
+// (rantai bawaan: satu tahap, dosen wali). Di rantai bertahap, approve memajukan
+// tahap dengan status tetap submitted; verify hanya di tahap terakhir. Setiap tahap
+// harus diputuskan orang yang berbeda. Pengajuan (ulang) memulai rantai dari tahap pertama.
+//
+// Mahasiswa pemilik bisa menarik kembali (withdraw) pengajuan ke draft; syarat
+// "belum mulai direview" dicek pemanggil karena butuh data di luar reference.
+//
+// delete hanya soft delete (deleted_at diisi); admin bisa me-restore prestasi
+// terhapus ke draft sebelum di-purge setelah masa retensi.
+func Default() *Machine {
+	// Status di tangan mahasiswa: boleh diedit dan diajukan (ulang)
+	revisable := []string{
+		model.AchievementStatusDraft,
+		model.AchievementStatusRejected,
+		model.AchievementStatusRevisionRequested,
+	}
+
+	return New(
+		Transition{
+			Action:     ActionEdit,
+			From:       revisable,
+			Permission: model.PermissionAchievementUpdate,
+			Guard:      isOwner,
+		},
+		Transition{
+			Action:     ActionAttach,
+			From:       append([]string{model.AchievementStatusSubmitted}, revisable...),
+			Permission: model.PermissionAchievementUpdate,
+			Guard:      isOwner,
+		},
+		Transition{
+			Action:     ActionSubmit,
+			From:       revisable,
+			To:         model.AchievementStatusSubmitted,
+			Permission: model.PermissionAchievementUpdate,
+			Guard:      isOwner,
+			Effect: func(ctx *Context) {
+				// rejection_note dibiarkan supaya dosen wali tetap melihat alasan penolakan sebelumnya
+				ctx.Reference.SubmittedAt = &ctx.Now
+				ctx.Reference.SubmissionCount++
+				ctx.Reference.ApprovalStage = 0
+			},
+		},
+		Transition{
+			Action:      ActionApprove,
+			From:        []string{model.AchievementStatusSubmitted},
+			To:          model.AchievementStatusSubmitted,
+			Permission:  model.PermissionAchievementVerify,
+			Guard:       isStageApprover,
+			DenyMessage: "forbidden: you are not the advisor of this student",
+			Effect: func(ctx *Context) {
+				ctx.Reference.ApprovalStage = ctx.StageIndex + 1
+			},
+		},
+		Transition{
+			Action:      ActionVerify,
+			From:        []string{model.AchievementStatusSubmitted},
+			To:          model.AchievementStatusVerified,
+			Permission:  model.PermissionAchievementVerify,
+			Guard:       isFinalApprover,
+			DenyMessage: "forbidden: you are not the advisor of this student",
+			Effect: func(ctx *Context) {
+				ctx.Reference.VerifiedAt = &ctx.Now
+				ctx.Reference.VerifiedBy = &ctx.Claims.UserID
+			},
+		},
+		Transition{
+			Action:      ActionReject,
+			From:        []string{model.AchievementStatusSubmitted},
+			To:          model.AchievementStatusRejected,
+			Permission:  model.PermissionAchievementVerify,
+			Guard:       isStageApprover,
+			DenyMessage: "forbidden: you are not the advisor of this student",
+			Effect: func(ctx *Context) {
+				ctx.Reference.RejectionNote = ctx.Note
+			},
+		},
+		Transition{
+			// Catatan perbaikan hanya di riwayat; rejection_note tidak diubah
+			Action:      ActionRequestRevision,
+			From:        []string{model.AchievementStatusSubmitted},
+			To:          model.AchievementStatusRevisionRequested,
+			Permission:  model.PermissionAchievementVerify,
+			Guard:       isStageApprover,
+			DenyMessage: "forbidden: you are not the advisor of this student",
+		},
+		Transition{
+			Action:     ActionWithdraw,
+			From:       []string{model.AchievementStatusSubmitted},
+			To:         model.AchievementStatusDraft,
+			Permission: model.PermissionAchievementUpdate,
+			Guard:      isOwner,
+			Effect: func(ctx *Context) {
+				// submission_count tetap: snapshot pengajuan yang ditarik tetap tersimpan
+				ctx.Reference.SubmittedAt = nil
+				// Hanya sebelum tahap pertama disetujui; persetujuan yang terjadi bersamaan
+				// membuat withdraw gagal (ErrStatusChanged)
+				firstStage := 0
+				ctx.RequiredStage = &firstStage
+			},
+		},
+		Transition{
+			Action:     ActionDelete,
+			From:       []string{model.AchievementStatusDraft},
+			To:         model.AchievementStatusDeleted,
+			Permission: model.PermissionAchievementDelete,
+			Guard:      isOwner,
+			Effect: func(ctx *Context) {
+				ctx.Reference.DeletedAt = &ctx.Now
+			},
+		},
+		Transition{
+			Action:     ActionRestore,
+			From:       []string{model.AchievementStatusDeleted},
+			To:         model.AchievementStatusDraft,
+			Permission: model.PermissionUserManage,
+			Effect: func(ctx *Context) {
+				ctx.Reference.DeletedAt = nil
+			},
+		},
+	)
+}
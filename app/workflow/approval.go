@@ -0,0 +1,210 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"UASBE/app/model"
+)
+
+// Aturan approver sebuah tahap persetujuan
+const (
+	ApproverAdvisor    = "advisor"    // dosen wali mahasiswa (atau delegasi aktifnya)
+	ApproverDepartment = "department" // dosen dengan achievement:read_department di program studi mahasiswa (mis. Kaprodi)
+	ApproverPermission = "permission" // siapa saja yang memiliki Permission tahap
+)
+
+// DefaultChainName - Rantai bawaan untuk prestasi yang tidak cocok dengan rantai mana pun
+const DefaultChainName = "default"
+
+// ApprovalStage - Satu tahap persetujuan. Semua approver tetap butuh achievement:verify.
+type ApprovalStage struct {
+	Name       string `json:"name"`
+	Approver   string `json:"approver"`
+	Permission string `json:"permission,omitempty"` // hanya untuk approver "permission"
+}
+
+// ApprovalChain - Urutan tahap persetujuan untuk prestasi yang cocok dengan kriterianya.
+// Kriteria kosong berarti tidak membatasi.
+type ApprovalChain struct {
+	Name              string          `json:"name"`
+	AchievementType   string          `json:"achievement_type,omitempty"`
+	MinPoints         int             `json:"min_points,omitempty"`
+	CompetitionLevels []string        `json:"competition_levels,omitempty"` // details.competitionLevel
+	Stages            []ApprovalStage `json:"stages"`
+}
+
+// ApprovalChains - Daftar rantai persetujuan; rantai pertama yang cocok dipakai
+type ApprovalChains struct {
+	Chains []ApprovalChain `json:"chains"`
+}
+
+// DefaultChain - Alur SRS: cukup diverifikasi dosen wali
+func DefaultChain() *ApprovalChain {
+	return &ApprovalChain{
+		Name:   DefaultChainName,
+		Stages: []ApprovalStage{{Name: ApproverAdvisor, Approver: ApproverAdvisor}},
+	}
+}
+
+// DefaultApprovalChains - Tanpa konfigurasi: semua prestasi memakai DefaultChain
+func DefaultApprovalChains() *ApprovalChains {
+	return &ApprovalChains{}
+}
+
+// LoadApprovalChains - Baca rantai persetujuan dari file JSON:
+//
+//	{"chains": [{"name": "international_competition", "achievement_type": "competition",
+//	  "competition_levels": ["international"],
+//	  "stages": [{"name": "advisor", "approver": "advisor"}, {"name": "kaprodi", "approver": "department"}]}]}
+func LoadApprovalChains(path string) (*ApprovalChains, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseApprovalChains(data)
+}
+
+// ParseApprovalChains - Seperti LoadApprovalChains dari isi file
+func ParseApprovalChains(data []byte) (*ApprovalChains, error) {
+	chains := &ApprovalChains{}
+	if err := json.Unmarshal(data, chains); err != nil {
+		return nil, fmt.Errorf("approval chains: %w", err)
+	}
+	if err := chains.validate(); err != nil {
+		return nil, fmt.Errorf("approval chains: %w", err)
+	}
+	return chains, nil
+}
+
+func (c *ApprovalChains) validate() error {
+	names := map[string]bool{}
+	for _, chain := range c.Chains {
+		switch {
+		case chain.Name == "":
+			return fmt.Errorf("chain name is required")
+		case chain.Name == DefaultChainName:
+			return fmt.Errorf("chain name %q is reserved", DefaultChainName)
+		case names[chain.Name]:
+			return fmt.Errorf("duplicate chain %q", chain.Name)
+		case chain.MinPoints < 0:
+			return fmt.Errorf("chain %q: min_points must not be negative", chain.Name)
+		case len(chain.Stages) == 0:
+			return fmt.Errorf("chain %q: at least one stage is required", chain.Name)
+		}
+		names[chain.Name] = true
+
+		stages := map[string]bool{}
+		for _, stage := range chain.Stages {
+			if stage.Name == "" || stages[stage.Name] {
+				return fmt.Errorf("chain %q: stage names must be unique and not empty", chain.Name)
+			}
+			stages[stage.Name] = true
+
+			switch stage.Approver {
+			case ApproverAdvisor, ApproverDepartment:
+			case ApproverPermission:
+				if stage.Permission == "" {
+					return fmt.Errorf("chain %q: stage %q requires a permission", chain.Name, stage.Name)
+				}
+			default:
+				return fmt.Errorf("chain %q: stage %q approver must be advisor, department or permission", chain.Name, stage.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// ChainFor - Rantai pertama yang cocok dengan prestasi, atau DefaultChain
+func (c *ApprovalChains) ChainFor(achievement *model.Achievement) *ApprovalChain {
+	for i := range c.Chains {
+		if c.Chains[i].Matches(achievement) {
+			return &c.Chains[i]
+		}
+	}
+	return DefaultChain()
+}
+
+// Chain - Rantai berdasarkan nama yang tersimpan di reference. Prestasi lama (nil) atau
+// rantai yang sudah dihapus dari konfigurasi memakai DefaultChain.
+func (c *ApprovalChains) Chain(name *string) *ApprovalChain {
+	if name != nil {
+		for i := range c.Chains {
+			if c.Chains[i].Name == *name {
+				return &c.Chains[i]
+			}
+		}
+	}
+	return DefaultChain()
+}
+
+// Matches - Prestasi memenuhi semua kriteria rantai
+func (c *ApprovalChain) Matches(achievement *model.Achievement) bool {
+	if c.AchievementType != "" && c.AchievementType != achievement.AchievementType {
+		return false
+	}
+	if achievement.Points < c.MinPoints {
+		return false
+	}
+	if len(c.CompetitionLevels) > 0 {
+		level, _ := achievement.Details["competitionLevel"].(string)
+		for _, allowed := range c.CompetitionLevels {
+			if strings.EqualFold(level, allowed) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// StageAt - Tahap ke-index; index di luar rentang (mis. rantai diubah setelah
+// prestasi diajukan) dianggap tahap terakhir
+func (c *ApprovalChain) StageAt(index int) (int, *ApprovalStage) {
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(c.Stages) {
+		index = len(c.Stages) - 1
+	}
+	return index, &c.Stages[index]
+}
+
+// IsFinalStage - Tahap ke-index adalah tahap terakhir rantai
+func (c *ApprovalChain) IsFinalStage(index int) bool {
+	return index >= len(c.Stages)-1
+}
+
+// Progress - Posisi reference di rantai ini untuk response API
+func (c *ApprovalChain) Progress(ref *model.AchievementReference) *model.ApprovalProgress {
+	index, stage := c.StageAt(ref.ApprovalStage)
+	names := make([]string, 0, len(c.Stages))
+	for _, s := range c.Stages {
+		names = append(names, s.Name)
+	}
+	return &model.ApprovalProgress{
+		Chain:       c.Name,
+		Stages:      names,
+		Stage:       index + 1,
+		StageName:   stage.Name,
+		TotalStages: len(c.Stages),
+	}
+}
+
+// VerifyAction - Aksi untuk "verify" pada tahap reference saat ini: approve jika
+// masih ada tahap berikutnya (status tetap submitted), verify di tahap terakhir
+func VerifyAction(ctx *Context) string {
+	if !chainOf(ctx).IsFinalStage(ctx.Reference.ApprovalStage) {
+		return ActionApprove
+	}
+	return ActionVerify
+}
+
+func chainOf(ctx *Context) *ApprovalChain {
+	if ctx.Chain == nil {
+		return DefaultChain()
+	}
+	return ctx.Chain
+}
@@ -0,0 +1,176 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"UASBE/app/model"
+	"UASBE/app/policy"
+)
+
+// Context - Data yang tersedia untuk guard dan side effect sebuah transisi
+type Context struct {
+	Reference *model.AchievementReference
+	Actor     *policy.Actor
+	Claims    *model.JWTClaims
+	Note      *string // catatan dari request (mis. alasan penolakan)
+	Now       time.Time
+
+	// OnBehalfOf - Dosen wali yang diwakili actor; diisi guard dosen wali
+	// jika actor bertindak sebagai delegasi (dicatat di riwayat status)
+	OnBehalfOf *string
+
+	// Chain - Rantai persetujuan reference (nil = DefaultChain), diisi pemanggil
+	Chain *ApprovalChain
+	// Stage / StageIndex - Tahap persetujuan yang diputuskan actor; diisi guard tahap
+	// (nil untuk aksi di luar persetujuan)
+	Stage      *ApprovalStage
+	StageIndex int
+	// RequiredStage - Syarat approval_stage di database untuk aksi di luar persetujuan
+	// (nil = tidak dibatasi); diisi side effect transisi
+	RequiredStage *int
+	// EarlierDeciders - ID user (actor dan dosen wali yang diwakili) yang sudah memutuskan
+	// tahap sebelumnya pada pengajuan saat ini; diisi pemanggil untuk aksi persetujuan
+	EarlierDeciders []string
+
+	// DenyMessage - Pesan 403 dari guard, menggantikan Transition.DenyMessage
+	DenyMessage string
+}
+
+// Transition - Satu aksi pada prestasi.
+// To kosong berarti aksi tidak mengubah status (edit, upload lampiran).
+type Transition struct {
+	Action     string
+	From       []string
+	To         string
+	Permission string // permission yang wajib dimiliki actor ("" = tidak ada)
+
+	// Guard - Syarat relasi data (pemilik, dosen wali). Nil = selalu boleh.
+	Guard       func(ctx *Context) bool
+	DenyMessage string // pesan 403 jika permission/guard tidak terpenuhi
+
+	// Effect - Ubah field reference selain status (submitted_at, verified_by, ...)
+	// sebelum disimpan. Status sendiri disimpan pemanggil bersama riwayatnya.
+	Effect func(ctx *Context)
+}
+
+// ChangesStatus - true jika transisi memindahkan prestasi ke status lain
+func (t *Transition) ChangesStatus() bool {
+	return t.To != ""
+}
+
+// ForbiddenError - Actor tidak punya permission atau tidak lolos guard
+type ForbiddenError struct {
+	Message string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+// TransitionError - Aksi tidak valid untuk status prestasi saat ini
+type TransitionError struct {
+	Action  string
+	Status  string
+	Allowed []string // status berikutnya yang valid dari Status
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot %s achievement with status '%s'", e.Action, e.Status)
+}
+
+// Machine - State machine deklaratif: daftar transisi per aksi
+type Machine struct {
+	transitions map[string]Transition
+	order       []string // urutan deklarasi, untuk daftar status berikutnya yang stabil
+}
+
+// New - Machine dari daftar transisi. Aksi yang sama dideklarasikan ulang menimpa yang lama.
+func New(transitions ...Transition) *Machine {
+	m := &Machine{transitions: make(map[string]Transition, len(transitions))}
+	for _, t := range transitions {
+		m.Register(t)
+	}
+	return m
+}
+
+// Register - Tambah atau ganti transisi
+func (m *Machine) Register(t Transition) {
+	if _, exists := m.transitions[t.Action]; !exists {
+		m.order = append(m.order, t.Action)
+	}
+	m.transitions[t.Action] = t
+}
+
+// Transition - Definisi transisi untuk aksi (nil jika tidak ada)
+func (m *Machine) Transition(action string) *Transition {
+	t, ok := m.transitions[action]
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+// Apply - Cek permission, guard dan status asal, lalu jalankan side effect.
+// Otorisasi dicek lebih dulu supaya status prestasi milik orang lain tidak bocor.
+func (m *Machine) Apply(action string, ctx *Context) (*Transition, error) {
+	t, ok := m.transitions[action]
+	if !ok {
+		return nil, fmt.Errorf("workflow: unknown action %q", action)
+	}
+
+	if t.Permission != "" && !ctx.Actor.Can(t.Permission) {
+		return nil, &ForbiddenError{Message: t.denyMessage()}
+	}
+	if t.Guard != nil && !t.Guard(ctx) {
+		message := ctx.DenyMessage
+		if message == "" {
+			message = t.denyMessage()
+		}
+		return nil, &ForbiddenError{Message: message}
+	}
+
+	if !contains(t.From, ctx.Reference.Status) {
+		return nil, &TransitionError{
+			Action:  t.Action,
+			Status:  ctx.Reference.Status,
+			Allowed: m.AllowedNext(ctx.Reference.Status),
+		}
+	}
+
+	if ctx.Now.IsZero() {
+		ctx.Now = time.Now()
+	}
+	if t.Effect != nil {
+		t.Effect(ctx)
+	}
+	return &t, nil
+}
+
+// AllowedNext - Status lain yang bisa dicapai dari status ini
+func (m *Machine) AllowedNext(status string) []string {
+	next := []string{}
+	for _, action := range m.order {
+		t := m.transitions[action]
+		if t.ChangesStatus() && t.To != status && contains(t.From, status) && !contains(next, t.To) {
+			next = append(next, t.To)
+		}
+	}
+	return next
+}
+
+func (t *Transition) denyMessage() string {
+	if t.DenyMessage != "" {
+		return t.DenyMessage
+	}
+	return "forbidden"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
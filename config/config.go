@@ -1,9 +1,81 @@
 package config
 
+import "time"
+
 type Config struct {
+	AppEnv    string // development, staging atau production (default)
 	DBUrl     string
 	MongoURL  string
 	MongoDB   string
 	Port      string
 	JWTSecret string
-}
\ No newline at end of file
+
+	// JWT signing: HS256 (JWT_SECRET) atau RS256/EdDSA dengan key PEM
+	JWTAlgorithm  string
+	JWTSigningKey string // path private key PEM untuk menandatangani token
+	JWTVerifyKeys string // path public key PEM lama (dipisah koma), tetap diterima selama rotasi
+
+	// Backend denylist token: "postgres" (default) atau "memory"
+	TokenStore string
+
+	// Lama cache permission per role di AuthRequired (0 = selalu baca database)
+	PermissionCacheTTL time.Duration
+
+	// Brute-force protection
+	LoginMaxAttempts          int           // login gagal berturut-turut sebelum akun dikunci (0 = nonaktif)
+	LoginLockoutBase          time.Duration // durasi kunci pertama, berlipat dua tiap gagal berikutnya
+	LoginLockoutMax           time.Duration // batas atas durasi kunci
+	AuthRateLimit             int           // request per menit per IP ke /api/v1/auth
+	LoginIPFailureLimit       int           // login gagal per IP dalam LoginFailureWindow
+	LoginUsernameFailureLimit int           // login gagal per username dalam LoginFailureWindow
+	LoginFailureWindow        time.Duration
+
+	// Password policy (berlaku untuk create user, ganti password dan reset password)
+	PasswordMinLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	PasswordResetTokenTTL time.Duration // masa berlaku token reset password dari admin
+
+	// Multi-factor authentication (TOTP)
+	MFAIssuer       string        // nama issuer di aplikasi authenticator
+	MFAChallengeTTL time.Duration // masa berlaku challenge token antara password dan kode TOTP
+
+	// Impersonation ("login as") oleh admin
+	ImpersonationTTL        time.Duration // masa berlaku token impersonation (tanpa refresh token)
+	ImpersonationAllowWrite bool          // false = token impersonation hanya boleh GET
+
+	// Identity provider eksternal (direktori kampus). Kosong = nonaktif.
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string // URL callback publik, mis. https://api.kampus.ac.id/api/v1/auth/oidc/callback
+	OIDCScopes       string // dipisah spasi
+	OIDCGroupsClaim  string
+
+	LDAPURL          string // ldap://host:389 atau ldaps://host:636
+	LDAPBindDN       string
+	LDAPBindPassword string
+	LDAPBaseDN       string
+	LDAPUserAttr     string
+	LDAPGroupAttr    string
+
+	IdentityStudentIDAttr  string // claim OIDC / atribut LDAP berisi NIM
+	IdentityLecturerIDAttr string // claim OIDC / atribut LDAP berisi NIP
+	IdentityGroupRoles     string // "grup=Role;grup lain=Role Lain"
+	IdentityDefaultRole    string // role untuk user baru tanpa grup terpetakan (kosong = tolak)
+
+	// SLA verifikasi: lama prestasi menunggu di status submitted (dihitung dari submitted_at)
+	VerificationReminderAfter time.Duration // kirim pengingat ke dosen wali
+	VerificationEscalateAfter time.Duration // eskalasi ke reviewer departemen / antrian admin
+	VerificationSLAInterval   time.Duration // interval pengecekan scheduler (0 = scheduler nonaktif)
+
+	// Rantai persetujuan bertahap per tipe prestasi (file JSON, lihat workflow.LoadApprovalChains).
+	// Kosong = semua prestasi cukup diverifikasi dosen wali.
+	ApprovalChainsFile string
+
+	// Prestasi yang di-soft delete dihapus permanen (MongoDB + file lampiran) setelah retensi
+	DeletedAchievementRetention time.Duration // lama prestasi terhapus bisa di-restore admin
+	AchievementPurgeInterval    time.Duration // interval purge (0 = purge nonaktif)
+}
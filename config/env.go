@@ -3,24 +3,97 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 var AppConfig Config
 
+// DevelopmentJWTSecret - Secret bawaan, hanya dipakai jika APP_ENV=development
+// dan JWT_SECRET kosong. Validate menolaknya di environment lain.
+const DevelopmentJWTSecret = "default-secret-key"
+
+// envProblems - Nilai env yang tidak bisa di-parse saat LoadEnv (dilaporkan oleh Validate)
+var envProblems []Problem
+
 func LoadEnv() {
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("No .env file found")
 	}
 
+	envProblems = nil
+
 	AppConfig = Config{
+		AppEnv:     getEnv("APP_ENV", EnvProduction),
 		DBUrl:      os.Getenv("DATABASE_URL"),
 		MongoURL:   getEnv("MONGO_URL", "mongodb://localhost:27017"),
 		MongoDB:    getEnv("MONGO_DB", "UASBE"),
 		Port:       getEnv("PORT", "3000"),
-		JWTSecret:  getEnv("JWT_SECRET", "default-secret-key"),
+		JWTSecret:  os.Getenv("JWT_SECRET"),
+		TokenStore: getEnv("TOKEN_STORE", "postgres"),
+
+		PermissionCacheTTL: getEnvDuration("PERMISSION_CACHE_TTL", 30*time.Second),
+
+		JWTAlgorithm:  getEnv("JWT_ALGORITHM", "HS256"),
+		JWTSigningKey: os.Getenv("JWT_SIGNING_KEY"),
+		JWTVerifyKeys: os.Getenv("JWT_VERIFY_KEYS"),
+
+		LoginMaxAttempts:          getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginLockoutBase:          getEnvDuration("LOGIN_LOCKOUT_BASE", time.Minute),
+		LoginLockoutMax:           getEnvDuration("LOGIN_LOCKOUT_MAX", time.Hour),
+		AuthRateLimit:             getEnvInt("AUTH_RATE_LIMIT", 30),
+		LoginIPFailureLimit:       getEnvInt("LOGIN_IP_FAILURE_LIMIT", 20),
+		LoginUsernameFailureLimit: getEnvInt("LOGIN_USERNAME_FAILURE_LIMIT", 10),
+		LoginFailureWindow:        getEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+
+		PasswordMinLength:     getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordRequireUpper:  getEnvBool("PASSWORD_REQUIRE_UPPER", true),
+		PasswordRequireLower:  getEnvBool("PASSWORD_REQUIRE_LOWER", true),
+		PasswordRequireDigit:  getEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+		PasswordRequireSymbol: getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordResetTokenTTL: getEnvDuration("PASSWORD_RESET_TOKEN_TTL", time.Hour),
+
+		MFAIssuer:       getEnv("MFA_ISSUER", "UASBE"),
+		MFAChallengeTTL: getEnvDuration("MFA_CHALLENGE_TTL", 5*time.Minute),
+
+		ImpersonationTTL:        getEnvDuration("IMPERSONATION_TTL", 30*time.Minute),
+		ImpersonationAllowWrite: getEnvBool("IMPERSONATION_ALLOW_WRITE", false),
+
+		OIDCIssuer:       os.Getenv("OIDC_ISSUER"),
+		OIDCClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		OIDCClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		OIDCRedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		OIDCScopes:       getEnv("OIDC_SCOPES", "openid profile email"),
+		OIDCGroupsClaim:  getEnv("OIDC_GROUPS_CLAIM", "groups"),
+
+		LDAPURL:          os.Getenv("LDAP_URL"),
+		LDAPBindDN:       os.Getenv("LDAP_BIND_DN"),
+		LDAPBindPassword: os.Getenv("LDAP_BIND_PASSWORD"),
+		LDAPBaseDN:       os.Getenv("LDAP_BASE_DN"),
+		LDAPUserAttr:     getEnv("LDAP_USER_ATTR", "uid"),
+		LDAPGroupAttr:    getEnv("LDAP_GROUP_ATTR", "memberOf"),
+
+		IdentityStudentIDAttr:  getEnv("IDENTITY_STUDENT_ID_ATTR", "nim"),
+		IdentityLecturerIDAttr: getEnv("IDENTITY_LECTURER_ID_ATTR", "nip"),
+		IdentityGroupRoles:     os.Getenv("IDENTITY_GROUP_ROLES"),
+		IdentityDefaultRole:    os.Getenv("IDENTITY_DEFAULT_ROLE"),
+
+		VerificationReminderAfter: getEnvDuration("VERIFICATION_REMINDER_AFTER", 3*24*time.Hour),
+		VerificationEscalateAfter: getEnvDuration("VERIFICATION_ESCALATE_AFTER", 7*24*time.Hour),
+		VerificationSLAInterval:   getEnvDuration("VERIFICATION_SLA_INTERVAL", time.Hour),
+
+		ApprovalChainsFile: os.Getenv("APPROVAL_CHAINS_FILE"),
+
+		DeletedAchievementRetention: getEnvDuration("DELETED_ACHIEVEMENT_RETENTION", 30*24*time.Hour),
+		AchievementPurgeInterval:    getEnvDuration("ACHIEVEMENT_PURGE_INTERVAL", 24*time.Hour),
+	}
+
+	if AppConfig.JWTSecret == "" && AppConfig.IsDevelopment() {
+		log.Println("WARNING: JWT_SECRET not set, using development default secret")
+		AppConfig.JWTSecret = DevelopmentJWTSecret
 	}
 
 	log.Println("Environment variables loaded successfully")
@@ -33,4 +106,49 @@ func getEnv(key, defaultValue string) string {
 		return defaultValue
 	}
 	return value
-}
\ No newline at end of file
+}
+
+// Helper function untuk get env integer dengan default value
+func getEnvInt(key string, defaultValue int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		envProblems = append(envProblems, Problem{Key: key, Message: "must be an integer"})
+		return defaultValue
+	}
+	return value
+}
+
+// Helper function untuk get env boolean ("true", "false", "1", "0") dengan default value
+func getEnvBool(key string, defaultValue bool) bool {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		envProblems = append(envProblems, Problem{Key: key, Message: "must be true or false"})
+		return defaultValue
+	}
+	return value
+}
+
+// Helper function untuk get env durasi (format Go: "30s", "15m", "1h") dengan default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		envProblems = append(envProblems, Problem{Key: key, Message: "must be a duration such as 30s, 15m or 1h"})
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Nilai APP_ENV yang dikenal
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// Panjang minimum JWT_SECRET untuk HS256 (256 bit)
+const minJWTSecretLength = 32
+
+// Secret yang sering dipakai di contoh / tutorial dan tidak boleh dipakai di server
+var weakJWTSecrets = map[string]bool{
+	DevelopmentJWTSecret: true,
+	"secret":             true,
+	"secret_key_for_jwt": true,
+	"changeme":           true,
+	"jwt_secret":         true,
+}
+
+// IsDevelopment - true jika APP_ENV=development (boleh memakai default yang tidak aman)
+func (c Config) IsDevelopment() bool {
+	return c.AppEnv == EnvDevelopment
+}
+
+// Problem - Satu setting yang tidak valid
+type Problem struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// ValidationError - Laporan semua setting yang tidak valid sekaligus
+type ValidationError struct {
+	AppEnv   string    `json:"app_env"`
+	Problems []Problem `json:"problems"`
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration (APP_ENV=%s, %d problem(s)):", e.AppEnv, len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s: %s", p.Key, p.Message)
+	}
+	return b.String()
+}
+
+// Validate - Cek AppConfig hasil LoadEnv. Semua setting yang salah dilaporkan
+// sekaligus (bukan berhenti di yang pertama). Return nil jika valid,
+// atau *ValidationError.
+func Validate() error {
+	cfg := AppConfig
+	problems := append([]Problem{}, envProblems...)
+	add := func(key, message string) {
+		problems = append(problems, Problem{Key: key, Message: message})
+	}
+
+	switch cfg.AppEnv {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		add("APP_ENV", "must be one of development, staging, production")
+	}
+
+	// Database
+	if cfg.DBUrl == "" {
+		add("DATABASE_URL", "is required")
+	} else if u, err := url.Parse(cfg.DBUrl); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		add("DATABASE_URL", "must be a postgres:// or postgresql:// URL")
+	}
+
+	if cfg.MongoURL != "" && !strings.HasPrefix(cfg.MongoURL, "mongodb://") && !strings.HasPrefix(cfg.MongoURL, "mongodb+srv://") {
+		add("MONGO_URL", "must be a mongodb:// or mongodb+srv:// URL")
+	}
+
+	if port, err := strconv.Atoi(cfg.Port); err != nil || port < 1 || port > 65535 {
+		add("PORT", "must be a port number between 1 and 65535")
+	}
+
+	// JWT
+	switch cfg.JWTAlgorithm {
+	case "HS256":
+		switch {
+		case cfg.JWTSecret == "":
+			add("JWT_SECRET", "is required when JWT_ALGORITHM=HS256")
+		case cfg.IsDevelopment():
+			// development boleh memakai secret lemah
+		case weakJWTSecrets[strings.ToLower(cfg.JWTSecret)]:
+			add("JWT_SECRET", "is a well-known default value")
+		case len(cfg.JWTSecret) < minJWTSecretLength:
+			add("JWT_SECRET", fmt.Sprintf("must be at least %d characters", minJWTSecretLength))
+		}
+	case "RS256", "EdDSA":
+		if cfg.JWTSigningKey == "" {
+			add("JWT_SIGNING_KEY", "is required when JWT_ALGORITHM="+cfg.JWTAlgorithm)
+		} else if _, err := os.Stat(cfg.JWTSigningKey); err != nil {
+			add("JWT_SIGNING_KEY", "file not readable: "+cfg.JWTSigningKey)
+		}
+		for _, file := range strings.Split(cfg.JWTVerifyKeys, ",") {
+			file = strings.TrimSpace(file)
+			if file == "" {
+				continue
+			}
+			if _, err := os.Stat(file); err != nil {
+				add("JWT_VERIFY_KEYS", "file not readable: "+file)
+			}
+		}
+	default:
+		add("JWT_ALGORITHM", "must be one of HS256, RS256, EdDSA")
+	}
+
+	if cfg.TokenStore != "postgres" && cfg.TokenStore != "memory" {
+		add("TOKEN_STORE", "must be postgres or memory")
+	}
+
+	if cfg.PermissionCacheTTL < 0 {
+		add("PERMISSION_CACHE_TTL", "must not be negative")
+	}
+
+	// Brute-force protection
+	if cfg.LoginMaxAttempts > 0 && cfg.LoginLockoutBase <= 0 {
+		add("LOGIN_LOCKOUT_BASE", "must be positive when LOGIN_MAX_ATTEMPTS is enabled")
+	}
+	if cfg.LoginLockoutMax < cfg.LoginLockoutBase {
+		add("LOGIN_LOCKOUT_MAX", "must not be shorter than LOGIN_LOCKOUT_BASE")
+	}
+	if (cfg.LoginIPFailureLimit > 0 || cfg.LoginUsernameFailureLimit > 0) && cfg.LoginFailureWindow <= 0 {
+		add("LOGIN_FAILURE_WINDOW", "must be positive when login failure limits are enabled")
+	}
+
+	// Password policy
+	if cfg.PasswordMinLength < 0 {
+		add("PASSWORD_MIN_LENGTH", "must not be negative")
+	}
+	if cfg.PasswordResetTokenTTL <= 0 {
+		add("PASSWORD_RESET_TOKEN_TTL", "must be positive")
+	}
+
+	// MFA
+	if cfg.MFAChallengeTTL <= 0 {
+		add("MFA_CHALLENGE_TTL", "must be positive")
+	}
+
+	// Impersonation
+	if cfg.ImpersonationTTL <= 0 {
+		add("IMPERSONATION_TTL", "must be positive")
+	}
+
+	// Identity provider eksternal
+	if cfg.OIDCIssuer != "" {
+		if u, err := url.Parse(cfg.OIDCIssuer); err != nil || (u.Scheme != "https" && !(cfg.IsDevelopment() && u.Scheme == "http")) {
+			add("OIDC_ISSUER", "must be an https:// URL")
+		}
+		if cfg.OIDCClientID == "" {
+			add("OIDC_CLIENT_ID", "is required when OIDC_ISSUER is set")
+		}
+		if cfg.OIDCRedirectURL == "" {
+			add("OIDC_REDIRECT_URL", "is required when OIDC_ISSUER is set")
+		}
+	}
+	if cfg.LDAPURL != "" {
+		if u, err := url.Parse(cfg.LDAPURL); err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") {
+			add("LDAP_URL", "must be an ldap:// or ldaps:// URL")
+		} else if u.Scheme == "ldap" && !cfg.IsDevelopment() {
+			add("LDAP_URL", "must use ldaps:// outside development (passwords are sent in the bind request)")
+		}
+		if cfg.LDAPBaseDN == "" {
+			add("LDAP_BASE_DN", "is required when LDAP_URL is set")
+		}
+	}
+
+	// SLA verifikasi
+	if cfg.VerificationReminderAfter <= 0 {
+		add("VERIFICATION_REMINDER_AFTER", "must be positive")
+	}
+	if cfg.VerificationEscalateAfter <= cfg.VerificationReminderAfter {
+		add("VERIFICATION_ESCALATE_AFTER", "must be longer than VERIFICATION_REMINDER_AFTER")
+	}
+	if cfg.VerificationSLAInterval < 0 {
+		add("VERIFICATION_SLA_INTERVAL", "must not be negative")
+	}
+
+	// Rantai persetujuan (isi file divalidasi saat dimuat di main)
+	if cfg.ApprovalChainsFile != "" {
+		if _, err := os.Stat(cfg.ApprovalChainsFile); err != nil {
+			add("APPROVAL_CHAINS_FILE", "file not readable: "+cfg.ApprovalChainsFile)
+		}
+	}
+
+	// Purge prestasi terhapus
+	if cfg.DeletedAchievementRetention <= 0 {
+		add("DELETED_ACHIEVEMENT_RETENTION", "must be positive")
+	}
+	if cfg.AchievementPurgeInterval < 0 {
+		add("ACHIEVEMENT_PURGE_INTERVAL", "must not be negative")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{AppEnv: cfg.AppEnv, Problems: problems}
+}
@@ -15,9 +15,13 @@ func RunMigrations(db *sql.DB) error {
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			name VARCHAR(50) UNIQUE NOT NULL,
 			description TEXT,
+			mfa_required BOOLEAN NOT NULL DEFAULT false,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
+		// Kolom wajib MFA per role untuk database yang dibuat sebelumnya
+		`ALTER TABLE roles ADD COLUMN IF NOT EXISTS mfa_required BOOLEAN NOT NULL DEFAULT false`,
+
 		// Create permissions table
 		`CREATE TABLE IF NOT EXISTS permissions (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -43,10 +47,28 @@ func RunMigrations(db *sql.DB) error {
 			full_name VARCHAR(100) NOT NULL,
 			role_id UUID REFERENCES roles(id) ON DELETE SET NULL,
 			is_active BOOLEAN DEFAULT true,
+			failed_login_attempts INT NOT NULL DEFAULT 0,
+			last_failed_login_at TIMESTAMP,
+			locked_until TIMESTAMP,
+			deleted_at TIMESTAMP,
+			mfa_enabled BOOLEAN NOT NULL DEFAULT false,
+			mfa_secret VARCHAR(64),
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
+		// Kolom status akun (kunci sementara, soft delete) untuk database yang dibuat sebelumnya
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP`,
+
+		// Kolom lockout login untuk database yang dibuat sebelumnya
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_login_attempts INT NOT NULL DEFAULT 0`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS last_failed_login_at TIMESTAMP`,
+
+		// Kolom MFA (TOTP) untuk database yang dibuat sebelumnya
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_enabled BOOLEAN NOT NULL DEFAULT false`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_secret VARCHAR(64)`,
+
 		// Create lecturers table
 		`CREATE TABLE IF NOT EXISTS lecturers (
 			id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
@@ -70,15 +92,216 @@ func RunMigrations(db *sql.DB) error {
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
 			mongo_achievement_id VARCHAR(24) NOT NULL,
-			status VARCHAR(20) NOT NULL CHECK (status IN ('draft', 'submitted', 'verified', 'rejected', 'deleted')),
+			status VARCHAR(20) NOT NULL CHECK (status IN ('draft', 'submitted', 'verified', 'rejected', 'revision_requested', 'deleted')),
 			submitted_at TIMESTAMP,
 			verified_at TIMESTAMP,
 			verified_by UUID REFERENCES users(id) ON DELETE SET NULL,
 			rejection_note TEXT,
+			submission_count INT NOT NULL DEFAULT 0,
+			reminded_at TIMESTAMP,
+			escalated_at TIMESTAMP,
+			approval_chain VARCHAR(100),
+			approval_stage INT NOT NULL DEFAULT 0,
+			deleted_at TIMESTAMP,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
+		// Kolom submission_count untuk database yang dibuat sebelum alur revisi ada
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS submission_count INT NOT NULL DEFAULT 0`,
+		`UPDATE achievement_references SET submission_count = 1 WHERE submission_count = 0 AND submitted_at IS NOT NULL`,
+
+		// Kolom SLA verifikasi (pengingat / eskalasi terakhir) untuk database yang dibuat sebelumnya
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMP`,
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP`,
+
+		// Kolom rantai persetujuan (nama rantai + tahap yang sedang menunggu) untuk database yang dibuat sebelumnya
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS approval_chain VARCHAR(100)`,
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS approval_stage INT NOT NULL DEFAULT 0`,
+
+		// Kolom deleted_at (acuan retensi purge) untuk database yang dibuat sebelumnya
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP`,
+		`UPDATE achievement_references SET deleted_at = updated_at WHERE status = 'deleted' AND deleted_at IS NULL`,
+
+		// Status revision_requested untuk database yang dibuat sebelum status ini ada
+		`ALTER TABLE achievement_references DROP CONSTRAINT IF EXISTS achievement_references_status_check`,
+		`ALTER TABLE achievement_references ADD CONSTRAINT achievement_references_status_check
+			CHECK (status IN ('draft', 'submitted', 'verified', 'rejected', 'revision_requested', 'deleted'))`,
+
+		// Create achievement_status_history table (setiap transisi status prestasi)
+		`CREATE TABLE IF NOT EXISTS achievement_status_history (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+			from_status VARCHAR(20),
+			to_status VARCHAR(20) NOT NULL,
+			actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			actor_role VARCHAR(50) NOT NULL DEFAULT '',
+			on_behalf_of UUID REFERENCES users(id) ON DELETE SET NULL,
+			approval_stage INT,
+			approval_stage_name VARCHAR(50),
+			note TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Kolom on_behalf_of (dosen wali yang diwakili delegasi) untuk database yang dibuat sebelumnya
+		`ALTER TABLE achievement_status_history ADD COLUMN IF NOT EXISTS on_behalf_of UUID REFERENCES users(id) ON DELETE SET NULL`,
+
+		// Kolom tahap persetujuan yang diputuskan (verify/reject/request_revision) untuk database yang dibuat sebelumnya
+		`ALTER TABLE achievement_status_history ADD COLUMN IF NOT EXISTS approval_stage INT`,
+		`ALTER TABLE achievement_status_history ADD COLUMN IF NOT EXISTS approval_stage_name VARCHAR(50)`,
+
+		// Create achievement_submissions table (snapshot isi prestasi setiap kali diajukan)
+		`CREATE TABLE IF NOT EXISTS achievement_submissions (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+			submission_number INT NOT NULL,
+			snapshot JSONB NOT NULL,
+			submitted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (achievement_ref_id, submission_number)
+		)`,
+
+		// Create achievement_comments table (diskusi pada prestasi; parent_id = balasan)
+		`CREATE TABLE IF NOT EXISTS achievement_comments (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+			parent_id UUID REFERENCES achievement_comments(id) ON DELETE CASCADE,
+			author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			author_role VARCHAR(50) NOT NULL DEFAULT '',
+			body TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create achievement_comment_mentions table (user yang disebut @username di komentar)
+		`CREATE TABLE IF NOT EXISTS achievement_comment_mentions (
+			comment_id UUID NOT NULL REFERENCES achievement_comments(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			PRIMARY KEY (comment_id, user_id)
+		)`,
+
+		// Create verifier_delegations table (dosen wali memberi hak verifikasi atas mahasiswa
+		// bimbingannya ke dosen lain selama rentang tanggal, mis. saat cuti)
+		`CREATE TABLE IF NOT EXISTS verifier_delegations (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			advisor_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+			delegate_id UUID NOT NULL REFERENCES lecturers(id) ON DELETE CASCADE,
+			starts_on DATE NOT NULL,
+			ends_on DATE NOT NULL,
+			reason TEXT,
+			created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			revoked_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			CHECK (advisor_id <> delegate_id),
+			CHECK (ends_on >= starts_on)
+		)`,
+
+		// Create notifications table (notifikasi in-app, mis. pengingat & eskalasi SLA verifikasi)
+		`CREATE TABLE IF NOT EXISTS notifications (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			type VARCHAR(50) NOT NULL,
+			achievement_ref_id UUID REFERENCES achievement_references(id) ON DELETE CASCADE,
+			message TEXT NOT NULL,
+			read_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create refresh_tokens table (id = jti, family_id = rantai rotasi)
+		`CREATE TABLE IF NOT EXISTS refresh_tokens (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			family_id UUID NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			revoked_at TIMESTAMP,
+			replaced_by UUID,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create api_tokens table (token personal untuk script/integrasi, hanya hash yang disimpan)
+		`CREATE TABLE IF NOT EXISTS api_tokens (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			token_hash VARCHAR(64) UNIQUE NOT NULL,
+			token_prefix VARCHAR(20) NOT NULL,
+			scopes TEXT NOT NULL DEFAULT '',
+			expires_at TIMESTAMP,
+			last_used_at TIMESTAMP,
+			revoked_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create audit_logs table (append-only; user tidak di-FK supaya jejak tetap ada setelah user dihapus)
+		`CREATE TABLE IF NOT EXISTS audit_logs (
+			id UUID PRIMARY KEY,
+			action VARCHAR(50) NOT NULL,
+			actor_id UUID NOT NULL,
+			subject_id UUID,
+			method VARCHAR(10),
+			path TEXT,
+			status_code INTEGER,
+			ip_address VARCHAR(64),
+			detail TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create user_identities table (link akun direktori kampus OIDC/LDAP ke user lokal)
+		`CREATE TABLE IF NOT EXISTS user_identities (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			provider VARCHAR(20) NOT NULL,
+			subject VARCHAR(255) NOT NULL,
+			last_login_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (provider, subject)
+		)`,
+
+		// Create user_sessions table (id = family_id refresh token, satu baris per login/device)
+		`CREATE TABLE IF NOT EXISTS user_sessions (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			user_agent TEXT NOT NULL DEFAULT '',
+			ip_address VARCHAR(64) NOT NULL DEFAULT '',
+			issued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			revoked_at TIMESTAMP
+		)`,
+
+		// Create revoked_access_tokens table (denylist access token berdasarkan jti)
+		`CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+			jti VARCHAR(64) PRIMARY KEY,
+			user_id UUID NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create user_token_cutoffs table
+		// Tanpa FK ke users supaya batas waktu tetap berlaku walau user dihapus
+		`CREATE TABLE IF NOT EXISTS user_token_cutoffs (
+			user_id UUID PRIMARY KEY,
+			valid_after TIMESTAMP NOT NULL
+		)`,
+
+		// Create password_reset_tokens table (token sekali pakai, hanya hash yang disimpan)
+		`CREATE TABLE IF NOT EXISTS password_reset_tokens (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			token_hash VARCHAR(64) UNIQUE NOT NULL,
+			created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			expires_at TIMESTAMP NOT NULL,
+			used_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create mfa_recovery_codes table (kode sekali pakai, hanya hash yang disimpan)
+		`CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			code_hash VARCHAR(64) NOT NULL,
+			used_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
 		`CREATE INDEX IF NOT EXISTS idx_users_username ON users(username)`,
 		`CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)`,
 		`CREATE INDEX IF NOT EXISTS idx_users_role_id ON users(role_id)`,
@@ -89,6 +312,61 @@ func RunMigrations(db *sql.DB) error {
 		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_student_id ON achievement_references(student_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_mongo_id ON achievement_references(mongo_achievement_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_status ON achievement_references(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_status_history_ref_id ON achievement_status_history(achievement_ref_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_comments_ref_id ON achievement_comments(achievement_ref_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_comment_mentions_user_id ON achievement_comment_mentions(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_submitted_at ON achievement_references(submitted_at) WHERE status = 'submitted'`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_deleted_at ON achievement_references(deleted_at) WHERE status = 'deleted'`,
+		`CREATE INDEX IF NOT EXISTS idx_verifier_delegations_advisor_id ON verifier_delegations(advisor_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_verifier_delegations_delegate_id ON verifier_delegations(delegate_id, ends_on)`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs(actor_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_logs_subject_id ON audit_logs(subject_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id)`,
+
+		// Backfill achievement_status_history untuk prestasi yang dibuat sebelum tabel ini ada,
+		// direkonstruksi dari kolom timestamp di achievement_references (hanya sekali per prestasi)
+		`INSERT INTO achievement_status_history (achievement_ref_id, from_status, to_status, actor_id, actor_role, note, created_at)
+		SELECT t.ref_id, t.from_status, t.to_status, t.actor_id, COALESCE(ro.name, ''), t.note, t.created_at
+		FROM (
+			SELECT r.id AS ref_id, NULL::VARCHAR AS from_status, 'draft'::VARCHAR AS to_status,
+				r.student_id AS actor_id, NULL::TEXT AS note, r.created_at
+			FROM achievement_references r
+			UNION ALL
+			SELECT r.id, 'draft', 'submitted', r.student_id, NULL, r.submitted_at
+			FROM achievement_references r WHERE r.submitted_at IS NOT NULL
+			UNION ALL
+			SELECT r.id, 'submitted', 'verified', r.verified_by, NULL, r.verified_at
+			FROM achievement_references r WHERE r.status = 'verified' AND r.verified_at IS NOT NULL
+			UNION ALL
+			SELECT r.id, 'submitted', 'rejected', r.verified_by, r.rejection_note, r.updated_at
+			FROM achievement_references r WHERE r.status = 'rejected'
+			UNION ALL
+			SELECT r.id, 'draft', 'deleted', r.student_id, NULL, r.updated_at
+			FROM achievement_references r WHERE r.status = 'deleted'
+		) t
+		LEFT JOIN users u ON u.id = t.actor_id
+		LEFT JOIN roles ro ON ro.id = u.role_id
+		WHERE NOT EXISTS (SELECT 1 FROM achievement_status_history h WHERE h.achievement_ref_id = t.ref_id)`,
+
+		// Permission jangkauan baca (app/policy) untuk database yang di-seed sebelumnya:
+		// Admin membaca semua prestasi lewat achievement:read_all, bukan lagi nama role.
+		// Tanpa role Admin (database baru) grant dilakukan oleh seeder.
+		`INSERT INTO permissions (name, resource, action, description) VALUES
+			('achievement:read_all', 'achievement', 'read_all', 'Membaca prestasi semua mahasiswa'),
+			('achievement:read_department', 'achievement', 'read_department', 'Membaca prestasi mahasiswa satu program studi')
+		ON CONFLICT (name) DO NOTHING`,
+		`INSERT INTO role_permissions (role_id, permission_id)
+		SELECT r.id, p.id FROM roles r, permissions p
+		WHERE r.name = 'Admin' AND p.name = 'achievement:read_all'
+		ON CONFLICT DO NOTHING`,
 	}
 
 	for i, migration := range migrations {
@@ -108,6 +386,21 @@ func DropTables(db *sql.DB) error {
 	log.Println("Dropping all tables...")
 
 	drops := []string{
+		`DROP TABLE IF EXISTS mfa_recovery_codes CASCADE`,
+		`DROP TABLE IF EXISTS password_reset_tokens CASCADE`,
+		`DROP TABLE IF EXISTS user_token_cutoffs CASCADE`,
+		`DROP TABLE IF EXISTS revoked_access_tokens CASCADE`,
+		`DROP TABLE IF EXISTS audit_logs CASCADE`,
+		`DROP TABLE IF EXISTS api_tokens CASCADE`,
+		`DROP TABLE IF EXISTS user_identities CASCADE`,
+		`DROP TABLE IF EXISTS user_sessions CASCADE`,
+		`DROP TABLE IF EXISTS refresh_tokens CASCADE`,
+		`DROP TABLE IF EXISTS verifier_delegations CASCADE`,
+		`DROP TABLE IF EXISTS notifications CASCADE`,
+		`DROP TABLE IF EXISTS achievement_comment_mentions CASCADE`,
+		`DROP TABLE IF EXISTS achievement_comments CASCADE`,
+		`DROP TABLE IF EXISTS achievement_submissions CASCADE`,
+		`DROP TABLE IF EXISTS achievement_status_history CASCADE`,
 		`DROP TABLE IF EXISTS achievement_references CASCADE`,
 		`DROP TABLE IF EXISTS students CASCADE`,
 		`DROP TABLE IF EXISTS lecturers CASCADE`,
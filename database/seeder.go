@@ -70,6 +70,8 @@ func seedPermissions(db *sql.DB) error {
 		{"user:manage", "user", "manage", "Mengelola data user"},
 		{"achievement:create", "achievement", "create", "Membuat prestasi baru"},
 		{"achievement:read", "achievement", "read", "Membaca prestasi"},
+		{"achievement:read_all", "achievement", "read_all", "Membaca prestasi semua mahasiswa"},
+		{"achievement:read_department", "achievement", "read_department", "Membaca prestasi mahasiswa satu program studi"},
 		{"achievement:update", "achievement", "update", "Mengupdate prestasi"},
 		{"achievement:delete", "achievement", "delete", "Menghapus prestasi"},
 		{"achievement:verify", "achievement", "verify", "Memverifikasi prestasi mahasiswa"},
@@ -99,6 +101,7 @@ func seedRolePermissions(db *sql.DB) error {
 	adminPerms := []string{
 		"user:manage",
 		"achievement:read",
+		"achievement:read_all",
 		"achievement:create",
 		"achievement:update",
 		"achievement:delete",
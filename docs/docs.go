@@ -25,11 +25,6 @@ const docTemplate = `{
     "paths": {
         "/achievements": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
                 "description": "Get achievements list with role-based filtering: Mahasiswa sees own achievements, Dosen Wali sees advisees' achievements, Admin sees all.",
                 "consumes": [
                     "application/json"
@@ -106,14 +101,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            },
+            "post": {
                 "description": "Create new achievement draft. Status will be set to 'draft' initially.",
                 "consumes": [
                     "application/json"
@@ -185,17 +180,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/achievements/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get detailed achievement information with authorization check based on role",
+                ]
+            }
+        },
+        "/achievements/batch/reject": {
+            "post": {
+                "description": "Reject up to 100 achievements. Each item uses its own rejection_note, or the shared rejection_note if empty; every item must end up with a note. Checks, modes and response are the same as batch verify.",
                 "consumes": [
                     "application/json"
                 ],
@@ -205,19 +200,21 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Get achievement by ID",
+                "summary": "Reject many achievements at once (Dosen Wali only)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Achievement Reference ID (UUID from PostgreSQL)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Items with rejection notes and mode",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BatchRejectRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement details",
+                        "description": "Batch processed, per-item results",
                         "schema": {
                             "allOf": [
                                 {
@@ -227,40 +224,60 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.AchievementResponse"
+                                            "$ref": "#/definitions/model.BatchResult"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - Not authorized to view this achievement",
+                    "409": {
+                        "description": "Atomic batch aborted, nothing was changed",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BatchResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Achievement not found",
+                    "422": {
+                        "description": "Validation error - rejection note required",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update achievement data. Can only update if status is 'draft' and you are the owner.",
+                ]
+            }
+        },
+        "/achievements/batch/verify": {
+            "post": {
+                "description": "Verify up to 100 achievements. Each item gets the same checks as POST /achievements/{id}/verify (approver of the current approval stage, status 'submitted'). Items at a non-final stage advance to the next stage and stay 'submitted'. mode 'atomic' (default): if any item fails nothing is changed and the response is 409; otherwise all items are saved in one transaction. mode 'partial': valid items are processed even if others fail. The response always lists a result per item, in request order.",
                 "consumes": [
                     "application/json"
                 ],
@@ -270,28 +287,21 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Update achievement (Mahasiswa only, draft status)",
+                "summary": "Verify many achievements at once (Dosen Wali only)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Achievement Reference ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update data (all fields optional)",
+                        "description": "Achievement IDs and mode",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.AchievementUpdateRequest"
+                            "$ref": "#/definitions/model.BatchVerifyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement updated successfully",
+                        "description": "Batch processed, per-item results",
                         "schema": {
                             "allOf": [
                                 {
@@ -301,7 +311,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.AchievementResponse"
+                                            "$ref": "#/definitions/model.BatchResult"
                                         }
                                     }
                                 }
@@ -309,7 +319,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Can only update achievements with status 'draft'",
+                        "description": "Invalid request body",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -320,90 +330,43 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - Not your achievement",
+                    "409": {
+                        "description": "Atomic batch aborted, nothing was changed",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BatchResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Achievement not found",
+                    "422": {
+                        "description": "Validation error",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Soft delete achievement. Can only delete if status is 'draft' and you are the owner.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Achievements"
-                ],
-                "summary": "Delete achievement (Mahasiswa only, draft status)",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Achievement Reference ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Achievement deleted successfully",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Can only delete achievements with status 'draft'",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - Not your achievement",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Achievement not found",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    }
-                }
+                ]
             }
         },
-        "/achievements/{id}/attachments": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Upload file attachment to achievement. Accepts PDF, JPG, PNG with max size 5MB. Can upload for 'draft' or 'submitted' status.",
+        "/achievements/deleted": {
+            "get": {
+                "description": "List soft-deleted achievements that have not been purged yet, most recently deleted first. Each item includes deleted_at.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
@@ -411,26 +374,26 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Upload attachment file (Mahasiswa only)",
+                "summary": "Get deleted achievements (Admin only)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Achievement Reference ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "type": "file",
-                        "description": "File to upload (PDF, JPG, PNG, max 5MB)",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size (max 100)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Attachment uploaded successfully",
+                    "200": {
+                        "description": "List of deleted achievements",
                         "schema": {
                             "allOf": [
                                 {
@@ -440,19 +403,13 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.Attachment"
+                                            "$ref": "#/definitions/model.AchievementListResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid file, file too large, or file type not allowed",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -460,28 +417,22 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Not your achievement",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Achievement not found",
+                        "description": "Forbidden - requires user:manage",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/achievements/{id}/history": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get timeline of achievement status changes (draft → submitted → verified/rejected). Includes actor info and notes.",
+                ]
+            }
+        },
+        "/achievements/{id}": {
+            "get": {
+                "description": "Get detailed achievement information with authorization check based on role",
                 "consumes": [
                     "application/json"
                 ],
@@ -491,11 +442,11 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Get achievement status history",
+                "summary": "Get achievement by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Achievement Reference ID (UUID)",
+                        "description": "Achievement Reference ID (UUID from PostgreSQL)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -503,7 +454,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement history timeline",
+                        "description": "Achievement details",
                         "schema": {
                             "allOf": [
                                 {
@@ -513,7 +464,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object"
+                                            "$ref": "#/definitions/model.AchievementResponse"
                                         }
                                     }
                                 }
@@ -538,17 +489,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/achievements/{id}/reject": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Reject submitted achievement with mandatory rejection note. Can only reject if you are the advisor and status is 'submitted'.",
+                ]
+            },
+            "put": {
+                "description": "Update achievement data. Can only update if status is 'draft', 'rejected' or 'revision_requested' and you are the owner.",
                 "consumes": [
                     "application/json"
                 ],
@@ -558,7 +507,7 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Reject achievement (Dosen Wali only)",
+                "summary": "Update achievement (Mahasiswa only, draft or rejected status)",
                 "parameters": [
                     {
                         "type": "string",
@@ -568,18 +517,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Rejection note (required)",
+                        "description": "Update data (all fields optional)",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.RejectAchievementRequest"
+                            "$ref": "#/definitions/model.AchievementUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement rejected with note",
+                        "description": "Achievement updated successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -589,19 +538,13 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object"
+                                            "$ref": "#/definitions/model.AchievementResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Achievement must be in 'submitted' status",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -609,7 +552,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Not advisor of this student",
+                        "description": "Forbidden - Not your achievement",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -620,23 +563,21 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "422": {
-                        "description": "Validation error - rejection note required",
+                    "409": {
+                        "description": "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states)",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/achievements/{id}/submit": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Submit draft achievement to advisor for verification. Changes status from 'draft' to 'submitted'.",
+                ]
+            },
+            "delete": {
+                "description": "Soft delete achievement. Can only delete if status is 'draft' and you are the owner. The achievement can be restored by an admin until it is purged permanently (together with its attachment files) after the retention period (DELETED_ACHIEVEMENT_RETENTION, default 30 days).",
                 "consumes": [
                     "application/json"
                 ],
@@ -646,7 +587,7 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Submit achievement for verification (Mahasiswa only)",
+                "summary": "Delete achievement (Mahasiswa only, draft status)",
                 "parameters": [
                     {
                         "type": "string",
@@ -658,25 +599,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement submitted with new status and timestamp",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/model.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Achievement already submitted or processed",
+                        "description": "Achievement deleted successfully",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -698,20 +621,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
                     }
-                }
-            }
-        },
-        "/achievements/{id}/verify": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Approve submitted achievement. Can only verify if you are the advisor of the student and status is 'submitted'.",
+                ]
+            }
+        },
+        "/achievements/{id}/attachments": {
+            "post": {
+                "description": "Upload file attachment to achievement. Accepts PDF, JPG, PNG with max size 5MB. Can upload for 'draft', 'submitted', 'rejected' or 'revision_requested' status.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -719,7 +648,7 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Verify achievement (Dosen Wali only)",
+                "summary": "Upload attachment file (Mahasiswa only)",
                 "parameters": [
                     {
                         "type": "string",
@@ -727,11 +656,18 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to upload (PDF, JPG, PNG, max 5MB)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Achievement verified with timestamp and verifier info",
+                    "201": {
+                        "description": "Attachment uploaded successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -741,7 +677,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object"
+                                            "$ref": "#/definitions/model.Attachment"
                                         }
                                     }
                                 }
@@ -749,7 +685,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Achievement must be in 'submitted' status",
+                        "description": "Invalid file, file too large, or file type not allowed",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -761,7 +697,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Not advisor of this student",
+                        "description": "Forbidden - Not your achievement",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -771,13 +707,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Authenticate user with username/email and password",
+        "/achievements/{id}/changes": {
+            "get": {
+                "description": "Field-level changes of the achievement compared to the previous submission, so advisors can review a revised achievement. While submitted/verified the current content is compared to the submission before the latest one; while draft/rejected it is compared to the latest submission. Includes the rejection note given for the compared submission.",
                 "consumes": [
                     "application/json"
                 ],
@@ -785,23 +732,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Achievements"
                 ],
-                "summary": "Login to the system",
+                "summary": "Get changes since the previous submission",
                 "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "Achievement Reference ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "Changes since the previous submission (empty if there is none)",
                         "schema": {
                             "allOf": [
                                 {
@@ -811,70 +756,42 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.LoginResponse"
+                                            "$ref": "#/definitions/model.AchievementChangesResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
                     "401": {
-                        "description": "Invalid username or password",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/auth/logout": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Logout current user (invalidate token on client side)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Authentication"
-                ],
-                "summary": "Logout from system",
-                "responses": {
-                    "200": {
-                        "description": "Logout successful",
+                    },
+                    "403": {
+                        "description": "Forbidden - Not authorized to view this achievement",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Achievement not found",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/auth/profile": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get profile of currently authenticated user",
+                ]
+            }
+        },
+        "/achievements/{id}/comments": {
+            "get": {
+                "description": "Comment threads on the achievement, oldest first. Replies are nested under their parent comment.",
                 "consumes": [
                     "application/json"
                 ],
@@ -882,12 +799,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Achievements"
+                ],
+                "summary": "Get achievement comments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement Reference ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get current user profile",
                 "responses": {
                     "200": {
-                        "description": "User profile",
+                        "description": "Comment threads (data.comments) and total",
                         "schema": {
                             "allOf": [
                                 {
@@ -897,7 +823,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.UserResponse"
+                                            "type": "object"
                                         }
                                     }
                                 }
@@ -910,18 +836,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - Not authorized to view this achievement",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
                     "404": {
-                        "description": "User not found",
+                        "description": "Achievement not found",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/auth/refresh": {
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
             "post": {
-                "description": "Get new access token using refresh token",
+                "description": "Add a comment to the achievement, or reply to another comment with parent_id. Anyone who can view the achievement can comment. @username mentions of active users who can view the achievement are stored with the comment and notify the mentioned user. Comments also appear in the achievement history.",
                 "consumes": [
                     "application/json"
                 ],
@@ -929,23 +864,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Achievements"
                 ],
-                "summary": "Refresh access token",
+                "summary": "Add a comment or reply",
                 "parameters": [
                     {
-                        "description": "Refresh token",
+                        "type": "string",
+                        "description": "Achievement Reference ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment body (max 2000 characters) and optional parent_id",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.RefreshTokenRequest"
+                            "$ref": "#/definitions/model.AchievementCommentRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Token refreshed",
+                    "201": {
+                        "description": "Comment added",
                         "schema": {
                             "allOf": [
                                 {
@@ -955,36 +897,54 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.LoginResponse"
+                                            "$ref": "#/definitions/model.AchievementComment"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
                     "401": {
-                        "description": "Invalid refresh token",
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Not authorized to view this achievement",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Achievement not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error or parent comment not on this achievement",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/lecturers": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get list of all lecturers with pagination and their user details",
+                ]
+            }
+        },
+        "/achievements/{id}/history": {
+            "get": {
+                "description": "Get every recorded status transition of the achievement, oldest first (including repeated submissions and rejections). Each entry has the from/to status, actor, actor role, note and timestamp. Comments are merged into the timeline as entries with type 'comment'.",
                 "consumes": [
                     "application/json"
                 ],
@@ -992,28 +952,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Lecturers"
+                    "Achievements"
                 ],
-                "summary": "Get all lecturers",
+                "summary": "Get achievement status history",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Page size (max 100)",
-                        "name": "page_size",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Achievement Reference ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of lecturers with user info",
+                        "description": "Achievement history timeline",
                         "schema": {
                             "allOf": [
                                 {
@@ -1035,18 +988,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden - Not authorized to view this achievement",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
                     }
-                }
-            }
-        },
-        "/lecturers/{id}/advisees": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get all students advised by this lecturer. Dosen Wali can only view own advisees, Admin can view all.",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/reject": {
+            "post": {
+                "description": "Reject submitted achievement with mandatory rejection note. Can only reject if you are the approver of the current approval stage and status is 'submitted'.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1054,42 +1019,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Lecturers"
+                    "Achievements"
                 ],
-                "summary": "Get lecturer's advisees",
+                "summary": "Reject achievement (Dosen Wali only)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Lecturer ID (UUID)",
+                        "description": "Achievement Reference ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Page size (max 100)",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "default": false,
-                        "description": "Include achievements summary",
-                        "name": "include_achievements",
-                        "in": "query"
+                        "description": "Rejection note (required)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RejectAchievementRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of advisees with optional achievement summary",
+                        "description": "Achievement rejected with note",
                         "schema": {
                             "allOf": [
                                 {
@@ -1113,28 +1066,40 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Dosen Wali can only view own advisees",
+                        "description": "Forbidden - Not the approver of the current stage",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Lecturer not found",
+                        "description": "Achievement not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error - rejection note required",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/reports/statistics": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get comprehensive statistics based on role: Mahasiswa gets own stats, Dosen Wali gets advisees' stats, Admin gets all stats. Includes breakdown by type, period, status, and competition level.",
+                ]
+            }
+        },
+        "/achievements/{id}/request-revision": {
+            "post": {
+                "description": "Send a submitted achievement back to the student for revision (status 'revision_requested'). Unlike reject, this does not set rejection_note; the note is recorded in the history. The student can edit and resubmit.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1142,12 +1107,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Reports"
+                    "Achievements"
+                ],
+                "summary": "Request changes on an achievement (Dosen Wali only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement Reference ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "What needs to be changed (required)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RequestRevisionRequest"
+                        }
+                    }
                 ],
-                "summary": "Get achievement statistics",
                 "responses": {
                     "200": {
-                        "description": "Achievement statistics",
+                        "description": "Revision requested",
                         "schema": {
                             "allOf": [
                                 {
@@ -1157,13 +1140,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.AchievementStatistics"
+                                            "type": "object"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -1171,28 +1160,40 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Forbidden - Not the approver of the current stage",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Profile not found (student/lecturer)",
+                        "description": "Achievement not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error - note required",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/reports/student/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get comprehensive achievement report for a specific student including summary, type breakdown, recent achievements, and timeline. Mahasiswa can only view own report, Dosen Wali can view advisees' reports, Admin can view all.",
+                ]
+            }
+        },
+        "/achievements/{id}/restore": {
+            "post": {
+                "description": "Undo a soft delete: the achievement goes back to 'draft' for its owner and the restore is recorded in history. Not possible once the achievement has been purged.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1200,13 +1201,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Reports"
+                    "Achievements"
                 ],
-                "summary": "Get student achievement report",
+                "summary": "Restore deleted achievement (Admin only)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Student ID (UUID)",
+                        "description": "Achievement Reference ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1214,7 +1215,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Student report with all details",
+                        "description": "Achievement restored to draft",
                         "schema": {
                             "allOf": [
                                 {
@@ -1224,7 +1225,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.StudentReport"
+                                            "type": "object"
                                         }
                                     }
                                 }
@@ -1238,28 +1239,34 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Not authorized for this student",
+                        "description": "Forbidden - requires user:manage",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Student not found",
+                        "description": "Achievement not found or already purged",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Achievement is not deleted (code INVALID_TRANSITION) or status was changed by another request",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/students": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get list of all students with pagination. Admin and Mahasiswa can see all, Dosen Wali only sees their advisees.",
+                ]
+            }
+        },
+        "/achievements/{id}/submit": {
+            "post": {
+                "description": "Submit a draft achievement, or resubmit a rejected / revision_requested one after revision, to the advisor. Changes status to 'submitted', increments submission_count and stores a snapshot of the content. The previous rejection note is kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1267,28 +1274,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Achievements"
                 ],
-                "summary": "Get all students",
+                "summary": "Submit achievement for verification (Mahasiswa only)",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Page size (max 100)",
-                        "name": "page_size",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Achievement Reference ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of students with user info and advisor details",
+                        "description": "Achievement submitted with new status and timestamp",
                         "schema": {
                             "allOf": [
                                 {
@@ -1311,23 +1311,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - Not your achievement",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Lecturer profile not found (for Dosen Wali)",
+                        "description": "Achievement not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/students/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get detailed student information including advisor. Mahasiswa can only view own profile, Dosen Wali can view advisees, Admin can view all.",
+                ]
+            }
+        },
+        "/achievements/{id}/verify": {
+            "post": {
+                "description": "Approve submitted achievement. Can only verify if you are the approver of the current approval stage (default chain: the advisor of the student, or an active delegate) and status is 'submitted'. Achievements matching a multi-stage approval chain (APPROVAL_CHAINS_FILE, keyed by achievement_type, min_points and details.competitionLevel) stay 'submitted' after non-final stages; the response then contains the approval progress. Only the final stage sets status 'verified'.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1335,13 +1347,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Achievements"
                 ],
-                "summary": "Get student by ID",
+                "summary": "Verify achievement (Dosen Wali only)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Student ID (UUID)",
+                        "description": "Achievement Reference ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1349,7 +1361,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Student details with user and advisor info",
+                        "description": "Achievement verified with timestamp and verifier info",
                         "schema": {
                             "allOf": [
                                 {
@@ -1373,28 +1385,34 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Not authorized to view this student",
+                        "description": "Forbidden - Not the approver of the current stage",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Student not found",
+                        "description": "Achievement not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid transition (code INVALID_TRANSITION, data.allowed_next_states) or status was changed by another request",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/students/{id}/achievements": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get all achievements of a student with pagination and status filter. Authorization checks apply based on role.",
+                ]
+            }
+        },
+        "/achievements/{id}/withdraw": {
+            "post": {
+                "description": "Pull a submitted achievement back to 'draft' so it can be corrected. Only allowed while no reviewer has picked it up: no approval stage has been decided and nobody other than the student has commented since it was submitted. Clears submitted_at and records the withdrawal in history. submission_count is kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1402,47 +1420,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Achievements"
                 ],
-                "summary": "Get student achievements",
+                "summary": "Withdraw a submitted achievement back to draft (Mahasiswa only)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Student ID (UUID)",
+                        "description": "Achievement Reference ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Page size (max 100)",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "draft",
-                            "submitted",
-                            "verified",
-                            "rejected"
-                        ],
-                        "type": "string",
-                        "description": "Filter by status",
-                        "name": "status",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of achievements with student info",
+                        "description": "Submission withdrawn, status is 'draft'",
                         "schema": {
                             "allOf": [
                                 {
@@ -1466,28 +1458,34 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Not authorized to view this student's achievements",
+                        "description": "Forbidden - Not your achievement",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Student not found",
+                        "description": "Achievement not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not submitted (code INVALID_TRANSITION), already under review, or status was changed by another request",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/students/{id}/advisor": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Assign or change student's advisor (Dosen Wali)",
+                ]
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user with username/email and password. Rejected logins carry a machine-readable \"code\": INVALID_CREDENTIALS, ACCOUNT_INACTIVE, ACCOUNT_LOCKED or ACCOUNT_DELETED. If the user has MFA enabled, or the role requires MFA, no tokens are issued: the response contains an MFA challenge token (data=model.MFAChallengeResponse) that must be exchanged at /auth/mfa/verify. When LDAP is configured, unknown usernames and users without a local password are authenticated against the directory; first-time directory users are provisioned automatically.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1495,30 +1493,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Authentication"
                 ],
-                "summary": "Set student advisor (Admin only)",
+                "summary": "Login to the system",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Student ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Advisor (Lecturer) ID",
+                        "description": "Login credentials",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.SetAdvisorRequest"
+                            "$ref": "#/definitions/model.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Advisor set successfully with updated student info",
+                        "description": "Login successful (or MFA challenge, see description)",
                         "schema": {
                             "allOf": [
                                 {
@@ -1528,7 +1519,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object"
+                                            "$ref": "#/definitions/model.LoginResponse"
                                         }
                                     }
                                 }
@@ -1542,25 +1533,37 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid username or password (INVALID_CREDENTIALS)",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Admin only",
+                        "description": "Account inactive or deleted (ACCOUNT_INACTIVE, ACCOUNT_DELETED), or directory account not mapped to a role",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "Student or advisor (lecturer) not found",
+                    "409": {
+                        "description": "Directory account conflicts with an existing local account",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "422": {
-                        "description": "Validation error",
+                    "423": {
+                        "description": "Account temporarily locked after too many failed logins (ACCOUNT_LOCKED)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many login attempts, see Retry-After header (TOO_MANY_REQUESTS)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Identity provider unavailable",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -1568,14 +1571,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get list of all users with pagination and optional role filter. Includes student or lecturer profile if applicable.",
+        "/auth/logout": {
+            "post": {
+                "description": "Logout current user. The current access token is revoked immediately. Revokes the session of the given refresh token; without a refresh token the session of the current access token is revoked. Use /auth/logout/all to log out from every device.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1583,53 +1581,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
                 ],
-                "summary": "Get all users (Admin only)",
+                "summary": "Logout from system",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Page size (max 100)",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "Admin",
-                            "Mahasiswa",
-                            "Dosen Wali"
-                        ],
-                        "type": "string",
-                        "description": "Filter by role name",
-                        "name": "role",
-                        "in": "query"
+                        "description": "Refresh token of the session to revoke",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.RefreshTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of users",
+                        "description": "Logout successful",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/model.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/model.UserListResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "401": {
@@ -1637,22 +1612,49 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/logout/all": {
+            "post": {
+                "description": "Revoke the current access token, every login session and every refresh token of the current user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Logout from all devices",
+                "responses": {
+                    "200": {
+                        "description": "Logged out from all devices",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
                     },
-                    "403": {
-                        "description": "Forbidden - Admin only",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Create new user with role and profile. Supports creating Mahasiswa with student profile or Dosen Wali with lecturer profile.",
+                ]
+            }
+        },
+        "/auth/mfa/activate": {
+            "post": {
+                "description": "Enable MFA by confirming a code from the authenticator app. Returns one-time recovery codes, shown only once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1660,23 +1662,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
                 ],
-                "summary": "Create new user (Admin only)",
+                "summary": "Confirm TOTP enrollment",
                 "parameters": [
                     {
-                        "description": "User data with optional student_profile or lecturer_profile",
+                        "description": "TOTP code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UserCreateRequest"
+                            "$ref": "#/definitions/model.MFAActivateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User created successfully",
+                    "200": {
+                        "description": "MFA enabled",
                         "schema": {
                             "allOf": [
                                 {
@@ -1686,7 +1688,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.UserResponse"
+                                            "$ref": "#/definitions/model.MFARecoveryCodesResponse"
                                         }
                                     }
                                 }
@@ -1694,52 +1696,34 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid request body or setup not started",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - Admin only",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Role not found",
+                        "description": "Invalid code (INVALID_CREDENTIALS)",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "409": {
-                        "description": "Username/Email/StudentID/LecturerID already exists",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Validation error",
+                        "description": "MFA is already enabled",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            }
-        },
-        "/users/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get detailed user information by ID including profile (student/lecturer)",
+                ]
+            }
+        },
+        "/auth/mfa/challenge/setup": {
+            "post": {
+                "description": "For users whose role requires MFA but who have not enrolled yet (enrollmentRequired=true in the login response). Returns a new TOTP secret; confirm it by calling /auth/mfa/verify with the first code.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1747,21 +1731,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
                 ],
-                "summary": "Get user by ID (Admin only)",
+                "summary": "Set up TOTP during login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Challenge token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MFAChallengeSetupRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User details",
+                        "description": "TOTP secret and otpauth URL",
                         "schema": {
                             "allOf": [
                                 {
@@ -1771,40 +1757,37 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.UserResponse"
+                                            "$ref": "#/definitions/model.MFASetupResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid request body",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - Admin only",
+                    "401": {
+                        "description": "Invalid or used challenge token",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "409": {
+                        "description": "MFA is already enabled",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update user information (email, full_name, is_active). Cannot update username or password.",
+            }
+        },
+        "/auth/mfa/disable": {
+            "post": {
+                "description": "Disable MFA for the current user. Requires the password and a TOTP or recovery code. Not allowed when the role requires MFA.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1812,85 +1795,108 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
                 ],
-                "summary": "Update user (Admin only)",
+                "summary": "Disable MFA",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update data",
+                        "description": "Password and code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UserUpdateRequest"
+                            "$ref": "#/definitions/model.MFADisableRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User updated successfully",
+                        "description": "MFA disabled",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/model.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/model.UserResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid request body or MFA not enabled",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid password or code (INVALID_CREDENTIALS)",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - Admin only",
+                    "409": {
+                        "description": "MFA is required for the role",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/mfa/setup": {
+            "post": {
+                "description": "Generate a new TOTP secret for the current user. MFA stays disabled until the first code is confirmed at /auth/mfa/activate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Start TOTP enrollment",
+                "responses": {
+                    "200": {
+                        "description": "TOTP secret and otpauth URL",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.MFASetupResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "409": {
-                        "description": "Email already used by another user",
+                        "description": "MFA is already enabled",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Delete user and associated profile (student/lecturer). This will also cascade delete related data.",
+                ]
+            }
+        },
+        "/auth/mfa/verify": {
+            "post": {
+                "description": "Second login step. Exchange the challenge token from /auth/login and a TOTP code (or a one-time recovery code) for access and refresh tokens. The challenge token can be used once. Wrong codes count as failed logins. When MFA is being enrolled during login, the response also contains the recovery codes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1898,39 +1904,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
                 ],
-                "summary": "Delete user (Admin only)",
+                "summary": "Complete login with an MFA code",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Challenge token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MFAVerifyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User deleted successfully",
+                        "description": "Login successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, or TOTP not set up yet (MFA_REQUIRED)",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid or used challenge token, or invalid code (INVALID_CREDENTIALS)",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Admin only",
+                        "description": "Account inactive or deleted (ACCOUNT_INACTIVE, ACCOUNT_DELETED)",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "423": {
+                        "description": "Account temporarily locked (ACCOUNT_LOCKED)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts (TOO_MANY_REQUESTS)",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -1938,45 +1970,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/role": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Change user's role. Note: Changing role does not automatically create/delete profiles.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "Exchange the authorization code for an ID token, then log in the linked local user. First-time users are provisioned just-in-time: the role comes from the directory groups, and existing students/lecturers are linked by NIM/NIP. The response is the same as /auth/login (tokens, or an MFA challenge).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
                 ],
-                "summary": "Assign role to user (Admin only)",
+                "summary": "Campus SSO callback (OIDC)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID (UUID)",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Role name (Admin, Mahasiswa, or Dosen Wali)",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.AssignRoleRequest"
-                        }
+                        "type": "string",
+                        "description": "State from /auth/oidc/login",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Role assigned successfully",
+                        "description": "Login successful (or MFA challenge)",
                         "schema": {
                             "allOf": [
                                 {
@@ -1986,7 +2008,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.UserResponse"
+                                            "$ref": "#/definitions/model.LoginResponse"
                                         }
                                     }
                                 }
@@ -1994,354 +2016,4012 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid or expired login state",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Identity provider login failed (INVALID_CREDENTIALS)",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Admin only",
+                        "description": "Account inactive/deleted or not mapped to a role",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "User or role not found",
+                    "409": {
+                        "description": "Directory account conflicts with an existing local account",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Identity provider unavailable",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.APIResponse": {
-            "type": "object",
-            "properties": {
-                "data": {},
-                "error": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
         },
-        "model.AchievementCreateRequest": {
-            "type": "object",
-            "required": [
-                "achievement_type",
-                "description",
-                "title"
-            ],
-            "properties": {
-                "achievement_type": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "details": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "points": {
-                    "type": "integer"
-                },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Redirect the browser to the campus identity provider. A short-lived state cookie binds the callback to this browser.",
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Start campus SSO login (OIDC)",
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the identity provider"
+                    },
+                    "404": {
+                        "description": "OIDC login is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Identity provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
                     }
-                },
-                "title": {
-                    "type": "string"
                 }
             }
         },
-        "model.AchievementListResponse": {
-            "type": "object",
-            "properties": {
-                "achievements": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.AchievementResponse"
+        "/auth/password": {
+            "post": {
+                "description": "Change the password of the current user. The old password is required and the new one must satisfy the password policy. All existing sessions (access and refresh tokens) are revoked, so the user has to login again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Change own password",
+                "parameters": [
+                    {
+                        "description": "Old and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password changed",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Old password is incorrect (INVALID_CREDENTIALS)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "New password violates the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
                     }
                 },
-                "page": {
-                    "type": "integer"
-                },
-                "page_size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/password/reset": {
+            "post": {
+                "description": "Redeem a password reset token issued by an admin. The token can be used once and expires after PASSWORD_RESET_TOKEN_TTL. All existing sessions of the user are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Reset password with a one-time token",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password reset",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid, used or expired reset token",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "New password violates the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/profile": {
+            "get": {
+                "description": "Get profile of currently authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Get current user profile",
+                "responses": {
+                    "200": {
+                        "description": "User profile",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Get new access token using refresh token. The refresh token is rotated: the old one stops working, and reusing an already-rotated token revokes the whole token family.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token refreshed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, revoked or reused refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Account inactive or deleted (ACCOUNT_INACTIVE, ACCOUNT_DELETED)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Account temporarily locked (ACCOUNT_LOCKED)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "description": "List the current user's active login sessions (device/user-agent, IP, login time, last use). The session used by this request is marked as current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "List active sessions",
+                "responses": {
+                    "200": {
+                        "description": "Active sessions",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.SessionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "description": "Log out one of the current user's sessions. Its refresh token is revoked and its access token is rejected immediately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Revoke a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/tokens": {
+            "get": {
+                "description": "List the current user's personal API tokens that are not revoked. Only the token prefix is shown. Not available when authenticated with an API token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "List API tokens",
+                "responses": {
+                    "200": {
+                        "description": "API tokens",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.APITokenResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Authenticated with an API token",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a personal API token for scripts and integrations. Scopes must be a subset of the current user's permissions; expires_in_days 0 means no expiry. The token is returned only once. Use it as \"Authorization: Bearer \u003ctoken\u003e\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Create API token",
+                "parameters": [
+                    {
+                        "description": "Token name, scopes and optional expiry",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.APITokenCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "API token created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.APITokenCreatedResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or scope not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Authenticated with an API token",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/tokens/{id}": {
+            "delete": {
+                "description": "Revoke one of the current user's API tokens. It is rejected immediately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Revoke API token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API token revoked",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Authenticated with an API token",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "API token not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/lecturers": {
+            "get": {
+                "description": "Get list of all lecturers with pagination and their user details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lecturers"
+                ],
+                "summary": "Get all lecturers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of lecturers with user info",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/lecturers/{id}/advisees": {
+            "get": {
+                "description": "Get all students advised by this lecturer. Dosen Wali can only view own advisees, Admin can view all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lecturers"
+                ],
+                "summary": "Get lecturer's advisees",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lecturer ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include achievements summary",
+                        "name": "include_achievements",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of advisees with optional achievement summary",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Dosen Wali can only view own advisees",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Lecturer not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/lecturers/{id}/delegations": {
+            "get": {
+                "description": "Delegations granted by or to the lecturer, newest first. active is true when the delegation is not revoked and covers today. Dosen Wali can only view own delegations, Admin can view all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lecturers"
+                ],
+                "summary": "Get verifier delegations of a lecturer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lecturer ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of delegations",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.VerifierDelegation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Not own delegations",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Lecturer not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Grant another lecturer verify/reject rights over the advisees of lecturer {id} between starts_on and ends_on (inclusive). Verifications by the delegate are recorded in history as on behalf of the advisor. Dosen Wali can only delegate own advisees, Admin can delegate for any lecturer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lecturers"
+                ],
+                "summary": "Delegate verification of advisees to another lecturer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Advisor lecturer ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delegate and date range (YYYY-MM-DD)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateDelegationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Delegation created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.VerifierDelegation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Not own advisees",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Lecturer not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error, invalid date range, self delegation or delegate not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/lecturers/{id}/delegations/{delegationId}": {
+            "delete": {
+                "description": "Revoke a delegation before it ends. Dosen Wali can only revoke own delegations, Admin can revoke any.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lecturers"
+                ],
+                "summary": "Revoke a verifier delegation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Advisor lecturer ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delegation ID (UUID)",
+                        "name": "delegationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delegation revoked",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Not own delegations",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Delegation not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Delegation already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications": {
+            "get": {
+                "description": "In-app notifications of the current user, newest first (max 100), e.g. verification reminders and escalations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Get my notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notifications",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Notification"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications/{id}/read": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Mark notification as read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification marked as read",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/permissions": {
+            "get": {
+                "description": "Get all permissions that can be attached to roles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "List permissions (Admin only)",
+                "responses": {
+                    "200": {
+                        "description": "List of permissions",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.PermissionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reports/overdue": {
+            "get": {
+                "description": "List submitted achievements waiting longer than the reminder threshold (VERIFICATION_REMINDER_AFTER), oldest first. Items past VERIFICATION_ESCALATE_AFTER are in stage \"escalation\". Dosen Wali sees advisees, department reviewers see their program study, Admin sees all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get overdue verification report",
+                "parameters": [
+                    {
+                        "enum": [
+                            "reminder",
+                            "escalation"
+                        ],
+                        "type": "string",
+                        "description": "Filter by SLA stage",
+                        "name": "stage",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Overdue achievements",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.OverdueReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid stage",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reports/statistics": {
+            "get": {
+                "description": "Get comprehensive statistics based on role: Mahasiswa gets own stats, Dosen Wali gets advisees' stats, Admin gets all stats. Includes breakdown by type, period, status, and competition level.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get achievement statistics",
+                "responses": {
+                    "200": {
+                        "description": "Achievement statistics",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AchievementStatistics"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Profile not found (student/lecturer)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reports/student/{id}": {
+            "get": {
+                "description": "Get comprehensive achievement report for a specific student including summary, type breakdown, recent achievements, and timeline. Mahasiswa can only view own report, Dosen Wali can view advisees' reports, Admin can view all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get student achievement report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Student ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Student report with all details",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.StudentReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Not authorized for this student",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Student not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/roles": {
+            "get": {
+                "description": "Get all roles with their permissions. Built-in roles (Admin, Mahasiswa, Dosen Wali) are flagged with built_in=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "List roles (Admin only)",
+                "responses": {
+                    "200": {
+                        "description": "List of roles",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.RoleResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new role, optionally with an initial list of permission names.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Create role (Admin only)",
+                "parameters": [
+                    {
+                        "description": "Role data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RoleCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Role created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.RoleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Permission not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Role name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/roles/{id}": {
+            "get": {
+                "description": "Get a role with its permissions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get role detail (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role detail",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.RoleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update role name and/or description. Built-in roles cannot be renamed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Update role (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RoleUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.RoleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Built-in role cannot be renamed or name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a custom role. Built-in roles and roles still assigned to users cannot be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Delete role (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role deleted",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Built-in role or role still assigned to users",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/roles/{id}/permissions": {
+            "post": {
+                "description": "Grant a permission to a role. Takes effect on the next request of every user with the role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Attach permission to role (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permission name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RolePermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Permission attached",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.RoleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role or permission not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/roles/{id}/permissions/{permission}": {
+            "delete": {
+                "description": "Revoke a permission from a role. Takes effect on the next request of every user with the role. user:manage cannot be removed from Admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Detach permission from role (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Permission name, e.g. achievement:verify",
+                        "name": "permission",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Permission detached",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.RoleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role or permission not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Admin role must keep user:manage",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/students": {
+            "get": {
+                "description": "Get list of all students with pagination. Admin and Mahasiswa can see all, Dosen Wali only sees their advisees.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Get all students",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of students with user info and advisor details",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Lecturer profile not found (for Dosen Wali)",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/students/{id}": {
+            "get": {
+                "description": "Get detailed student information including advisor. Mahasiswa can only view own profile, Dosen Wali can view advisees, Admin can view all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Get student by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Student ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Student details with user and advisor info",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Not authorized to view this student",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Student not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/students/{id}/achievements": {
+            "get": {
+                "description": "Get all achievements of a student with pagination and status filter. Authorization checks apply based on role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Get student achievements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Student ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "submitted",
+                            "verified",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of achievements with student info",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Not authorized to view this student's achievements",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Student not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/students/{id}/advisor": {
+            "put": {
+                "description": "Assign or change student's advisor (Dosen Wali)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Set student advisor (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Student ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Advisor (Lecturer) ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetAdvisorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Advisor set successfully with updated student info",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Student or advisor (lecturer) not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Get list of all users with pagination and optional role filter. Includes student or lecturer profile if applicable.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users (Admin only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Admin",
+                            "Mahasiswa",
+                            "Dosen Wali"
+                        ],
+                        "type": "string",
+                        "description": "Filter by role name",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UserListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create new user with role and profile. Supports creating Mahasiswa with student profile or Dosen Wali with lecturer profile.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create new user (Admin only)",
+                "parameters": [
+                    {
+                        "description": "User data with optional student_profile or lecturer_profile",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UserCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Username/Email/StudentID/LecturerID already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed or password violates the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Get detailed user information by ID including profile (student/lecturer)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by ID (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User details",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update user information (email, full_name, is_active). Cannot update username or password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UserUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already used by another user, or deactivating own account / last active user with user:manage",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Soft delete user: the account is marked deleted (account_status = \"deleted\"), existing tokens stop working immediately, and the student/lecturer profile is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Cannot delete own account or last active user with user:manage",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/impersonate": {
+            "post": {
+                "description": "Issue a short-lived access token acting as another user, for support. The token carries both the admin (claim \"act\") and the user. Every response made with it has the X-Impersonated-By header, and every request is written to the audit log. By default only GET requests are allowed (IMPERSONATION_ALLOW_WRITE). No refresh token is issued; POST /auth/logout ends the impersonation. Administrators cannot be impersonated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Impersonate a user (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason (recorded in the audit log)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ImpersonateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Impersonation started",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ImpersonationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or impersonating yourself",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only, target is an administrator, or already impersonating",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User is inactive",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/password-reset": {
+            "post": {
+                "description": "Issue a one-time, time-limited password reset token for a user. Previously issued tokens for the user stop working. The token is redeemed via POST /auth/password/reset.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Issue password reset token (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Reset token issued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.PasswordResetTokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "description": "Change user's role. Note: Changing role does not automatically create/delete profiles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Assign role to user (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role name (Admin, Mahasiswa, or Dosen Wali)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AssignRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role assigned successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User or role not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Cannot demote own account or last active user with user:manage",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/sessions": {
+            "get": {
+                "description": "List the active login sessions of a user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List a user's active sessions (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active sessions",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.SessionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/sessions/{sessionId}": {
+            "delete": {
+                "description": "Log out one session of a user. Its refresh token is revoked and its access token is rejected immediately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Revoke a user's session (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/unlock": {
+            "put": {
+                "description": "Clear the failed login counter and lift a lockout caused by too many failed logins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Unlock user account (Admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User unlocked successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "model.APIResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "kode error yang bisa dibaca mesin (lihat ErrCode*)",
+                    "type": "string"
+                },
+                "data": {},
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.APITokenCreateRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_in_days": {
+                    "description": "0 = tidak kadaluarsa",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
+                    "description": "nama permission",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.APITokenCreatedResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "type": "string"
+                },
+                "token_prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.APITokenResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AchievementChangesResponse": {
+            "type": "object",
+            "properties": {
+                "achievement_id": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldChange"
+                    }
+                },
+                "compared_to_submission": {
+                    "description": "nil jika belum ada pengajuan sebelumnya",
+                    "type": "integer"
+                },
+                "previous_rejection_note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "submission_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AchievementComment": {
+            "type": "object",
+            "properties": {
+                "achievement_ref_id": {
+                    "type": "string"
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "author_name": {
+                    "description": "dari join ke users, bukan kolom",
+                    "type": "string"
+                },
+                "author_role": {
+                    "description": "role penulis saat komentar dibuat",
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CommentMention"
+                    }
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AchievementComment"
+                    }
+                }
+            }
+        },
+        "model.AchievementCommentRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "parent_id": {
+                    "description": "isi untuk membalas komentar lain",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AchievementCreateRequest": {
+            "type": "object",
+            "required": [
+                "achievement_type",
+                "description",
+                "title"
+            ],
+            "properties": {
+                "achievement_type": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AchievementListResponse": {
+            "type": "object",
+            "properties": {
+                "achievements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AchievementResponse"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AchievementResponse": {
+            "type": "object",
+            "properties": {
+                "achievement_type": {
+                    "type": "string"
+                },
+                "approval": {
+                    "$ref": "#/definitions/model.ApprovalProgress"
+                },
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Attachment"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "id": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "rejection_note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "submission_count": {
+                    "type": "integer"
+                },
+                "submitted_at": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "verified_at": {
+                    "type": "string"
+                },
+                "verified_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AchievementStatistics": {
+            "type": "object",
+            "properties": {
+                "competition_level_distribution": {
+                    "description": "Distribusi tingkat kompetisi",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "status_breakdown": {
+                    "description": "Breakdown by status",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "top_students": {
+                    "description": "Top mahasiswa berprestasi",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TopStudent"
+                    }
+                },
+                "total_achievements": {
+                    "description": "Total keseluruhan",
+                    "type": "integer"
+                },
+                "total_by_period": {
+                    "description": "Total prestasi per periode (tahun-bulan)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PeriodStats"
+                    }
+                },
+                "total_by_type": {
+                    "description": "Total prestasi per tipe",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "model.AchievementUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "achievement_type": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ApprovalProgress": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "stage": {
+                    "type": "integer"
+                },
+                "stage_name": {
+                    "type": "string"
+                },
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "total_stages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AssignRoleRequest": {
+            "type": "object",
+            "required": [
+                "role_name"
+            ],
+            "properties": {
+                "role_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Attachment": {
+            "type": "object",
+            "properties": {
+                "file_name": {
+                    "type": "string"
+                },
+                "file_type": {
+                    "type": "string"
+                },
+                "file_url": {
+                    "type": "string"
+                },
+                "uploaded_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "kode HTTP yang setara jika item dikirim sendiri",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "status prestasi setelah diproses (atau status saat ini jika gagal)",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.BatchRejectItem": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
                 },
-                "total_pages": {
+                "rejection_note": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BatchRejectRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.BatchRejectItem"
+                    }
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "partial"
+                    ]
+                },
+                "rejection_note": {
+                    "description": "dipakai untuk item tanpa rejection_note sendiri",
+                    "type": "string"
+                }
+            }
+        },
+        "model.BatchResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BatchItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                },
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "model.AchievementResponse": {
+        "model.BatchVerifyRequest": {
             "type": "object",
+            "required": [
+                "ids"
+            ],
             "properties": {
-                "achievement_type": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "partial"
+                    ]
+                }
+            }
+        },
+        "model.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
                     "type": "string"
                 },
-                "attachments": {
+                "old_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CommentMention": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateDelegationRequest": {
+            "type": "object",
+            "required": [
+                "delegate_id",
+                "ends_on",
+                "starts_on"
+            ],
+            "properties": {
+                "delegate_id": {
+                    "type": "string"
+                },
+                "ends_on": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "starts_on": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FieldChange": {
+            "type": "object",
+            "properties": {
+                "current": {},
+                "field": {
+                    "description": "mis. \"title\", \"details.rank\"",
+                    "type": "string"
+                },
+                "previous": {}
+            }
+        },
+        "model.ImpersonateRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "dicatat di audit log",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "model.ImpersonationResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "impersonated_by": {
+                    "type": "string"
+                },
+                "read_only": {
+                    "description": "true = hanya GET yang diizinkan",
+                    "type": "boolean"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.UserResponse"
+                }
+            }
+        },
+        "model.LecturerProfileRequest": {
+            "type": "object",
+            "required": [
+                "department",
+                "lecturer_id"
+            ],
+            "properties": {
+                "department": {
+                    "type": "string"
+                },
+                "lecturer_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LecturerResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "department": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lecturer_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
+                    "description": "hanya saat MFA baru diaktifkan lewat login",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Attachment"
+                        "type": "string"
+                    }
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.UserResponse"
+                }
+            }
+        },
+        "model.MFAActivateRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MFAChallengeSetupRequest": {
+            "type": "object",
+            "required": [
+                "challengeToken"
+            ],
+            "properties": {
+                "challengeToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MFADisableRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "description": "kode TOTP atau recovery code",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MFARecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                }
+            }
+        },
+        "model.MFASetupResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_url": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MFAVerifyRequest": {
+            "type": "object",
+            "required": [
+                "challengeToken",
+                "code"
+            ],
+            "properties": {
+                "challengeToken": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Notification": {
+            "type": "object",
+            "properties": {
+                "achievement_ref_id": {
+                    "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OverdueAchievement": {
+            "type": "object",
+            "properties": {
+                "advisor_id": {
+                    "type": "string"
+                },
+                "advisor_name": {
+                    "type": "string"
+                },
+                "approval_stage_name": {
+                    "description": "diisi service dari rantai persetujuan",
                     "type": "string"
                 },
-                "details": {
-                    "type": "object",
-                    "additionalProperties": true
+                "escalated_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "points": {
-                    "type": "integer"
-                },
-                "rejection_note": {
+                "program_study": {
                     "type": "string"
                 },
-                "status": {
+                "reminded_at": {
                     "type": "string"
                 },
-                "student_id": {
+                "stage": {
+                    "description": "reminder / escalation",
                     "type": "string"
                 },
-                "submitted_at": {
+                "stage_started_at": {
+                    "description": "persetujuan tahap sebelumnya; nil di tahap pertama",
                     "type": "string"
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "title": {
+                "student_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "student_name": {
                     "type": "string"
                 },
-                "verified_at": {
+                "student_nim": {
                     "type": "string"
                 },
-                "verified_by": {
+                "submitted_at": {
                     "type": "string"
+                },
+                "waiting_days": {
+                    "type": "integer"
                 }
             }
         },
-        "model.AchievementStatistics": {
+        "model.OverdueReport": {
             "type": "object",
             "properties": {
-                "competition_level_distribution": {
-                    "description": "Distribusi tingkat kompetisi",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "escalate_after_days": {
+                    "type": "number"
                 },
-                "status_breakdown": {
-                    "description": "Breakdown by status",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "escalated": {
+                    "type": "integer"
                 },
-                "top_students": {
-                    "description": "Top mahasiswa berprestasi",
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.TopStudent"
+                        "$ref": "#/definitions/model.OverdueAchievement"
                     }
                 },
-                "total_achievements": {
-                    "description": "Total keseluruhan",
+                "reminder_after_days": {
+                    "type": "number"
+                },
+                "total": {
                     "type": "integer"
+                }
+            }
+        },
+        "model.PasswordResetTokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
                 },
-                "total_by_period": {
-                    "description": "Total prestasi per periode (tahun-bulan)",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.PeriodStats"
-                    }
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PeriodStats": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
                 },
-                "total_by_type": {
-                    "description": "Total prestasi per tipe",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "period": {
+                    "description": "Format: \"2025-01\", \"2025-02\"",
+                    "type": "string"
                 }
             }
         },
-        "model.AchievementUpdateRequest": {
+        "model.PermissionResponse": {
             "type": "object",
             "properties": {
-                "achievement_type": {
+                "action": {
                     "type": "string"
                 },
                 "description": {
                     "type": "string"
                 },
-                "details": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "points": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "name": {
+                    "type": "string"
                 },
-                "title": {
+                "resource": {
                     "type": "string"
                 }
             }
         },
-        "model.AssignRoleRequest": {
+        "model.RefreshTokenRequest": {
             "type": "object",
             "required": [
-                "role_name"
+                "refreshToken"
             ],
             "properties": {
-                "role_name": {
+                "refreshToken": {
                     "type": "string"
                 }
             }
         },
-        "model.Attachment": {
+        "model.RejectAchievementRequest": {
             "type": "object",
+            "required": [
+                "rejection_note"
+            ],
             "properties": {
-                "file_name": {
-                    "type": "string"
-                },
-                "file_type": {
-                    "type": "string"
-                },
-                "file_url": {
+                "rejection_note": {
                     "type": "string"
-                },
-                "uploaded_at": {
+                }
+            }
+        },
+        "model.RequestRevisionRequest": {
+            "type": "object",
+            "required": [
+                "note"
+            ],
+            "properties": {
+                "note": {
                     "type": "string"
                 }
             }
         },
-        "model.LecturerProfileRequest": {
+        "model.ResetPasswordRequest": {
             "type": "object",
             "required": [
-                "department",
-                "lecturer_id"
+                "new_password",
+                "token"
             ],
             "properties": {
-                "department": {
+                "new_password": {
                     "type": "string"
                 },
-                "lecturer_id": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "model.LecturerResponse": {
+        "model.RoleCreateRequest": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "created_at": {
+                "description": {
                     "type": "string"
                 },
-                "department": {
-                    "type": "string"
+                "mfa_required": {
+                    "type": "boolean"
                 },
-                "id": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
                 },
-                "lecturer_id": {
-                    "type": "string"
+                "permissions": {
+                    "description": "nama permission, opsional",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "model.LoginRequest": {
+        "model.RolePermissionRequest": {
             "type": "object",
             "required": [
-                "password",
-                "username"
+                "permission"
             ],
             "properties": {
-                "password": {
-                    "type": "string"
-                },
-                "username": {
+                "permission": {
                     "type": "string"
                 }
             }
         },
-        "model.LoginResponse": {
+        "model.RoleResponse": {
             "type": "object",
             "properties": {
-                "refreshToken": {
+                "built_in": {
+                    "type": "boolean"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "token": {
+                "description": {
+                    "description": "⭐ TAMBAHKAN INI JUGA (opsional)",
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/model.UserResponse"
-                }
-            }
-        },
-        "model.PeriodStats": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
                 },
-                "period": {
-                    "description": "Format: \"2025-01\", \"2025-02\"",
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "name": {
                     "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "model.RefreshTokenRequest": {
+        "model.RoleUpdateRequest": {
             "type": "object",
-            "required": [
-                "refreshToken"
-            ],
             "properties": {
-                "refreshToken": {
+                "description": {
                     "type": "string"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
                 }
             }
         },
-        "model.RejectAchievementRequest": {
+        "model.SessionResponse": {
             "type": "object",
-            "required": [
-                "rejection_note"
-            ],
             "properties": {
-                "rejection_note": {
+                "current": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
                     "type": "string"
                 }
             }
@@ -2591,18 +6271,29 @@ const docTemplate = `{
         "model.UserResponse": {
             "type": "object",
             "properties": {
+                "account_status": {
+                    "description": "active, inactive, locked, deleted",
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "email": {
                     "type": "string"
                 },
+                "failed_login_attempts": {
+                    "type": "integer"
+                },
                 "full_name": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
+                "impersonated_by": {
+                    "description": "username admin jika sedang impersonation",
+                    "type": "string"
+                },
                 "is_active": {
                     "type": "boolean"
                 },
@@ -2614,6 +6305,12 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "locked_until": {
+                    "type": "string"
+                },
+                "mfa_enabled": {
+                    "type": "boolean"
+                },
                 "permissions": {
                     "type": "array",
                     "items": {
@@ -2623,6 +6320,9 @@ const docTemplate = `{
                 "role": {
                     "type": "string"
                 },
+                "role_id": {
+                    "type": "string"
+                },
                 "student_profile": {
                     "description": "jika role = Mahasiswa",
                     "allOf": [
@@ -2650,6 +6350,52 @@ const docTemplate = `{
                     "type": "boolean"
                 }
             }
+        },
+        "model.VerifierDelegation": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "belum dicabut dan berlaku hari ini (dihitung service)",
+                    "type": "boolean"
+                },
+                "advisor_id": {
+                    "type": "string"
+                },
+                "advisor_name": {
+                    "description": "dari join ke users, bukan kolom",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "delegate_id": {
+                    "type": "string"
+                },
+                "delegate_name": {
+                    "description": "dari join ke users, bukan kolom",
+                    "type": "string"
+                },
+                "ends_on": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "starts_on": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
+	"strings"
+	"UASBE/app/identity"
 	"UASBE/app/repository"
+	"UASBE/app/workflow"
 	"UASBE/routes"
 	"UASBE/app/service"
 	"UASBE/config"
 	"UASBE/database"
+	"UASBE/middleware"
 	"UASBE/utils"
 
 	_ "UASBE/docs" // ← TAMBAHKAN INI (Import swagger docs)
@@ -40,8 +45,15 @@ func main() {
 	// Load config
 	config.LoadEnv()
 
+	// Validate config: tolak start jika ada setting wajib yang kosong / lemah
+	if err := config.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
 	// Initialize JWT
-	utils.InitJWT()
+	if err := utils.InitJWT(); err != nil {
+		log.Fatal("Failed to load JWT keys:", err)
+	}
 
 	// Connect PostgreSQL database
 	database.ConnectDatabase()
@@ -56,19 +68,101 @@ func main() {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(sqlDB)
 	roleRepo := repository.NewRoleRepository(sqlDB)
-	permRepo := repository.NewPermissionRepository(sqlDB)
+	permRepo := repository.NewCachedPermissionRepository(repository.NewPermissionRepository(sqlDB), config.AppConfig.PermissionCacheTTL)
 	studentRepo := repository.NewStudentRepository(sqlDB)
 	lecturerRepo := repository.NewLecturerRepository(sqlDB)
 	achievementRepo := repository.NewAchievementRepository(sqlDB, database.MongoDB)
+	achievementCommentRepo := repository.NewAchievementCommentRepository(sqlDB)
 	reportRepo := repository.NewReportRepository(sqlDB, database.MongoDB)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(sqlDB)
+	passwordResetRepo := repository.NewPasswordResetRepository(sqlDB)
+	mfaRepo := repository.NewMFARepository(sqlDB)
+	sessionRepo := repository.NewSessionRepository(sqlDB)
+	apiTokenRepo := repository.NewAPITokenRepository(sqlDB)
+	identityRepo := repository.NewIdentityRepository(sqlDB)
+	auditLogRepo := repository.NewAuditLogRepository(sqlDB)
+	notificationRepo := repository.NewNotificationRepository(sqlDB)
+	verificationSLARepo := repository.NewVerificationSLARepository(sqlDB)
+
+	// Token denylist: postgres (shared antar instance) atau memory (single instance)
+	var tokenRevocationRepo repository.TokenRevocationRepository
+	if config.AppConfig.TokenStore == "memory" {
+		tokenRevocationRepo = repository.NewMemoryTokenRevocationRepository()
+	} else {
+		tokenRevocationRepo = repository.NewTokenRevocationRepository(sqlDB)
+	}
+	middleware.SetTokenRevocationRepository(tokenRevocationRepo)
+	middleware.SetPermissionRepository(permRepo)
+	middleware.SetSessionRepository(sessionRepo)
+	middleware.SetAPITokenRepository(apiTokenRepo)
+	middleware.SetAuditLogRepository(auditLogRepo)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, roleRepo, permRepo)
-	userService := service.NewUserService(userRepo, roleRepo, permRepo, studentRepo, lecturerRepo)
+	authService := service.NewAuthService(userRepo, roleRepo, permRepo, refreshTokenRepo, tokenRevocationRepo, passwordResetRepo, mfaRepo, sessionRepo)
+	roleService := service.NewRoleService(roleRepo, permRepo)
+	apiTokenService := service.NewAPITokenService(apiTokenRepo)
+	userService := service.NewUserService(userRepo, roleRepo, permRepo, studentRepo, lecturerRepo, tokenRevocationRepo, passwordResetRepo, sessionRepo, auditLogRepo)
 	studentService := service.NewStudentService(studentRepo, lecturerRepo, achievementRepo, userRepo)
 	lecturerService := service.NewLecturerService(lecturerRepo, studentRepo, achievementRepo, userRepo)
-	achievementService := service.NewAchievementService(achievementRepo, studentRepo, lecturerRepo, userRepo)
+	achievementService := service.NewAchievementService(achievementRepo, studentRepo, lecturerRepo, userRepo, achievementCommentRepo)
+	achievementService.SetPermissionRepository(permRepo)
 	reportService := service.NewReportService(reportRepo, achievementRepo, studentRepo, lecturerRepo, userRepo) 
+	verificationSLAService := service.NewVerificationSLAService(verificationSLARepo, studentRepo, lecturerRepo)
+	achievementPurgeService := service.NewAchievementPurgeService(achievementRepo, "./uploads")
+	notificationService := service.NewNotificationService(notificationRepo)
+
+	// Rantai persetujuan bertahap (mis. dosen wali lalu Kaprodi untuk kompetisi internasional)
+	if config.AppConfig.ApprovalChainsFile != "" {
+		approvalChains, err := workflow.LoadApprovalChains(config.AppConfig.ApprovalChainsFile)
+		if err != nil {
+			log.Fatal("Failed to load approval chains:", err)
+		}
+		achievementService.SetApprovalChains(approvalChains)
+		verificationSLAService.SetApprovalChains(approvalChains)
+	}
+
+	// Scheduler SLA verifikasi: pengingat ke dosen wali & eskalasi prestasi yang lama di status submitted
+	if config.AppConfig.VerificationSLAInterval > 0 {
+		verificationSLAService.Start(context.Background(), config.AppConfig.VerificationSLAInterval)
+	}
+
+	// Purge prestasi yang di-soft delete melewati masa retensi (dokumen MongoDB + file lampiran)
+	if config.AppConfig.AchievementPurgeInterval > 0 {
+		achievementPurgeService.Start(context.Background(), config.AppConfig.AchievementPurgeInterval)
+	}
+
+	// Login lewat direktori kampus (opsional, aktif jika OIDC_ISSUER / LDAP_URL di-set)
+	cfg := config.AppConfig
+	var identityProviders []identity.Provider
+	if cfg.OIDCIssuer != "" {
+		identityProviders = append(identityProviders, identity.NewOIDCProvider(identity.OIDCConfig{
+			Issuer:          cfg.OIDCIssuer,
+			ClientID:        cfg.OIDCClientID,
+			ClientSecret:    cfg.OIDCClientSecret,
+			RedirectURL:     cfg.OIDCRedirectURL,
+			Scopes:          strings.Fields(cfg.OIDCScopes),
+			GroupsClaim:     cfg.OIDCGroupsClaim,
+			StudentIDClaim:  cfg.IdentityStudentIDAttr,
+			LecturerIDClaim: cfg.IdentityLecturerIDAttr,
+		}, nil))
+	}
+	if cfg.LDAPURL != "" {
+		identityProviders = append(identityProviders, identity.NewLDAPProvider(identity.LDAPConfig{
+			URL:                 cfg.LDAPURL,
+			BindDN:              cfg.LDAPBindDN,
+			BindPassword:        cfg.LDAPBindPassword,
+			BaseDN:              cfg.LDAPBaseDN,
+			UserAttribute:       cfg.LDAPUserAttr,
+			GroupAttribute:      cfg.LDAPGroupAttr,
+			StudentIDAttribute:  cfg.IdentityStudentIDAttr,
+			LecturerIDAttribute: cfg.IdentityLecturerIDAttr,
+		}))
+	}
+	if len(identityProviders) > 0 {
+		provisioner := identity.NewProvisioner(userRepo, roleRepo, studentRepo, lecturerRepo, identityRepo, tokenRevocationRepo,
+			identity.ParseGroupRoles(cfg.IdentityGroupRoles), cfg.IdentityDefaultRole)
+		authService.SetIdentityProviders(provisioner, identityProviders...)
+	}
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
@@ -100,12 +194,14 @@ func main() {
 	})
 
 	// Register routes
-	routes.AuthRoutes(app, authService)
+	routes.AuthRoutes(app, authService, apiTokenService)
 	routes.UserRoutes(app, userService)
+	routes.RoleRoutes(app, roleService)
 	routes.StudentRoutes(app, studentService)
 	routes.LecturerRoutes(app, lecturerService)
 	routes.AchievementRoutes(app, achievementService)
-	routes.ReportRoutes(app, reportService) 
+	routes.ReportRoutes(app, reportService, verificationSLAService)
+	routes.NotificationRoutes(app, notificationService)
 
 	// Start server
 	port := config.AppConfig.Port
@@ -1,12 +1,66 @@
 package middleware
 
 import (
+	"log"
 	"strings"
+	"time"
+	"UASBE/app/model"
+	"UASBE/app/repository"
+	"UASBE/config"
 	"UASBE/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// tokenRevocationRepo - Di-set saat startup (lihat main.go).
+// Jika nil, pengecekan denylist dilewati (misal di unit test).
+var tokenRevocationRepo repository.TokenRevocationRepository
+
+func SetTokenRevocationRepository(repo repository.TokenRevocationRepository) {
+	tokenRevocationRepo = repo
+}
+
+// permissionRepo - Di-set saat startup (lihat main.go), sebaiknya lewat
+// NewCachedPermissionRepository. Jika nil, permission dari JWT claims yang dipakai.
+var permissionRepo repository.PermissionRepository
+
+func SetPermissionRepository(repo repository.PermissionRepository) {
+	permissionRepo = repo
+}
+
+// sessionRepo - Di-set saat startup (lihat main.go).
+// Jika nil, access token tidak dicek terhadap sesi login (misal di unit test).
+var sessionRepo repository.SessionRepository
+
+func SetSessionRepository(repo repository.SessionRepository) {
+	sessionRepo = repo
+}
+
+// apiTokenRepo - Di-set saat startup (lihat main.go).
+// Jika nil, API token personal tidak diterima.
+var apiTokenRepo repository.APITokenRepository
+
+func SetAPITokenRepository(repo repository.APITokenRepository) {
+	apiTokenRepo = repo
+}
+
+// auditLogRepo - Di-set saat startup (lihat main.go).
+// Jika nil, request impersonation tidak dicatat (misal di unit test).
+var auditLogRepo repository.AuditLogRepository
+
+func SetAuditLogRepository(repo repository.AuditLogRepository) {
+	auditLogRepo = repo
+}
+
+// HeaderImpersonatedBy - Ditambahkan ke setiap response selama impersonation
+const HeaderImpersonatedBy = "X-Impersonated-By"
+
+// impersonationWriteAllowlist - Request non-GET yang tetap boleh selama
+// impersonation read-only (admin harus bisa mengakhiri impersonation)
+var impersonationWriteAllowlist = map[string]bool{
+	"/api/v1/auth/logout": true,
+}
+
 func AuthRequired(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
@@ -15,20 +69,220 @@ func AuthRequired(c *fiber.Ctx) error {
 
 	token := strings.Replace(authHeader, "Bearer ", "", 1)
 
+	// ⭐ API TOKEN PERSONAL (script/integrasi)
+	if utils.IsAPIToken(token) {
+		return authenticateAPIToken(c, token)
+	}
+
 	claims, err := utils.ValidateToken(token)
 	if err != nil {
 		return c.Status(401).JSON(fiber.Map{"error": "invalid token"})
 	}
 
+	// ⭐ CEK TOKEN SUDAH DI-REVOKE (logout, deactivate, ganti role/password)
+	if tokenRevocationRepo != nil {
+		denied, err := tokenRevocationRepo.IsTokenDenied(claims.ID)
+		if err != nil {
+			return c.Status(503).JSON(fiber.Map{"error": "failed to validate token"})
+		}
+		if denied {
+			return c.Status(401).JSON(fiber.Map{"error": "token revoked"})
+		}
+
+		validAfter, err := tokenRevocationRepo.GetTokensValidAfter(claims.UserID)
+		if err != nil {
+			return c.Status(503).JSON(fiber.Map{"error": "failed to validate token"})
+		}
+		if validAfter != nil && (claims.IssuedAt == nil || claims.IssuedAt.Time.Before(*validAfter)) {
+			return c.Status(401).JSON(fiber.Map{"error": "token revoked"})
+		}
+
+		// token impersonation ikut tidak berlaku jika akun admin-nya dinonaktifkan / logout semua
+		if claims.Impersonator != nil {
+			validAfter, err := tokenRevocationRepo.GetTokensValidAfter(claims.Impersonator.UserID)
+			if err != nil {
+				return c.Status(503).JSON(fiber.Map{"error": "failed to validate token"})
+			}
+			if validAfter != nil && (claims.IssuedAt == nil || claims.IssuedAt.Time.Before(*validAfter)) {
+				return c.Status(401).JSON(fiber.Map{"error": "token revoked"})
+			}
+		}
+	}
+
+	// ⭐ CEK SESI LOGIN MASIH AKTIF (sesi bisa di-revoke dari device lain)
+	// Access token wajib terikat sesi login. Token impersonation tidak punya sid;
+	// masa berlakunya dibatasi TTL dan cutoff akun admin di atas.
+	if sessionRepo != nil && claims.Impersonator == nil {
+		if claims.SessionID == "" {
+			return c.Status(401).JSON(fiber.Map{"error": "session required"})
+		}
+		active, err := sessionRepo.Touch(claims.SessionID)
+		if err != nil {
+			return c.Status(503).JSON(fiber.Map{"error": "failed to validate session"})
+		}
+		if !active {
+			return c.Status(401).JSON(fiber.Map{"error": "session revoked"})
+		}
+	}
+
+	// ⭐ RESOLVE PERMISSIONS TERBARU DARI DATABASE
+	// Permission di claims hanya snapshot saat login; permission yang dicabut
+	// dari role harus langsung berlaku. Token lama tanpa role_id tetap memakai claims.
+	if permissionRepo != nil && claims.RoleID != "" {
+		permissions, err := permissionRepo.GetPermissionsByRoleID(claims.RoleID)
+		if err != nil {
+			return c.Status(503).JSON(fiber.Map{"error": "failed to resolve permissions"})
+		}
+		claims.Permissions = permissions
+	}
+
 	// ⭐ SET USER CLAIMS
 	c.Locals("user", claims)
 	
 	// ⭐ SET PERMISSIONS - INI YANG KURANG!
 	c.Locals("permissions", claims.Permissions)
+
+	// ⭐ IMPERSONATION: tandai response, batasi aksi, catat ke audit log
+	if claims.Impersonator != nil {
+		return handleImpersonation(c, claims)
+	}
 	
 	return c.Next()
 }
 
+// handleImpersonation - Secara default token impersonation hanya boleh membaca
+// (IMPERSONATION_ALLOW_WRITE=false). Semua request, termasuk yang ditolak, dicatat.
+func handleImpersonation(c *fiber.Ctx, claims *model.JWTClaims) error {
+	c.Set(HeaderImpersonatedBy, claims.Impersonator.Username)
+
+	var err error
+	if !config.AppConfig.ImpersonationAllowWrite && !isSafeMethod(c.Method()) && !impersonationWriteAllowlist[c.Path()] {
+		err = c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error",
+			"error":  "Akses ditolak: aksi yang mengubah data tidak diizinkan selama impersonation",
+		})
+	} else {
+		err = c.Next()
+	}
+
+	if auditLogRepo != nil {
+		status := c.Response().StatusCode()
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			status = fiberErr.Code
+		}
+
+		entry := &model.AuditLog{
+			Action:     model.AuditActionImpersonationRequest,
+			ActorID:    claims.Impersonator.UserID,
+			SubjectID:  claims.UserID,
+			Method:     c.Method(),
+			Path:       c.OriginalURL(),
+			StatusCode: status,
+			IPAddress:  c.IP(),
+		}
+		if logErr := auditLogRepo.Create(entry); logErr != nil {
+			log.Printf("failed to write impersonation audit log: %v", logErr)
+		}
+	}
+
+	return err
+}
+
+func isSafeMethod(method string) bool {
+	return method == fiber.MethodGet || method == fiber.MethodHead || method == fiber.MethodOptions
+}
+
+// authenticateAPIToken - Validasi API token personal. Permission efektif adalah
+// irisan scopes token dengan permission role pemilik saat ini, sehingga scope
+// yang sudah dicabut dari role tidak ikut berlaku.
+func authenticateAPIToken(c *fiber.Ctx, token string) error {
+	if apiTokenRepo == nil {
+		return c.Status(401).JSON(fiber.Map{"error": "invalid token"})
+	}
+
+	apiToken, owner, err := apiTokenRepo.FindByHash(utils.HashAPIToken(token))
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": "invalid token"})
+	}
+	if apiToken.RevokedAt != nil {
+		return c.Status(401).JSON(fiber.Map{"error": "token revoked"})
+	}
+	if apiToken.ExpiresAt != nil && time.Now().After(*apiToken.ExpiresAt) {
+		return c.Status(401).JSON(fiber.Map{"error": "token expired"})
+	}
+	if owner.DeletedAt != nil || !owner.IsActive {
+		return c.Status(401).JSON(fiber.Map{"error": "token revoked"})
+	}
+
+	permissions := apiToken.Scopes
+	if permissionRepo != nil {
+		rolePermissions, err := permissionRepo.GetPermissionsByRoleID(owner.RoleID)
+		if err != nil {
+			return c.Status(503).JSON(fiber.Map{"error": "failed to resolve permissions"})
+		}
+		permissions = intersect(apiToken.Scopes, rolePermissions)
+	}
+
+	if err := apiTokenRepo.Touch(apiToken.ID); err != nil {
+		return c.Status(503).JSON(fiber.Map{"error": "failed to validate token"})
+	}
+
+	claims := &model.JWTClaims{
+		UserID:      owner.ID,
+		Username:    owner.Username,
+		Role:        owner.Role,
+		RoleID:      owner.RoleID,
+		Permissions: permissions,
+		APITokenID:  apiToken.ID,
+	}
+
+	c.Locals("user", claims)
+	c.Locals("permissions", claims.Permissions)
+
+	return c.Next()
+}
+
+func intersect(scopes []string, allowed []string) []string {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, perm := range allowed {
+		allowedSet[perm] = true
+	}
+
+	result := []string{}
+	for _, scope := range scopes {
+		if allowedSet[scope] {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+// RejectAPIToken - Endpoint akun (logout, password, MFA, sesi, kelola token)
+// hanya boleh diakses dengan login interaktif, bukan API token.
+// Dipasang setelah AuthRequired.
+func RejectAPIToken(c *fiber.Ctx) error {
+	if claims, ok := c.Locals("user").(*model.JWTClaims); ok && claims.APITokenID != "" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error",
+			"error":  "Akses ditolak: endpoint ini tidak bisa diakses dengan API token",
+		})
+	}
+	return c.Next()
+}
+
+// RejectImpersonation - Endpoint kredensial akun (password, MFA, sesi, API token)
+// milik user yang di-impersonate tidak boleh diubah atau dibaca admin, apa pun nilai
+// IMPERSONATION_ALLOW_WRITE. Dipasang setelah AuthRequired.
+func RejectImpersonation(c *fiber.Ctx) error {
+	if claims, ok := c.Locals("user").(*model.JWTClaims); ok && claims.Impersonator != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status": "error",
+			"error":  "Akses ditolak: endpoint ini tidak bisa diakses selama impersonation",
+		})
+	}
+	return c.Next()
+}
+
 func RequirePermission(requiredPermission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		permissions, ok := c.Locals("permissions").([]string)
@@ -50,4 +304,4 @@ func RequirePermission(requiredPermission string) fiber.Handler {
 			"error":  "Akses ditolak: permission tidak mencukupi",
 		})
 	}
-}
\ No newline at end of file
+}
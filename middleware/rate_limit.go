@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+	"UASBE/app/model"
+	"UASBE/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// AuthRateLimit - Batasi jumlah request ke group /auth per IP (per menit)
+// Limiter fiber otomatis mengisi header Retry-After saat limit tercapai
+func AuthRateLimit() fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        config.AppConfig.AuthRateLimit,
+		Expiration: time.Minute,
+		Next: func(c *fiber.Ctx) bool {
+			return config.AppConfig.AuthRateLimit <= 0
+		},
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return "auth:" + c.IP()
+		},
+		LimitReached: tooManyRequests,
+	})
+}
+
+// LoginIPFailureLimit - Batasi login GAGAL per IP
+// Login yang berhasil tidak dihitung (SkipSuccessfulRequests)
+func LoginIPFailureLimit() fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:                    config.AppConfig.LoginIPFailureLimit,
+		Expiration:             config.AppConfig.LoginFailureWindow,
+		SkipSuccessfulRequests: true,
+		Next: func(c *fiber.Ctx) bool {
+			return config.AppConfig.LoginIPFailureLimit <= 0
+		},
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return "login-ip:" + c.IP()
+		},
+		LimitReached: tooManyRequests,
+	})
+}
+
+// LoginUsernameFailureLimit - Batasi login GAGAL per username (termasuk username yang tidak ada)
+// Melengkapi lockout di tabel users yang hanya berlaku untuk username terdaftar
+func LoginUsernameFailureLimit() fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:                    config.AppConfig.LoginUsernameFailureLimit,
+		Expiration:             config.AppConfig.LoginFailureWindow,
+		SkipSuccessfulRequests: true,
+		Next: func(c *fiber.Ctx) bool {
+			return config.AppConfig.LoginUsernameFailureLimit <= 0
+		},
+		KeyGenerator: func(c *fiber.Ctx) string {
+			req := new(model.LoginRequest)
+			_ = c.BodyParser(req)
+			return "login-user:" + strings.ToLower(strings.TrimSpace(req.Username))
+		},
+		LimitReached: tooManyRequests,
+	})
+}
+
+func tooManyRequests(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusTooManyRequests).JSON(model.APIResponse{
+		Status: "error",
+		Error:  "too many requests, please try again later",
+		Code:   model.ErrCodeTooManyRequests,
+	})
+}
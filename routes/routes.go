@@ -11,16 +11,47 @@ import (
 // ==================== AUTH ROUTES ======================
 //
 
-func AuthRoutes(app *fiber.App, authService *service.AuthService) {
-	auth := app.Group("/api/v1/auth")
+func AuthRoutes(app *fiber.App, authService *service.AuthService, apiTokenService *service.APITokenService) {
+	// Public key untuk verifikasi token oleh service lain
+	app.Get("/.well-known/jwks.json", authService.JWKS)
 
-	auth.Post("/login", authService.Login)
+	auth := app.Group("/api/v1/auth", middleware.AuthRateLimit())
+
+	auth.Post("/login",
+		middleware.LoginIPFailureLimit(),
+		middleware.LoginUsernameFailureLimit(),
+		authService.Login,
+	)
 	auth.Post("/refresh", authService.Refresh)
+	auth.Post("/password/reset", authService.ResetPassword)
+
+	// MFA langkah kedua login (pakai challenge token, bukan access token)
+	auth.Post("/mfa/verify", middleware.LoginIPFailureLimit(), authService.VerifyMFA)
+	auth.Post("/mfa/challenge/setup", authService.SetupMFAChallenge)
+
+	// Login lewat IdP kampus (OIDC authorization code flow)
+	auth.Get("/oidc/login", authService.OIDCLogin)
+	auth.Get("/oidc/callback", middleware.LoginIPFailureLimit(), authService.OIDCCallback)
 
 	// Protected routes
 	protected := auth.Group("/", middleware.AuthRequired)
 	protected.Get("/profile", authService.Profile)
-	protected.Post("/logout", authService.Logout)
+
+	// Endpoint akun hanya untuk login interaktif, bukan API token. Selain logout
+	// (mengakhiri impersonation), juga tidak untuk token impersonation.
+	protected.Post("/logout", middleware.RejectAPIToken, authService.Logout)
+	protected.Post("/logout/all", middleware.RejectAPIToken, middleware.RejectImpersonation, authService.LogoutAll)
+	protected.Post("/password", middleware.RejectAPIToken, middleware.RejectImpersonation, authService.ChangePassword)
+	protected.Post("/mfa/setup", middleware.RejectAPIToken, middleware.RejectImpersonation, authService.SetupMFA)
+	protected.Post("/mfa/activate", middleware.RejectAPIToken, middleware.RejectImpersonation, authService.ActivateMFA)
+	protected.Post("/mfa/disable", middleware.RejectAPIToken, middleware.RejectImpersonation, authService.DisableMFA)
+	protected.Get("/sessions", middleware.RejectAPIToken, middleware.RejectImpersonation, authService.GetSessions)
+	protected.Delete("/sessions/:id", middleware.RejectAPIToken, middleware.RejectImpersonation, authService.RevokeSession)
+
+	// API token personal untuk script/integrasi
+	protected.Get("/tokens", middleware.RejectAPIToken, middleware.RejectImpersonation, apiTokenService.GetTokens)
+	protected.Post("/tokens", middleware.RejectAPIToken, middleware.RejectImpersonation, apiTokenService.CreateToken)
+	protected.Delete("/tokens/:id", middleware.RejectAPIToken, middleware.RejectImpersonation, apiTokenService.RevokeToken)
 }
 
 //
@@ -40,6 +71,37 @@ func UserRoutes(app *fiber.App, userService *service.UserService) {
 	users.Put("/:id", userService.UpdateUser)     // PUT /api/v1/users/:id
 	users.Delete("/:id", userService.DeleteUser)  // DELETE /api/v1/users/:id
 	users.Put("/:id/role", userService.AssignRole) // PUT /api/v1/users/:id/role
+	users.Put("/:id/unlock", userService.UnlockUser) // PUT /api/v1/users/:id/unlock
+	users.Post("/:id/password-reset", userService.IssuePasswordReset) // POST /api/v1/users/:id/password-reset
+	users.Get("/:id/sessions", userService.GetUserSessions) // GET /api/v1/users/:id/sessions
+	users.Delete("/:id/sessions/:sessionId", userService.RevokeUserSession) // DELETE /api/v1/users/:id/sessions/:sessionId
+	users.Post("/:id/impersonate", middleware.RejectAPIToken, userService.Impersonate) // POST /api/v1/users/:id/impersonate
+}
+
+//
+// ==================== ROLE & PERMISSION ROUTES (ADMIN ONLY) ======================
+//
+
+func RoleRoutes(app *fiber.App, roleService *service.RoleService) {
+	roles := app.Group("/api/v1/roles")
+
+	// Semua endpoint role butuh auth + permission "user:manage"
+	roles.Use(middleware.AuthRequired)
+	roles.Use(middleware.RequirePermission("user:manage"))
+
+	roles.Get("/", roleService.GetRoles)                                       // GET /api/v1/roles
+	roles.Get("/:id", roleService.GetRoleByID)                                 // GET /api/v1/roles/:id
+	roles.Post("/", roleService.CreateRole)                                    // POST /api/v1/roles
+	roles.Put("/:id", roleService.UpdateRole)                                  // PUT /api/v1/roles/:id
+	roles.Delete("/:id", roleService.DeleteRole)                               // DELETE /api/v1/roles/:id
+	roles.Post("/:id/permissions", roleService.AttachPermission)               // POST /api/v1/roles/:id/permissions
+	roles.Delete("/:id/permissions/:permission", roleService.DetachPermission) // DELETE /api/v1/roles/:id/permissions/:permission
+
+	permissions := app.Group("/api/v1/permissions")
+	permissions.Use(middleware.AuthRequired)
+	permissions.Use(middleware.RequirePermission("user:manage"))
+
+	permissions.Get("/", roleService.GetPermissions) // GET /api/v1/permissions
 }
 
 //
@@ -100,6 +162,24 @@ func LecturerRoutes(app *fiber.App, lecturerService *service.LecturerService) {
 		middleware.RequirePermission("achievement:read"),
 		lecturerService.GetLecturerAdvisees,
 	)
+
+	// GET /api/v1/lecturers/:id/delegations - Delegasi verifikasi dosen (dosen sendiri / admin)
+	lecturers.Get("/:id/delegations",
+		middleware.RequirePermission("achievement:read"),
+		lecturerService.GetDelegations,
+	)
+
+	// POST /api/v1/lecturers/:id/delegations - Delegasikan verifikasi bimbingan ke dosen lain
+	lecturers.Post("/:id/delegations",
+		middleware.RequirePermission("achievement:read"),
+		lecturerService.CreateDelegation,
+	)
+
+	// DELETE /api/v1/lecturers/:id/delegations/:delegationId - Cabut delegasi
+	lecturers.Delete("/:id/delegations/:delegationId",
+		middleware.RequirePermission("achievement:read"),
+		lecturerService.RevokeDelegation,
+	)
 }
 
 //
@@ -118,6 +198,13 @@ func AchievementRoutes(app *fiber.App, achievementService *service.AchievementSe
 		achievementService.GetAchievements,
 	)
 
+	// GET /achievements/deleted - Prestasi terhapus yang belum di-purge (Admin only)
+	// Didaftarkan sebelum /:id supaya "deleted" tidak dianggap sebagai :id
+	achievements.Get("/deleted",
+		middleware.RequirePermission("user:manage"),
+		achievementService.GetDeletedAchievements,
+	)
+
 	// GET /achievements/:id - Detail achievement
 	achievements.Get("/:id",
 		middleware.RequirePermission("achievement:read"),
@@ -130,7 +217,7 @@ func AchievementRoutes(app *fiber.App, achievementService *service.AchievementSe
 		achievementService.CreateAchievement,
 	)
 
-	// PUT /achievements/:id - Update achievement (Mahasiswa only, status = draft)
+	// PUT /achievements/:id - Update achievement (Mahasiswa only, status = draft/rejected/revision_requested)
 	achievements.Put("/:id",
 		middleware.RequirePermission("achievement:update"),
 		achievementService.UpdateAchievement,
@@ -142,12 +229,35 @@ func AchievementRoutes(app *fiber.App, achievementService *service.AchievementSe
 		achievementService.DeleteAchievement,
 	)
 
+	// POST /achievements/:id/restore - Kembalikan prestasi terhapus ke draft (Admin only)
+	achievements.Post("/:id/restore",
+		middleware.RequirePermission("user:manage"),
+		achievementService.RestoreAchievement,
+	)
+
+	// POST /achievements/batch/verify & /batch/reject - Banyak prestasi sekaligus (Dosen Wali only)
+	// Didaftarkan sebelum /:id/verify supaya "batch" tidak dianggap sebagai :id
+	achievements.Post("/batch/verify",
+		middleware.RequirePermission("achievement:verify"),
+		achievementService.BatchVerify,
+	)
+	achievements.Post("/batch/reject",
+		middleware.RequirePermission("achievement:verify"),
+		achievementService.BatchReject,
+	)
+
 	// POST /achievements/:id/submit - Submit for verification (Mahasiswa only)
 	achievements.Post("/:id/submit",
 		middleware.RequirePermission("achievement:update"),
 		achievementService.SubmitForVerification,
 	)
 
+	// POST /achievements/:id/withdraw - Tarik kembali pengajuan ke draft (Mahasiswa only)
+	achievements.Post("/:id/withdraw",
+		middleware.RequirePermission("achievement:update"),
+		achievementService.WithdrawSubmission,
+	)
+
 	// POST /achievements/:id/verify - Verify achievement (Dosen Wali only)
 	achievements.Post("/:id/verify",
 		middleware.RequirePermission("achievement:verify"),
@@ -160,6 +270,12 @@ func AchievementRoutes(app *fiber.App, achievementService *service.AchievementSe
 		achievementService.RejectAchievement,
 	)
 
+	// POST /achievements/:id/request-revision - Kembalikan untuk diperbaiki (Dosen Wali only)
+	achievements.Post("/:id/request-revision",
+		middleware.RequirePermission("achievement:verify"),
+		achievementService.RequestRevision,
+	)
+
 	// POST /achievements/:id/attachments - Upload attachment (Mahasiswa only)
 	achievements.Post("/:id/attachments",
 		middleware.RequirePermission("achievement:update"),
@@ -171,11 +287,29 @@ func AchievementRoutes(app *fiber.App, achievementService *service.AchievementSe
 		middleware.RequirePermission("achievement:read"),
 		achievementService.GetAchievementHistory,
 	)
+
+	// GET /achievements/:id/comments - Thread komentar
+	achievements.Get("/:id/comments",
+		middleware.RequirePermission("achievement:read"),
+		achievementService.GetComments,
+	)
+
+	// POST /achievements/:id/comments - Tambah komentar / balasan (siapa pun yang bisa melihat prestasi)
+	achievements.Post("/:id/comments",
+		middleware.RequirePermission("achievement:read"),
+		achievementService.CreateComment,
+	)
+
+	// GET /achievements/:id/changes - Perubahan sejak pengajuan sebelumnya (revisi)
+	achievements.Get("/:id/changes",
+		middleware.RequirePermission("achievement:read"),
+		achievementService.GetAchievementChanges,
+	)
 }
 
 // ==================== REPORT ROUTES ======================
 
-func ReportRoutes(app *fiber.App, reportService *service.ReportService) {
+func ReportRoutes(app *fiber.App, reportService *service.ReportService, slaService *service.VerificationSLAService) {
 	reports := app.Group("/api/v1/reports")
 
 	// Auth required untuk semua endpoint
@@ -198,4 +332,27 @@ func ReportRoutes(app *fiber.App, reportService *service.ReportService) {
 		middleware.RequirePermission("achievement:read"),
 		reportService.GetStudentReport,
 	)
+
+	// GET /api/v1/reports/overdue
+	// Prestasi yang menunggu verifikasi melewati batas SLA (pengingat / eskalasi)
+	// Actor: Dosen Wali (advisee), reviewer departemen (program studi), Admin (all)
+	reports.Get("/overdue",
+		middleware.RequirePermission("achievement:read"),
+		slaService.GetOverdueReport,
+	)
+}
+
+// ==================== NOTIFICATION ROUTES ======================
+
+func NotificationRoutes(app *fiber.App, notificationService *service.NotificationService) {
+	notifications := app.Group("/api/v1/notifications")
+
+	// Auth required untuk semua endpoint (notifikasi milik user sendiri)
+	notifications.Use(middleware.AuthRequired)
+
+	// GET /api/v1/notifications - Notifikasi user (?unread=true)
+	notifications.Get("/", notificationService.GetNotifications)
+
+	// POST /api/v1/notifications/:id/read - Tandai sudah dibaca
+	notifications.Post("/:id/read", notificationService.MarkNotificationRead)
 }
\ No newline at end of file
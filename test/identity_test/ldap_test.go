@@ -0,0 +1,219 @@
+package identity_test
+
+import (
+	"UASBE/app/identity"
+	"UASBE/app/model"
+	"bufio"
+	"context"
+	"encoding/asn1"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubLDAP - Server LDAP lokal dengan satu akun service dan satu user
+type stubLDAP struct {
+	listener  net.Listener
+	passwords map[string]string              // DN → password
+	entries   map[string]map[string][]string // uid → atribut (termasuk "dn")
+}
+
+func newStubLDAP(t *testing.T) *stubLDAP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	stub := &stubLDAP{
+		listener: listener,
+		passwords: map[string]string{
+			"cn=svc,dc=kampus,dc=ac,dc=id":             "svc-pass",
+			"uid=andi,ou=people,dc=kampus,dc=ac,dc=id": "rahasia",
+		},
+		entries: map[string]map[string][]string{
+			"andi": {
+				"dn":       {"uid=andi,ou=people,dc=kampus,dc=ac,dc=id"},
+				"mail":     {"andi@kampus.ac.id"},
+				"cn":       {"Andi Wijaya"},
+				"memberOf": {"cn=dosen,ou=groups,dc=kampus,dc=ac,dc=id"},
+				"nip":      {"198701012010"},
+			},
+		},
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go stub.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return stub
+}
+
+func (s *stubLDAP) provider() *identity.LDAPProvider {
+	return identity.NewLDAPProvider(identity.LDAPConfig{
+		URL:                 "ldap://" + s.listener.Addr().String(),
+		BindDN:              "cn=svc,dc=kampus,dc=ac,dc=id",
+		BindPassword:        "svc-pass",
+		BaseDN:              "ou=people,dc=kampus,dc=ac,dc=id",
+		UserAttribute:       "uid",
+		GroupAttribute:      "memberOf",
+		StudentIDAttribute:  "nim",
+		LecturerIDAttribute: "nip",
+	})
+}
+
+func (s *stubLDAP) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		raw, err := readElement(reader)
+		if err != nil {
+			return
+		}
+
+		var message asn1.RawValue
+		asn1.Unmarshal(raw, &message)
+
+		var id int
+		rest, _ := asn1.Unmarshal(message.Bytes, &id)
+		var op asn1.RawValue
+		asn1.Unmarshal(rest, &op)
+
+		switch op.Tag {
+		case 0: // BindRequest
+			var version int
+			var dn []byte
+			var password asn1.RawValue
+			rest, _ := asn1.Unmarshal(op.Bytes, &version)
+			rest, _ = asn1.Unmarshal(rest, &dn)
+			asn1.Unmarshal(rest, &password)
+
+			code := 49 // invalidCredentials
+			if expected, ok := s.passwords[string(dn)]; ok && expected == string(password.Bytes) {
+				code = 0
+			}
+			conn.Write(ldapMessage(id, 1, result(code)))
+
+		case 2: // UnbindRequest
+			return
+
+		case 3: // SearchRequest: hanya filter equality (uid=...)
+			var base []byte
+			var scope, deref asn1.Enumerated
+			var sizeLimit, timeLimit int
+			var typesOnly bool
+			var filter asn1.RawValue
+			rest, _ := asn1.Unmarshal(op.Bytes, &base)
+			rest, _ = asn1.Unmarshal(rest, &scope)
+			rest, _ = asn1.Unmarshal(rest, &deref)
+			rest, _ = asn1.Unmarshal(rest, &sizeLimit)
+			rest, _ = asn1.Unmarshal(rest, &timeLimit)
+			rest, _ = asn1.Unmarshal(rest, &typesOnly)
+			asn1.Unmarshal(rest, &filter)
+
+			var attr, value []byte
+			rest, _ = asn1.Unmarshal(filter.Bytes, &attr)
+			asn1.Unmarshal(rest, &value)
+
+			if entry, ok := s.entries[string(value)]; ok && string(attr) == "uid" {
+				conn.Write(ldapMessage(id, 4, searchEntry(entry)))
+			}
+			conn.Write(ldapMessage(id, 5, result(0)))
+		}
+	}
+}
+
+func readElement(r *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	length := int(header[1])
+	if header[1]&0x80 != 0 {
+		lenBytes := make([]byte, header[1]&0x7f)
+		if _, err := io.ReadFull(r, lenBytes); err != nil {
+			return nil, err
+		}
+		header = append(header, lenBytes...)
+		length = 0
+		for _, b := range lenBytes {
+			length = length<<8 | int(b)
+		}
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+	return append(header, content...), nil
+}
+
+func mustMarshal(v interface{}) []byte {
+	b, _ := asn1.Marshal(v)
+	return b
+}
+
+func ldapMessage(id int, opTag int, opContent []byte) []byte {
+	op := mustMarshal(asn1.RawValue{Class: asn1.ClassApplication, Tag: opTag, IsCompound: true, Bytes: opContent})
+	return mustMarshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true,
+		Bytes: append(mustMarshal(id), op...)})
+}
+
+// result - resultCode, matchedDN, diagnosticMessage
+func result(code int) []byte {
+	out := mustMarshal(asn1.Enumerated(code))
+	out = append(out, mustMarshal([]byte{})...)
+	return append(out, mustMarshal([]byte{})...)
+}
+
+func searchEntry(entry map[string][]string) []byte {
+	var attrs []byte
+	for name, values := range entry {
+		if name == "dn" {
+			continue
+		}
+		var vals []byte
+		for _, v := range values {
+			vals = append(vals, mustMarshal([]byte(v))...)
+		}
+		attr := append(mustMarshal([]byte(name)),
+			mustMarshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: vals})...)
+		attrs = append(attrs, mustMarshal(asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Bytes: attr})...)
+	}
+
+	out := mustMarshal([]byte(entry["dn"][0]))
+	return append(out, mustMarshal(asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Bytes: attrs})...)
+}
+
+func TestLDAP_SearchThenBind(t *testing.T) {
+	stub := newStubLDAP(t)
+	provider := stub.provider()
+	ctx := context.Background()
+
+	ext, err := provider.Authenticate(ctx, "andi", "rahasia")
+	require.NoError(t, err)
+	assert.Equal(t, model.IdentityProviderLDAP, ext.Provider)
+	assert.Equal(t, "uid=andi,ou=people,dc=kampus,dc=ac,dc=id", ext.Subject)
+	assert.Equal(t, "andi@kampus.ac.id", ext.Email)
+	assert.Equal(t, "Andi Wijaya", ext.FullName)
+	assert.Equal(t, []string{"cn=dosen,ou=groups,dc=kampus,dc=ac,dc=id"}, ext.Groups)
+	assert.Equal(t, "198701012010", ext.LecturerID)
+
+	_, err = provider.Authenticate(ctx, "andi", "salah")
+	assert.ErrorIs(t, err, identity.ErrInvalidCredentials)
+
+	_, err = provider.Authenticate(ctx, "tidak-ada", "rahasia")
+	assert.ErrorIs(t, err, identity.ErrUserNotFound)
+
+	// Password kosong = unauthenticated bind, tidak boleh dianggap login berhasil
+	_, err = provider.Authenticate(ctx, "andi", "")
+	assert.ErrorIs(t, err, identity.ErrInvalidCredentials)
+}
@@ -0,0 +1,150 @@
+package identity_test
+
+import (
+	"UASBE/app/identity"
+	"UASBE/app/model"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubIdP - IdP OIDC lokal: discovery, JWKS dan token endpoint.
+// Code "good-code" menghasilkan ID token untuk claims + nonce yang diberikan.
+type stubIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+	nonce  string
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &stubIdP{key: key}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(model.JWKSet{Keys: []model.JWK{{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: "stub-key",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, _ := r.BasicAuth()
+		if clientID != "uasbe" || secret != "client-secret" || r.FormValue("code") != "good-code" {
+			w.WriteHeader(400)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss":   idp.server.URL,
+			"aud":   "uasbe",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": idp.nonce,
+		}
+		for k, v := range idp.claims {
+			claims[k] = v
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "stub-key"
+		signed, _ := token.SignedString(key)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *stubIdP) provider() *identity.OIDCProvider {
+	return identity.NewOIDCProvider(identity.OIDCConfig{
+		Issuer:          idp.server.URL,
+		ClientID:        "uasbe",
+		ClientSecret:    "client-secret",
+		RedirectURL:     "http://localhost:3000/api/v1/auth/oidc/callback",
+		GroupsClaim:     "groups",
+		StudentIDClaim:  "nim",
+		LecturerIDClaim: "nip",
+	}, idp.server.Client())
+}
+
+func TestOIDC_AuthorizationCodeFlow(t *testing.T) {
+	idp := newStubIdP(t)
+	provider := idp.provider()
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1")
+	require.NoError(t, err)
+
+	u, _ := url.Parse(authURL)
+	assert.Equal(t, "/authorize", u.Path)
+	assert.Equal(t, "code", u.Query().Get("response_type"))
+	assert.Equal(t, "state-1", u.Query().Get("state"))
+	assert.Equal(t, "nonce-1", u.Query().Get("nonce"))
+
+	// IdP menyertakan nonce dari authorization request di ID token
+	idp.nonce = u.Query().Get("nonce")
+	idp.claims = jwt.MapClaims{
+		"sub":                "kc-1234",
+		"preferred_username": "budi",
+		"email":              "budi@kampus.ac.id",
+		"name":               "Budi Santoso",
+		"groups":             []string{"mahasiswa"},
+		"nim":                "2201001",
+	}
+
+	ext, err := provider.Exchange(ctx, "good-code", "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, model.IdentityProviderOIDC, ext.Provider)
+	assert.Equal(t, "kc-1234", ext.Subject)
+	assert.Equal(t, "budi", ext.Username)
+	assert.Equal(t, "budi@kampus.ac.id", ext.Email)
+	assert.Equal(t, []string{"mahasiswa"}, ext.Groups)
+	assert.Equal(t, "2201001", ext.StudentID)
+}
+
+func TestOIDC_RejectsNonceMismatchAndBadCode(t *testing.T) {
+	idp := newStubIdP(t)
+	provider := idp.provider()
+	ctx := context.Background()
+
+	idp.nonce = "nonce-from-another-login"
+	idp.claims = jwt.MapClaims{"sub": "kc-1234"}
+
+	// ID token hasil login lain (replay) ditolak
+	_, err := provider.Exchange(ctx, "good-code", "nonce-1")
+	assert.ErrorIs(t, err, identity.ErrInvalidToken)
+
+	_, err = provider.Exchange(ctx, "bad-code", "nonce-1")
+	assert.ErrorIs(t, err, identity.ErrInvalidCredentials)
+}
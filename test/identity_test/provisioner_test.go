@@ -0,0 +1,122 @@
+package identity_test
+
+import (
+	"UASBE/app/identity"
+	"UASBE/app/model"
+	"UASBE/app/repository"
+	"UASBE/test/mocks"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type provisionerMocks struct {
+	users      *mocks.MockUserRepository
+	roles      *mocks.MockRoleRepository
+	students   *mocks.MockStudentRepository
+	lecturers  *mocks.MockLecturerRepository
+	identities *mocks.MockIdentityRepository
+}
+
+func newProvisioner(defaultRole string) (*identity.Provisioner, provisionerMocks) {
+	m := provisionerMocks{
+		users:      new(mocks.MockUserRepository),
+		roles:      new(mocks.MockRoleRepository),
+		students:   new(mocks.MockStudentRepository),
+		lecturers:  new(mocks.MockLecturerRepository),
+		identities: new(mocks.MockIdentityRepository),
+	}
+	p := identity.NewProvisioner(m.users, m.roles, m.students, m.lecturers, m.identities,
+		repository.NewMemoryTokenRevocationRepository(),
+		identity.ParseGroupRoles("mahasiswa=Mahasiswa;cn=dosen,ou=groups,dc=kampus,dc=ac,dc=id=Dosen Wali"),
+		defaultRole)
+	return p, m
+}
+
+func TestProvisioner_JITCreatesUserWithMappedRole(t *testing.T) {
+	p, m := newProvisioner("")
+
+	ext := &model.ExternalIdentity{
+		Provider: model.IdentityProviderOIDC,
+		Subject:  "kc-1",
+		Username: "budi",
+		Email:    "budi@kampus.ac.id",
+		FullName: "Budi Santoso",
+		Groups:   []string{"staff", "mahasiswa"},
+	}
+
+	m.identities.On("FindByProviderSubject", "oidc", "kc-1").Return(nil, sql.ErrNoRows)
+	m.roles.On("GetRoleByName", "Mahasiswa").Return(&model.Role{ID: "role-mhs", Name: "Mahasiswa"}, nil)
+	m.users.On("FindByUsername", "budi").Return(nil, sql.ErrNoRows)
+	m.users.On("FindByEmail", "budi@kampus.ac.id").Return(nil, sql.ErrNoRows)
+	m.users.On("Create", mock.MatchedBy(func(u *model.User) bool {
+		u.ID = "user-new"
+		return u.RoleID == "role-mhs" && u.PasswordHash == "" && u.IsActive
+	})).Return(nil)
+	m.identities.On("Create", mock.MatchedBy(func(i *model.UserIdentity) bool {
+		i.ID = "link-1"
+		return i.UserID == "user-new" && i.Provider == "oidc" && i.Subject == "kc-1"
+	})).Return(nil)
+	m.identities.On("TouchLastLogin", "link-1").Return(nil)
+
+	user, err := p.Resolve(ext)
+	require.NoError(t, err)
+	assert.Equal(t, "user-new", user.ID)
+	assert.Equal(t, "Mahasiswa", user.Role)
+}
+
+func TestProvisioner_LinksExistingLecturerByNIPAndSyncsRole(t *testing.T) {
+	p, m := newProvisioner("")
+
+	ext := &model.ExternalIdentity{
+		Provider:   model.IdentityProviderLDAP,
+		Subject:    "uid=andi,ou=people,dc=kampus,dc=ac,dc=id",
+		Username:   "andi",
+		Groups:     []string{"cn=dosen,ou=groups,dc=kampus,dc=ac,dc=id"},
+		LecturerID: "198701012010",
+	}
+	existing := &model.User{ID: "user-andi", Username: "andi.w", RoleID: "role-old"}
+
+	m.identities.On("FindByProviderSubject", "ldap", ext.Subject).Return(nil, sql.ErrNoRows)
+	m.lecturers.On("FindByLecturerID", "198701012010").Return(&model.Lecturer{ID: "user-andi"}, nil)
+	m.users.On("FindByID", "user-andi").Return(existing, nil)
+	m.roles.On("GetRoleByName", "Dosen Wali").Return(&model.Role{ID: "role-dosen", Name: "Dosen Wali"}, nil)
+	m.users.On("UpdateRole", "user-andi", "role-dosen").Return(nil)
+	m.identities.On("Create", mock.MatchedBy(func(i *model.UserIdentity) bool {
+		return i.UserID == "user-andi"
+	})).Return(nil)
+	m.identities.On("TouchLastLogin", mock.Anything).Return(nil)
+
+	user, err := p.Resolve(ext)
+	require.NoError(t, err)
+	assert.Equal(t, "user-andi", user.ID)
+	assert.Equal(t, "role-dosen", user.RoleID)
+	m.users.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestProvisioner_RejectsUnmappedAndConflictingAccounts(t *testing.T) {
+	p, m := newProvisioner("")
+
+	m.identities.On("FindByProviderSubject", "oidc", mock.Anything).Return(nil, sql.ErrNoRows)
+
+	// Tidak ada grup terpetakan dan tidak ada role default
+	_, err := p.Resolve(&model.ExternalIdentity{Provider: "oidc", Subject: "kc-2", Username: "tamu", Email: "tamu@x.id"})
+	assert.ErrorIs(t, err, identity.ErrNoRoleMapped)
+
+	// Username sudah dipakai user lokal yang tidak terhubung: tidak di-link otomatis
+	m.roles.On("GetRoleByName", "Mahasiswa").Return(&model.Role{ID: "role-mhs", Name: "Mahasiswa"}, nil)
+	m.users.On("FindByUsername", "admin").Return(&model.User{ID: "user-admin"}, nil)
+	_, err = p.Resolve(&model.ExternalIdentity{Provider: "oidc", Subject: "kc-3", Username: "admin", Email: "a@x.id", Groups: []string{"mahasiswa"}})
+	assert.ErrorIs(t, err, identity.ErrAccountConflict)
+
+	// Database error saat cek link tidak boleh diperlakukan sebagai "belum terhubung"
+	p2, m2 := newProvisioner("Mahasiswa")
+	m2.identities.On("FindByProviderSubject", "oidc", "kc-4").Return(nil, errors.New("db down"))
+	_, err = p2.Resolve(&model.ExternalIdentity{Provider: "oidc", Subject: "kc-4"})
+	assert.Error(t, err)
+	m2.users.AssertNotCalled(t, "Create", mock.Anything)
+}
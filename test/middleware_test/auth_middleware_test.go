@@ -0,0 +1,278 @@
+package middleware_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/repository"
+	"UASBE/config"
+	"UASBE/middleware"
+	"UASBE/test/mocks"
+	"UASBE/utils"
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupProtectedApp(repo repository.TokenRevocationRepository) *fiber.App {
+	utils.JwtKey = []byte("test_secret")
+	middleware.SetTokenRevocationRepository(repo)
+
+	app := fiber.New()
+	app.Get("/protected", middleware.AuthRequired, func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+	return app
+}
+
+func requestWithToken(app *fiber.App, token string) int {
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, _ := app.Test(req)
+	return resp.StatusCode
+}
+
+func TestAuthRequired_DeniedToken(t *testing.T) {
+	repo := repository.NewMemoryTokenRevocationRepository()
+	app := setupProtectedApp(repo)
+
+	token, _ := utils.GenerateJWT(model.UserResponse{ID: "user-1", Role: "Mahasiswa"})
+	claims, _ := utils.ValidateToken(token)
+
+	assert.Equal(t, 200, requestWithToken(app, token))
+
+	// Logout: jti masuk denylist
+	repo.DenyToken(claims.ID, claims.UserID, claims.ExpiresAt.Time)
+	assert.Equal(t, 401, requestWithToken(app, token))
+}
+
+func TestAuthRequired_TokenIssuedBeforeCutoff(t *testing.T) {
+	repo := repository.NewMemoryTokenRevocationRepository()
+	app := setupProtectedApp(repo)
+
+	token, _ := utils.GenerateJWT(model.UserResponse{ID: "user-1", Role: "Mahasiswa"})
+
+	// Admin menonaktifkan user setelah token terbit
+	repo.SetTokensValidAfter("user-1", time.Now().Add(2*time.Second))
+	assert.Equal(t, 401, requestWithToken(app, token))
+
+	// User lain tidak terpengaruh
+	otherToken, _ := utils.GenerateJWT(model.UserResponse{ID: "user-2", Role: "Mahasiswa"})
+	assert.Equal(t, 200, requestWithToken(app, otherToken))
+}
+
+func TestAuthRequired_RejectsRefreshToken(t *testing.T) {
+	app := setupProtectedApp(nil)
+
+	refresh, err := utils.GenerateRefreshToken("user-1", "refresh-1", time.Now().Add(utils.RefreshTokenTTL))
+	assert.NoError(t, err)
+
+	// Refresh token hanya untuk /auth/refresh, bukan bearer token
+	assert.Equal(t, 401, requestWithToken(app, refresh))
+
+	// Sebaliknya access token tidak bisa dipakai sebagai refresh token
+	access, _ := utils.GenerateJWT(model.UserResponse{ID: "user-1", Role: "Mahasiswa"})
+	_, err = utils.ValidateRefreshToken(access)
+	assert.Error(t, err)
+
+	claims, err := utils.ValidateRefreshToken(refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh-1", claims.ID)
+}
+
+func TestRequirePermission_UsesFreshPermissions(t *testing.T) {
+	utils.JwtKey = []byte("test_secret")
+	middleware.SetTokenRevocationRepository(nil)
+
+	permRepo := new(mocks.MockPermissionRepository)
+	cache := repository.NewCachedPermissionRepository(permRepo, time.Minute)
+	middleware.SetPermissionRepository(cache)
+	defer middleware.SetPermissionRepository(nil)
+
+	app := fiber.New()
+	app.Get("/protected", middleware.AuthRequired, middleware.RequirePermission("achievement:verify"), func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	// Token dibuat saat role masih punya achievement:verify
+	token, _ := utils.GenerateJWT(model.UserResponse{
+		ID:          "user-1",
+		Role:        "Dosen Wali",
+		RoleID:      "role-dosen",
+		Permissions: []string{"achievement:verify"},
+	})
+
+	permRepo.On("GetPermissionsByRoleID", "role-dosen").Return([]string{"achievement:verify"}, nil).Once()
+	assert.Equal(t, 200, requestWithToken(app, token))
+	assert.Equal(t, 200, requestWithToken(app, token)) // dari cache
+
+	// Permission dicabut dari role: token lama langsung kehilangan akses
+	permRepo.On("GetPermissionsByRoleID", "role-dosen").Return([]string{"achievement:read"}, nil).Once()
+	cache.Invalidate("role-dosen")
+	assert.Equal(t, 403, requestWithToken(app, token))
+
+	permRepo.AssertExpectations(t)
+}
+
+func TestAuthRequired_RevokedSession(t *testing.T) {
+	app := setupProtectedApp(nil)
+
+	sessionRepo := new(mocks.MockSessionRepository)
+	middleware.SetSessionRepository(sessionRepo)
+	defer middleware.SetSessionRepository(nil)
+
+	token, _ := utils.GenerateSessionJWT(model.UserResponse{ID: "user-1", Role: "Mahasiswa"}, "session-1")
+
+	sessionRepo.On("Touch", "session-1").Return(true, nil).Once()
+	assert.Equal(t, 200, requestWithToken(app, token))
+
+	// Sesi di-revoke dari device lain: access token-nya ikut ditolak
+	sessionRepo.On("Touch", "session-1").Return(false, nil).Once()
+	assert.Equal(t, 401, requestWithToken(app, token))
+
+	// Token tanpa sid tidak bisa melewati pengecekan sesi
+	legacy, _ := utils.GenerateJWT(model.UserResponse{ID: "user-1", Role: "Mahasiswa"})
+	assert.Equal(t, 401, requestWithToken(app, legacy))
+
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestAuthRequired_APIToken(t *testing.T) {
+	app := setupProtectedApp(nil)
+	app.Get("/scoped", middleware.AuthRequired, middleware.RequirePermission("user:manage"), func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+	app.Post("/logout", middleware.AuthRequired, middleware.RejectAPIToken, func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	tokenRepo := new(mocks.MockAPITokenRepository)
+	permRepo := new(mocks.MockPermissionRepository)
+	middleware.SetAPITokenRepository(tokenRepo)
+	middleware.SetPermissionRepository(permRepo)
+	defer middleware.SetAPITokenRepository(nil)
+	defer middleware.SetPermissionRepository(nil)
+
+	raw, _, hash, err := utils.GenerateAPIToken()
+	assert.NoError(t, err)
+
+	owner := &model.User{ID: "user-1", Username: "admin", RoleID: "role-admin", Role: "Admin", IsActive: true}
+	tokenRepo.On("FindByHash", hash).Return(&model.APIToken{
+		ID:     "token-1",
+		UserID: "user-1",
+		Scopes: []string{"achievement:read", "user:manage"},
+	}, owner, nil)
+	tokenRepo.On("Touch", "token-1").Return(nil)
+
+	// Role masih punya kedua scope
+	permRepo.On("GetPermissionsByRoleID", "role-admin").Return([]string{"achievement:read", "user:manage"}, nil).Once()
+	assert.Equal(t, 200, requestWithToken(app, raw))
+
+	// Endpoint akun menolak API token
+	permRepo.On("GetPermissionsByRoleID", "role-admin").Return([]string{"achievement:read", "user:manage"}, nil).Once()
+	req := httptest.NewRequest("POST", "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+raw)
+	resp, _ := app.Test(req)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	// user:manage dicabut dari role: scope token ikut tidak berlaku
+	permRepo.On("GetPermissionsByRoleID", "role-admin").Return([]string{"achievement:read"}, nil).Once()
+	req = httptest.NewRequest("GET", "/scoped", nil)
+	req.Header.Set("Authorization", "Bearer "+raw)
+	resp, _ = app.Test(req)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestAuthRequired_APITokenExpiredOrUnknown(t *testing.T) {
+	app := setupProtectedApp(nil)
+
+	tokenRepo := new(mocks.MockAPITokenRepository)
+	middleware.SetAPITokenRepository(tokenRepo)
+	defer middleware.SetAPITokenRepository(nil)
+
+	expired, _, expiredHash, _ := utils.GenerateAPIToken()
+	unknown, _, unknownHash, _ := utils.GenerateAPIToken()
+
+	past := time.Now().Add(-time.Hour)
+	tokenRepo.On("FindByHash", expiredHash).Return(&model.APIToken{ID: "token-1", ExpiresAt: &past},
+		&model.User{ID: "user-1", IsActive: true}, nil)
+	tokenRepo.On("FindByHash", unknownHash).Return(nil, nil, sql.ErrNoRows)
+
+	assert.Equal(t, 401, requestWithToken(app, expired))
+	assert.Equal(t, 401, requestWithToken(app, unknown))
+	tokenRepo.AssertNotCalled(t, "Touch", mock.Anything)
+}
+
+func TestAuthRequired_ImpersonationIsReadOnlyAndAudited(t *testing.T) {
+	app := setupProtectedApp(nil)
+	app.Post("/protected", middleware.AuthRequired, func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	auditRepo := new(mocks.MockAuditLogRepository)
+	middleware.SetAuditLogRepository(auditRepo)
+	defer middleware.SetAuditLogRepository(nil)
+
+	token, _, _ := utils.GenerateImpersonationJWT(
+		model.UserResponse{ID: "student-1", Role: "Mahasiswa"},
+		model.ImpersonationActor{UserID: "admin-1", Username: "admin"},
+		time.Minute,
+	)
+
+	auditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == model.AuditActionImpersonationRequest && e.ActorID == "admin-1" && e.SubjectID == "student-1" &&
+			e.Method == "GET" && e.StatusCode == 200
+	})).Return(nil).Once()
+	auditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Method == "POST" && e.StatusCode == 403
+	})).Return(nil).Once()
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, _ := app.Test(req)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "admin", resp.Header.Get(middleware.HeaderImpersonatedBy))
+
+	// Aksi yang mengubah data ditolak secara default, tetap dicatat
+	req = httptest.NewRequest("POST", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, _ = app.Test(req)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	auditRepo.AssertExpectations(t)
+}
+
+func TestRejectImpersonation_AccountEndpointsEvenWithWriteAllowed(t *testing.T) {
+	config.AppConfig.ImpersonationAllowWrite = true
+	defer func() { config.AppConfig.ImpersonationAllowWrite = false }()
+
+	app := setupProtectedApp(nil)
+	app.Get("/tokens", middleware.AuthRequired, middleware.RejectImpersonation, func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+	app.Post("/password", middleware.AuthRequired, middleware.RejectImpersonation, func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	token, _, _ := utils.GenerateImpersonationJWT(
+		model.UserResponse{ID: "student-1", Role: "Mahasiswa"},
+		model.ImpersonationActor{UserID: "admin-1", Username: "admin"},
+		time.Minute,
+	)
+
+	// Termasuk GET: admin tidak boleh melihat API token milik user
+	req := httptest.NewRequest("GET", "/tokens", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, _ := app.Test(req)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	req = httptest.NewRequest("POST", "/password", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, _ = app.Test(req)
+	assert.Equal(t, 403, resp.StatusCode)
+}
@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"UASBE/app/model"
+	"time"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -28,6 +30,7 @@ func (m *MockUserRepository) GetPermissions(role string) ([]string, error) {
 func (m *MockUserRepository) Create(u *model.User) error { return m.Called(u).Error(0) }
 func (m *MockUserRepository) Update(u *model.User) error { return m.Called(u).Error(0) }
 func (m *MockUserRepository) Delete(id string) error { return m.Called(id).Error(0) }
+func (m *MockUserRepository) SoftDelete(id string) error { return m.Called(id).Error(0) }
 func (m *MockUserRepository) FindByEmail(e string) (*model.User, error) {
 	args := m.Called(e)
 	if args.Get(0) == nil { return nil, args.Error(1) }
@@ -41,7 +44,18 @@ func (m *MockUserRepository) CountAll(r string) (int, error) {
 	args := m.Called(r)
 	return args.Int(0), args.Error(1)
 }
+func (m *MockUserRepository) CountActiveWithPermission(p string) (int, error) {
+	args := m.Called(p)
+	return args.Int(0), args.Error(1)
+}
 func (m *MockUserRepository) UpdateRole(uid, rid string) error { return m.Called(uid, rid).Error(0) }
+func (m *MockUserRepository) IncrementFailedLogins(uid string) (int, error) {
+	args := m.Called(uid)
+	return args.Int(0), args.Error(1)
+}
+func (m *MockUserRepository) LockUntil(uid string, until time.Time) error { return m.Called(uid, until).Error(0) }
+func (m *MockUserRepository) ResetFailedLogins(uid string) error { return m.Called(uid).Error(0) }
+func (m *MockUserRepository) UpdatePassword(uid, hash string) error { return m.Called(uid, hash).Error(0) }
 
 // MockRoleRepository
 type MockRoleRepository struct{ mock.Mock }
@@ -55,6 +69,17 @@ func (m *MockRoleRepository) GetRoleByName(n string) (*model.Role, error) {
 	if args.Get(0) == nil { return nil, args.Error(1) }
 	return args.Get(0).(*model.Role), args.Error(1)
 }
+func (m *MockRoleRepository) GetAll() ([]model.Role, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Role), args.Error(1)
+}
+func (m *MockRoleRepository) Create(r *model.Role, permIDs []string) error { return m.Called(r, permIDs).Error(0) }
+func (m *MockRoleRepository) Update(r *model.Role) error { return m.Called(r).Error(0) }
+func (m *MockRoleRepository) Delete(id string) error { return m.Called(id).Error(0) }
+func (m *MockRoleRepository) CountUsers(id string) (int, error) {
+	args := m.Called(id)
+	return args.Int(0), args.Error(1)
+}
 
 // MockPermissionRepository
 type MockPermissionRepository struct{ mock.Mock }
@@ -62,6 +87,149 @@ func (m *MockPermissionRepository) GetPermissionsByRoleID(id string) ([]string,
 	args := m.Called(id)
 	return args.Get(0).([]string), args.Error(1)
 }
+func (m *MockPermissionRepository) GetAll() ([]model.Permission, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Permission), args.Error(1)
+}
+func (m *MockPermissionRepository) FindByName(name string) (*model.Permission, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*model.Permission), args.Error(1)
+}
+func (m *MockPermissionRepository) AttachToRole(rid, pid string) error { return m.Called(rid, pid).Error(0) }
+func (m *MockPermissionRepository) DetachFromRole(rid, pid string) error { return m.Called(rid, pid).Error(0) }
+
+// MockRefreshTokenRepository
+type MockRefreshTokenRepository struct{ mock.Mock }
+func (m *MockRefreshTokenRepository) Create(t *model.RefreshToken) error { return m.Called(t).Error(0) }
+func (m *MockRefreshTokenRepository) FindByID(id string) (*model.RefreshToken, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*model.RefreshToken), args.Error(1)
+}
+func (m *MockRefreshTokenRepository) MarkRotated(id, rb string) (bool, error) {
+	args := m.Called(id, rb)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockRefreshTokenRepository) Revoke(id string) error { return m.Called(id).Error(0) }
+func (m *MockRefreshTokenRepository) RevokeFamily(fid string) error { return m.Called(fid).Error(0) }
+func (m *MockRefreshTokenRepository) RevokeAllByUserID(uid string) error { return m.Called(uid).Error(0) }
+
+// MockPasswordResetRepository
+type MockPasswordResetRepository struct{ mock.Mock }
+func (m *MockPasswordResetRepository) Create(t *model.PasswordResetToken) error { return m.Called(t).Error(0) }
+func (m *MockPasswordResetRepository) FindByTokenHash(h string) (*model.PasswordResetToken, error) {
+	args := m.Called(h)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*model.PasswordResetToken), args.Error(1)
+}
+func (m *MockPasswordResetRepository) MarkUsed(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockPasswordResetRepository) InvalidateByUserID(uid string) error { return m.Called(uid).Error(0) }
+
+// MockMFARepository
+type MockMFARepository struct{ mock.Mock }
+func (m *MockMFARepository) SetSecret(uid, secret string) error { return m.Called(uid, secret).Error(0) }
+func (m *MockMFARepository) Enable(uid string, hashes []string) error { return m.Called(uid, hashes).Error(0) }
+func (m *MockMFARepository) Disable(uid string) error { return m.Called(uid).Error(0) }
+func (m *MockMFARepository) UseRecoveryCode(uid, h string) (bool, error) {
+	args := m.Called(uid, h)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockSessionRepository
+type MockSessionRepository struct{ mock.Mock }
+func (m *MockSessionRepository) Create(s *model.UserSession) error { return m.Called(s).Error(0) }
+func (m *MockSessionRepository) FindByID(id string) (*model.UserSession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*model.UserSession), args.Error(1)
+}
+func (m *MockSessionRepository) GetActiveByUserID(uid string) ([]model.UserSession, error) {
+	args := m.Called(uid)
+	return args.Get(0).([]model.UserSession), args.Error(1)
+}
+func (m *MockSessionRepository) Touch(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockSessionRepository) Revoke(id string) error { return m.Called(id).Error(0) }
+
+// MockAPITokenRepository
+type MockAPITokenRepository struct{ mock.Mock }
+func (m *MockAPITokenRepository) Create(t *model.APIToken) error { return m.Called(t).Error(0) }
+func (m *MockAPITokenRepository) FindByID(id string) (*model.APIToken, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*model.APIToken), args.Error(1)
+}
+func (m *MockAPITokenRepository) FindByHash(h string) (*model.APIToken, *model.User, error) {
+	args := m.Called(h)
+	if args.Get(0) == nil { return nil, nil, args.Error(2) }
+	return args.Get(0).(*model.APIToken), args.Get(1).(*model.User), args.Error(2)
+}
+func (m *MockAPITokenRepository) GetByUserID(uid string) ([]model.APIToken, error) {
+	args := m.Called(uid)
+	return args.Get(0).([]model.APIToken), args.Error(1)
+}
+func (m *MockAPITokenRepository) Touch(id string) error { return m.Called(id).Error(0) }
+func (m *MockAPITokenRepository) Revoke(id string) error { return m.Called(id).Error(0) }
+
+// MockAuditLogRepository
+type MockAuditLogRepository struct{ mock.Mock }
+func (m *MockAuditLogRepository) Create(e *model.AuditLog) error { return m.Called(e).Error(0) }
+
+// MockAchievementCommentRepository
+type MockAchievementCommentRepository struct{ mock.Mock }
+func (m *MockAchievementCommentRepository) Create(c *model.AchievementComment, n []model.Notification) error { return m.Called(c, n).Error(0) }
+func (m *MockAchievementCommentRepository) FindByID(id string) (*model.AchievementComment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*model.AchievementComment), args.Error(1)
+}
+func (m *MockAchievementCommentRepository) GetByAchievementRefID(id string) ([]model.AchievementComment, error) {
+	args := m.Called(id)
+	return args.Get(0).([]model.AchievementComment), args.Error(1)
+}
+
+// MockNotificationRepository
+type MockNotificationRepository struct{ mock.Mock }
+func (m *MockNotificationRepository) GetByUserID(uid string, unread bool) ([]model.Notification, error) {
+	args := m.Called(uid, unread)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+func (m *MockNotificationRepository) MarkRead(id, uid string) error { return m.Called(id, uid).Error(0) }
+
+// MockVerificationSLARepository
+type MockVerificationSLARepository struct{ mock.Mock }
+func (m *MockVerificationSLARepository) GetOverdue(before time.Time, sc model.AccessScope) ([]model.OverdueAchievement, error) {
+	args := m.Called(before, sc)
+	return args.Get(0).([]model.OverdueAchievement), args.Error(1)
+}
+func (m *MockVerificationSLARepository) FindReviewers(perm, dept string) ([]string, error) {
+	args := m.Called(perm, dept)
+	return args.Get(0).([]string), args.Error(1)
+}
+func (m *MockVerificationSLARepository) MarkReminded(id string, at time.Time, n []model.Notification) (bool, error) {
+	args := m.Called(id, at, n)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockVerificationSLARepository) MarkEscalated(id string, at time.Time, n []model.Notification) (bool, error) {
+	args := m.Called(id, at, n)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockIdentityRepository
+type MockIdentityRepository struct{ mock.Mock }
+func (m *MockIdentityRepository) Create(i *model.UserIdentity) error { return m.Called(i).Error(0) }
+func (m *MockIdentityRepository) FindByProviderSubject(p, sub string) (*model.UserIdentity, error) {
+	args := m.Called(p, sub)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*model.UserIdentity), args.Error(1)
+}
+func (m *MockIdentityRepository) TouchLastLogin(id string) error { return m.Called(id).Error(0) }
 
 // MockStudentRepository
 type MockStudentRepository struct{ mock.Mock }
@@ -121,6 +289,21 @@ func (m *MockLecturerRepository) CountAll() (int, error) {
 	args := m.Called()
 	return args.Int(0), args.Error(1)
 }
+func (m *MockLecturerRepository) CreateDelegation(d *model.VerifierDelegation) error { return m.Called(d).Error(0) }
+func (m *MockLecturerRepository) FindDelegationByID(id string) (*model.VerifierDelegation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*model.VerifierDelegation), args.Error(1)
+}
+func (m *MockLecturerRepository) GetDelegations(lid string) ([]model.VerifierDelegation, error) {
+	args := m.Called(lid)
+	return args.Get(0).([]model.VerifierDelegation), args.Error(1)
+}
+func (m *MockLecturerRepository) RevokeDelegation(id string) error { return m.Called(id).Error(0) }
+func (m *MockLecturerRepository) IsActiveDelegate(aid, did string) (bool, error) {
+	args := m.Called(aid, did)
+	return args.Bool(0), args.Error(1)
+}
 
 // MockAchievementRepository
 type MockAchievementRepository struct{ mock.Mock }
@@ -145,6 +328,8 @@ func (m *MockAchievementRepository) GetAchievementByID(id string) (*model.Achiev
 	return args.Get(0).(*model.Achievement), args.Error(1)
 }
 func (m *MockAchievementRepository) DeleteAchievement(id string) error { return m.Called(id).Error(0) }
+func (m *MockAchievementRepository) RestoreAchievement(id string) error { return m.Called(id).Error(0) }
+func (m *MockAchievementRepository) PurgeAchievement(id string) error { return m.Called(id).Error(0) }
 func (m *MockAchievementRepository) GetReferenceByMongoID(mid string) (*model.AchievementReference, error) {
 	args := m.Called(mid)
 	return args.Get(0).(*model.AchievementReference), args.Error(1)
@@ -157,50 +342,75 @@ func (m *MockAchievementRepository) CountReferencesByStudentID(sid, s string) (i
 	args := m.Called(sid, s)
 	return args.Int(0), args.Error(1)
 }
-func (m *MockAchievementRepository) GetReferencesByAdvisorID(aid, s string, l, o int) ([]model.AchievementReference, error) {
-	args := m.Called(aid, s, l, o)
+func (m *MockAchievementRepository) GetReferencesByScope(sc model.AccessScope, s string, l, o int) ([]model.AchievementReference, error) {
+	args := m.Called(sc, s, l, o)
 	return args.Get(0).([]model.AchievementReference), args.Error(1)
 }
-func (m *MockAchievementRepository) CountReferencesByAdvisorID(aid, s string) (int, error) {
-	args := m.Called(aid, s)
+func (m *MockAchievementRepository) CountReferencesByScope(sc model.AccessScope, s string) (int, error) {
+	args := m.Called(sc, s)
 	return args.Int(0), args.Error(1)
 }
-func (m *MockAchievementRepository) GetAllReferences(s string, l, o int) ([]model.AchievementReference, error) {
-	args := m.Called(s, l, o)
+func (m *MockAchievementRepository) GetDeletedReferences(l, o int) ([]model.AchievementReference, error) {
+	args := m.Called(l, o)
 	return args.Get(0).([]model.AchievementReference), args.Error(1)
 }
-func (m *MockAchievementRepository) CountAllReferences(s string) (int, error) {
-	args := m.Called(s)
+func (m *MockAchievementRepository) CountDeletedReferences() (int, error) {
+	args := m.Called()
 	return args.Int(0), args.Error(1)
 }
+func (m *MockAchievementRepository) GetPurgeableReferences(before time.Time) ([]model.AchievementReference, error) {
+	args := m.Called(before)
+	return args.Get(0).([]model.AchievementReference), args.Error(1)
+}
+func (m *MockAchievementRepository) PurgeReference(id string, cleanup func() error) error {
+	if err := m.Called(id).Error(0); err != nil { return err }
+	return cleanup()
+}
+func (m *MockAchievementRepository) TransitionReference(r *model.AchievementReference, e *model.AchievementStatusHistory) error {
+	return m.Called(r, e).Error(0)
+}
+func (m *MockAchievementRepository) GetStatusHistory(id string) ([]model.AchievementStatusHistory, error) {
+	args := m.Called(id)
+	return args.Get(0).([]model.AchievementStatusHistory), args.Error(1)
+}
+func (m *MockAchievementRepository) TransitionReferences(r []*model.AchievementReference, e []*model.AchievementStatusHistory) error {
+	return m.Called(r, e).Error(0)
+}
+func (m *MockAchievementRepository) SubmitReference(r *model.AchievementReference, e *model.AchievementStatusHistory, snap *model.AchievementSnapshot) error {
+	return m.Called(r, e, snap).Error(0)
+}
+func (m *MockAchievementRepository) GetSubmissions(id string) ([]model.AchievementSubmission, error) {
+	args := m.Called(id)
+	return args.Get(0).([]model.AchievementSubmission), args.Error(1)
+}
 func (m *MockAchievementRepository) UpdateAchievement(id string, a *model.Achievement) error { return m.Called(id, a).Error(0) }
 func (m *MockAchievementRepository) AddAttachment(id string, at model.Attachment) error { return m.Called(id, at).Error(0) }
 
 // MockReportRepository
 type MockReportRepository struct{ mock.Mock }
 
-func (m *MockReportRepository) GetTotalByType(sid, aid *string) (map[string]int, error) {
-	args := m.Called(sid, aid)
+func (m *MockReportRepository) GetTotalByType(sc model.AccessScope) (map[string]int, error) {
+	args := m.Called(sc)
 	return args.Get(0).(map[string]int), args.Error(1)
 }
 
-func (m *MockReportRepository) GetTotalByPeriod(sid, aid *string) ([]model.PeriodStats, error) {
-	args := m.Called(sid, aid)
+func (m *MockReportRepository) GetTotalByPeriod(sc model.AccessScope) ([]model.PeriodStats, error) {
+	args := m.Called(sc)
 	return args.Get(0).([]model.PeriodStats), args.Error(1)
 }
 
-func (m *MockReportRepository) GetTopStudents(limit int, aid *string) ([]model.TopStudent, error) {
-	args := m.Called(limit, aid)
+func (m *MockReportRepository) GetTopStudents(limit int, sc model.AccessScope) ([]model.TopStudent, error) {
+	args := m.Called(limit, sc)
 	return args.Get(0).([]model.TopStudent), args.Error(1)
 }
 
-func (m *MockReportRepository) GetCompetitionLevelDistribution(sid, aid *string) (map[string]int, error) {
-	args := m.Called(sid, aid)
+func (m *MockReportRepository) GetCompetitionLevelDistribution(sc model.AccessScope) (map[string]int, error) {
+	args := m.Called(sc)
 	return args.Get(0).(map[string]int), args.Error(1)
 }
 
-func (m *MockReportRepository) GetStatusBreakdown(sid, aid *string) (map[string]int, error) {
-	args := m.Called(sid, aid)
+func (m *MockReportRepository) GetStatusBreakdown(sc model.AccessScope) (map[string]int, error) {
+	args := m.Called(sc)
 	return args.Get(0).(map[string]int), args.Error(1)
 }
 
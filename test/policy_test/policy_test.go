@@ -0,0 +1,96 @@
+package policy_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/policy"
+	"UASBE/test/mocks"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_DepartmentScope(t *testing.T) {
+	// Role baru (mis. Kaprodi) cukup diberi achievement:read_department
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	p := policy.New(stuRepo, lecRepo)
+
+	stuRepo.On("FindByUserID", "user-kaprodi").Return(nil, errors.New("not found"))
+	lecRepo.On("FindByUserID", "user-kaprodi").Return(&model.Lecturer{ID: "lec-1", Department: "Informatika"}, nil)
+	lecRepo.On("IsActiveDelegate", "lec-2", "lec-1").Return(false, nil)
+
+	actor := p.Actor(&model.JWTClaims{
+		UserID:      "user-kaprodi",
+		Role:        "Kaprodi",
+		Permissions: []string{model.PermissionAchievementRead, model.PermissionAchievementReadDepartment},
+	})
+
+	scope := actor.ReadScope()
+	assert.Equal(t, model.ScopeDepartment, scope.Kind)
+	assert.Equal(t, "Informatika", scope.Department)
+
+	otherAdvisor := "lec-2"
+	assert.True(t, actor.CanReadStudent(&model.Student{ID: "std-1", ProgramStudy: "Informatika", AdvisorID: &otherAdvisor}))
+	assert.False(t, actor.CanReadStudent(&model.Student{ID: "std-2", ProgramStudy: "Sistem Informasi", AdvisorID: &otherAdvisor}))
+
+	// Tanpa achievement:verify tidak bisa verifikasi, walaupun dosen wali mahasiswa tersebut
+	ownAdvisor := "lec-1"
+	stuRepo.On("FindByID", "std-3").Return(&model.Student{ID: "std-3", AdvisorID: &ownAdvisor}, nil)
+	assert.True(t, actor.CanReadAchievement(&model.AchievementReference{StudentID: "std-3"}))
+	assert.False(t, actor.CanVerifyAchievement(&model.AchievementReference{StudentID: "std-3"}))
+}
+
+func TestPolicy_AdvisorCanVerifyOnlyAdvisees(t *testing.T) {
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	p := policy.New(stuRepo, lecRepo)
+
+	advisor := "lec-1"
+	otherAdvisor := "lec-2"
+	lecRepo.On("FindByUserID", "user-lec-1").Return(&model.Lecturer{ID: "lec-1"}, nil)
+	stuRepo.On("FindByUserID", "user-lec-1").Return(nil, errors.New("not found"))
+	stuRepo.On("FindByID", "std-1").Return(&model.Student{ID: "std-1", AdvisorID: &advisor}, nil)
+	stuRepo.On("FindByID", "std-2").Return(&model.Student{ID: "std-2", AdvisorID: &otherAdvisor}, nil)
+	lecRepo.On("IsActiveDelegate", "lec-2", "lec-1").Return(false, nil)
+
+	actor := p.Actor(&model.JWTClaims{
+		UserID:      "user-lec-1",
+		Permissions: []string{model.PermissionAchievementVerify},
+	})
+
+	assert.True(t, actor.CanVerifyAchievement(&model.AchievementReference{StudentID: "std-1"}))
+	assert.False(t, actor.CanVerifyAchievement(&model.AchievementReference{StudentID: "std-2"}))
+	assert.False(t, actor.IsOwner(&model.AchievementReference{StudentID: "std-1"}))
+}
+
+func TestPolicy_DelegateVerifiesOnBehalfOfAdvisor(t *testing.T) {
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	p := policy.New(stuRepo, lecRepo)
+
+	onLeave := "lec-1"
+	otherAdvisor := "lec-3"
+	lecRepo.On("FindByUserID", "user-lec-2").Return(&model.Lecturer{ID: "lec-2"}, nil)
+	stuRepo.On("FindByUserID", "user-lec-2").Return(nil, errors.New("not found"))
+	stuRepo.On("FindByID", "std-1").Return(&model.Student{ID: "std-1", AdvisorID: &onLeave}, nil)
+	stuRepo.On("FindByID", "std-2").Return(&model.Student{ID: "std-2", AdvisorID: &otherAdvisor}, nil)
+	lecRepo.On("IsActiveDelegate", "lec-1", "lec-2").Return(true, nil).Once()
+	lecRepo.On("IsActiveDelegate", "lec-3", "lec-2").Return(false, nil)
+
+	actor := p.Actor(&model.JWTClaims{
+		UserID:      "user-lec-2",
+		Permissions: []string{model.PermissionAchievementVerify},
+	})
+
+	onBehalfOf, ok := actor.VerifierFor(&model.AchievementReference{StudentID: "std-1"})
+	assert.True(t, ok)
+	if assert.NotNil(t, onBehalfOf) {
+		assert.Equal(t, "lec-1", *onBehalfOf)
+	}
+	assert.True(t, actor.CanReadAchievement(&model.AchievementReference{StudentID: "std-1"}))
+	assert.False(t, actor.CanVerifyAchievement(&model.AchievementReference{StudentID: "std-2"}))
+
+	// Delegasi dicek sekali per actor
+	lecRepo.AssertNumberOfCalls(t, "IsActiveDelegate", 2)
+}
@@ -2,12 +2,16 @@ package service_test
 
 import (
 	"UASBE/app/model"
+	"UASBE/app/repository"
 	"UASBE/app/service"
+	"UASBE/app/workflow"
 	"UASBE/test/mocks"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -18,7 +22,7 @@ func TestCreateAchievement_Success(t *testing.T) {
 	// Setup
 	achRepo := new(mocks.MockAchievementRepository)
 	stuRepo := new(mocks.MockStudentRepository)
-	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, nil)
 
 	app := fiber.New()
 	app.Post("/achievements", func(c *fiber.Ctx) error {
@@ -51,26 +55,478 @@ func TestCreateAchievement_Success(t *testing.T) {
 func TestSubmitForVerification_Success(t *testing.T) {
 	achRepo := new(mocks.MockAchievementRepository)
 	stuRepo := new(mocks.MockStudentRepository)
-	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, nil)
 
 	app := fiber.New()
 	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
-		c.Locals("user", &model.JWTClaims{UserID: "user-123", Role: "Mahasiswa"})
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Role: "Mahasiswa", Permissions: []string{model.PermissionAchievementUpdate}})
 		return svc.SubmitForVerification(c)
 	})
 
 	// Mock Data: Status must be 'draft' to submit
-	mockRef := &model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "draft"}
+	mockRef := &model.AchievementReference{ID: "ref-1", StudentID: "student-123", MongoAchievementID: "mongo-1", Status: "draft"}
 	mockStudent := &model.Student{ID: "student-123"}
 
 	achRepo.On("GetReferenceByID", "ref-1").Return(mockRef, nil)
 	stuRepo.On("FindByUserID", "user-123").Return(mockStudent, nil)
-	achRepo.On("UpdateReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
-		return r.Status == "submitted" // Verify status change to 'submitted'
+	achRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{Title: "Juara 1 Nasional"}, nil)
+	achRepo.On("SubmitReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.Status == "submitted" && r.SubmissionCount == 1 // Verify status change to 'submitted'
+	}), mock.MatchedBy(func(e *model.AchievementStatusHistory) bool {
+		return *e.FromStatus == "draft" && *e.ActorID == "user-123" && e.ActorRole == "Mahasiswa"
+	}), mock.MatchedBy(func(snap *model.AchievementSnapshot) bool {
+		return snap.Title == "Juara 1 Nasional"
 	})).Return(nil)
 
 	req := httptest.NewRequest("POST", "/achievements/ref-1/submit", nil)
 	resp, _ := app.Test(req)
 
 	assert.Equal(t, 200, resp.StatusCode)
-}
\ No newline at end of file
+}
+func TestGetAchievementHistory_FromHistoryTable(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	commentRepo := new(mocks.MockAchievementCommentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, commentRepo)
+
+	app := fiber.New()
+	app.Get("/achievements/:id/history", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Role: "Mahasiswa"})
+		return svc.GetAchievementHistory(c)
+	})
+
+	draft, submitted, rejected := "draft", "submitted", "rejected"
+	note1, note2 := "Sertifikat buram", "Tanggal tidak sesuai"
+	lecturer := "lecturer-1"
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "rejected"}, nil)
+	stuRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123"}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123"}, nil)
+
+	// Dua kali ditolak setelah diajukan ulang: semua transisi tetap ada
+	achRepo.On("GetStatusHistory", "ref-1").Return([]model.AchievementStatusHistory{
+		{ToStatus: "draft", CreatedAt: base},
+		{FromStatus: &draft, ToStatus: "submitted", CreatedAt: base.Add(time.Hour)},
+		{FromStatus: &submitted, ToStatus: "rejected", ActorID: &lecturer, ActorRole: "Dosen Wali", ActorName: "Bu Sari", Note: &note1, CreatedAt: base.Add(2 * time.Hour)},
+		{FromStatus: &rejected, ToStatus: "submitted", CreatedAt: base.Add(3 * time.Hour)},
+		{FromStatus: &submitted, ToStatus: "rejected", ActorID: &lecturer, ActorRole: "Dosen Wali", ActorName: "Bu Sari", Note: &note2, CreatedAt: base.Add(4 * time.Hour)},
+	}, nil)
+	commentRepo.On("GetByAchievementRefID", "ref-1").Return([]model.AchievementComment{}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-1/history", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var res struct {
+		Data struct {
+			History []service.HistoryEntry `json:"history"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+
+	assert.Len(t, res.Data.History, 5)
+	assert.Equal(t, "Bu Sari (Dosen Wali)", res.Data.History[2].Actor)
+	assert.Equal(t, "2025-03-01 10:00:00", res.Data.History[2].Timestamp)
+	assert.Equal(t, note2, *res.Data.History[4].Notes)
+}
+
+func TestVerifyAchievement_ConcurrentStatusChange(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, lecRepo, nil, nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "lecturer-user", Role: "Dosen Wali", Permissions: []string{model.PermissionAchievementVerify}})
+		return svc.VerifyAchievement(c)
+	})
+
+	advisorID := "lecturer-1"
+	lecRepo.On("FindByUserID", "lecturer-user").Return(&model.Lecturer{ID: advisorID}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisorID}, nil)
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "submitted"}, nil)
+
+	// Reference sudah diproses request lain (mis. ditolak dosen lain di tab berbeda)
+	achRepo.On("TransitionReference", mock.Anything, mock.Anything).Return(repository.ErrStatusChanged)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/verify", nil))
+	assert.Equal(t, 409, resp.StatusCode)
+}
+
+func TestSubmitForVerification_InvalidTransition(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Role: "Mahasiswa", Permissions: []string{model.PermissionAchievementUpdate}})
+		return svc.SubmitForVerification(c)
+	})
+
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "verified"}, nil)
+	stuRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123"}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/submit", nil))
+	assert.Equal(t, 409, resp.StatusCode)
+
+	var res struct {
+		Code string `json:"code"`
+		Data struct {
+			CurrentStatus     string   `json:"current_status"`
+			AllowedNextStates []string `json:"allowed_next_states"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+
+	assert.Equal(t, model.ErrCodeInvalidTransition, res.Code)
+	assert.Equal(t, "verified", res.Data.CurrentStatus)
+	assert.Empty(t, res.Data.AllowedNextStates)
+	achRepo.AssertNotCalled(t, "TransitionReference", mock.Anything, mock.Anything)
+}
+
+func TestSubmitForVerification_ResubmitRejected(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/submit", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Role: "Mahasiswa", Permissions: []string{model.PermissionAchievementUpdate}})
+		return svc.SubmitForVerification(c)
+	})
+
+	note := "Sertifikat buram"
+	mockRef := &model.AchievementReference{ID: "ref-1", StudentID: "student-123", MongoAchievementID: "mongo-1", Status: "rejected", RejectionNote: &note, SubmissionCount: 1}
+
+	achRepo.On("GetReferenceByID", "ref-1").Return(mockRef, nil)
+	stuRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123"}, nil)
+	achRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{Title: "Juara 1 Nasional (revisi)"}, nil)
+	achRepo.On("SubmitReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		// Pengajuan kedua, catatan penolakan sebelumnya tetap ada
+		return r.Status == "submitted" && r.SubmissionCount == 2 && r.RejectionNote != nil && *r.RejectionNote == note
+	}), mock.MatchedBy(func(e *model.AchievementStatusHistory) bool {
+		return *e.FromStatus == "rejected"
+	}), mock.Anything).Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/submit", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var res struct {
+		Data struct {
+			SubmissionCount int `json:"submission_count"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+	assert.Equal(t, 2, res.Data.SubmissionCount)
+}
+
+func TestGetAchievementChanges_SinceLastSubmission(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, lecRepo, nil, nil)
+
+	app := fiber.New()
+	app.Get("/achievements/:id/changes", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "lecturer-user", Role: "Dosen Wali", Permissions: []string{model.PermissionAchievementRead}})
+		return svc.GetAchievementChanges(c)
+	})
+
+	advisor := "lecturer-1"
+	note := "Sertifikat buram"
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", MongoAchievementID: "mongo-1", Status: "submitted", RejectionNote: &note, SubmissionCount: 2}, nil)
+	stuRepo.On("FindByUserID", "lecturer-user").Return(nil, errors.New("not found"))
+	lecRepo.On("FindByUserID", "lecturer-user").Return(&model.Lecturer{ID: "lecturer-1"}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisor}, nil)
+
+	// Snapshot dari JSONB: angka menjadi float64
+	achRepo.On("GetSubmissions", "ref-1").Return([]model.AchievementSubmission{
+		{SubmissionNumber: 1, CreatedAt: base, Snapshot: model.AchievementSnapshot{
+			Title: "Juara 1", Points: 50, Details: map[string]interface{}{"rank": float64(1), "level": "nasional"},
+		}},
+		{SubmissionNumber: 2, CreatedAt: base.Add(48 * time.Hour), Snapshot: model.AchievementSnapshot{
+			Title: "Juara 1 Nasional", Points: 50, Details: map[string]interface{}{"rank": 1, "level": "nasional", "organizer": "Kemdikbud"},
+		}},
+	}, nil)
+	achRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{
+		Title: "Juara 1 Nasional", Points: 50, Details: map[string]interface{}{"rank": 1, "level": "nasional", "organizer": "Kemdikbud"},
+	}, nil)
+	achRepo.On("GetStatusHistory", "ref-1").Return([]model.AchievementStatusHistory{
+		{ToStatus: "rejected", Note: &note, CreatedAt: base.Add(24 * time.Hour)},
+	}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-1/changes", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var res struct {
+		Data model.AchievementChangesResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+
+	assert.Equal(t, 1, *res.Data.ComparedToSubmission)
+	assert.Equal(t, note, *res.Data.PreviousRejectionNote)
+	if assert.Len(t, res.Data.Changes, 2) {
+		assert.Equal(t, "title", res.Data.Changes[0].Field)
+		assert.Equal(t, "Juara 1", res.Data.Changes[0].Previous)
+		assert.Equal(t, "details.organizer", res.Data.Changes[1].Field)
+		assert.Nil(t, res.Data.Changes[1].Previous)
+	}
+}
+
+func TestVerifyAchievement_DelegateRecordsOnBehalfOf(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, lecRepo, nil, nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "delegate-user", Role: "Dosen Wali", Permissions: []string{model.PermissionAchievementVerify}})
+		return svc.VerifyAchievement(c)
+	})
+
+	// Dosen wali lecturer-1 sedang cuti, verifikasi didelegasikan ke lecturer-2
+	advisorID := "lecturer-1"
+	lecRepo.On("FindByUserID", "delegate-user").Return(&model.Lecturer{ID: "lecturer-2"}, nil)
+	lecRepo.On("IsActiveDelegate", advisorID, "lecturer-2").Return(true, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisorID}, nil)
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "submitted"}, nil)
+	achRepo.On("TransitionReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.Status == "verified" && *r.VerifiedBy == "delegate-user"
+	}), mock.MatchedBy(func(h *model.AchievementStatusHistory) bool {
+		return *h.ActorID == "delegate-user" && h.OnBehalfOf != nil && *h.OnBehalfOf == advisorID
+	})).Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/verify", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+	achRepo.AssertExpectations(t)
+}
+
+func TestVerifyAchievement_IntermediateStageKeepsSubmitted(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, lecRepo, nil, nil)
+
+	chains, _ := workflow.ParseApprovalChains([]byte(`{"chains": [{"name": "international_competition",
+		"stages": [{"name": "advisor", "approver": "advisor"}, {"name": "kaprodi", "approver": "department"}]}]}`))
+	svc.SetApprovalChains(chains)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "lecturer-user", Role: "Dosen Wali", Permissions: []string{model.PermissionAchievementVerify}})
+		return svc.VerifyAchievement(c)
+	})
+
+	advisorID, chain := "lecturer-1", "international_competition"
+	lecRepo.On("FindByUserID", "lecturer-user").Return(&model.Lecturer{ID: advisorID}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisorID}, nil)
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "submitted", ApprovalChain: &chain}, nil)
+	achRepo.On("TransitionReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.Status == "submitted" && r.ApprovalStage == 1 && r.VerifiedAt == nil
+	}), mock.MatchedBy(func(h *model.AchievementStatusHistory) bool {
+		return *h.ApprovalStage == 0 && *h.StageName == "advisor"
+	})).Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/verify", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var res struct {
+		Data struct {
+			Status   string                 `json:"status"`
+			Approval model.ApprovalProgress `json:"approval"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+	assert.Equal(t, "submitted", res.Data.Status)
+	assert.Equal(t, 2, res.Data.Approval.Stage)
+	assert.Equal(t, "kaprodi", res.Data.Approval.StageName)
+	achRepo.AssertExpectations(t)
+}
+
+func TestWithdrawSubmission(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	commentRepo := new(mocks.MockAchievementCommentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, commentRepo)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/withdraw", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "student-123", Role: "Mahasiswa", Permissions: []string{model.PermissionAchievementUpdate}})
+		return svc.WithdrawSubmission(c)
+	})
+
+	stuRepo.On("FindByUserID", "student-123").Return(&model.Student{ID: "student-123"}, nil)
+
+	submittedAt := time.Now().Add(-time.Hour)
+	owner, advisor := "student-123", "lecturer-user"
+	achRepo.On("GetReferenceByID", "ref-new").Return(&model.AchievementReference{ID: "ref-new", StudentID: "student-123", Status: "submitted", SubmittedAt: &submittedAt, SubmissionCount: 1}, nil)
+	achRepo.On("GetReferenceByID", "ref-commented").Return(&model.AchievementReference{ID: "ref-commented", StudentID: "student-123", Status: "submitted", SubmittedAt: &submittedAt}, nil)
+	achRepo.On("GetReferenceByID", "ref-approved").Return(&model.AchievementReference{ID: "ref-approved", StudentID: "student-123", Status: "submitted", SubmittedAt: &submittedAt, ApprovalStage: 1}, nil)
+	achRepo.On("GetReferenceByID", "ref-verified").Return(&model.AchievementReference{ID: "ref-verified", StudentID: "student-123", Status: "verified"}, nil)
+	achRepo.On("GetReferenceByID", "ref-raced").Return(&model.AchievementReference{ID: "ref-raced", StudentID: "student-123", Status: "submitted", SubmittedAt: &submittedAt}, nil)
+
+	// Komentar dosen dari pengajuan sebelumnya tidak dihitung, balasan dosen sesudah diajukan dihitung
+	commentRepo.On("GetByAchievementRefID", "ref-new").Return([]model.AchievementComment{
+		{AuthorID: &advisor, CreatedAt: submittedAt.Add(-24 * time.Hour)},
+		{AuthorID: &owner, CreatedAt: submittedAt.Add(time.Minute)},
+	}, nil)
+	commentRepo.On("GetByAchievementRefID", "ref-commented").Return([]model.AchievementComment{
+		{AuthorID: &owner, CreatedAt: submittedAt.Add(time.Minute), Replies: []model.AchievementComment{
+			{AuthorID: &advisor, CreatedAt: submittedAt.Add(2 * time.Minute)},
+		}},
+	}, nil)
+	commentRepo.On("GetByAchievementRefID", "ref-raced").Return([]model.AchievementComment{}, nil)
+
+	// Update hanya berlaku selama approval_stage di database masih 0
+	achRepo.On("TransitionReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.ID == "ref-new" && r.Status == "draft" && r.SubmittedAt == nil && r.SubmissionCount == 1
+	}), mock.MatchedBy(func(h *model.AchievementStatusHistory) bool {
+		return *h.FromStatus == "submitted" && *h.ActorID == "student-123" && h.ApprovalStage != nil && *h.ApprovalStage == 0 && h.StageName == nil
+	})).Return(nil)
+	// ref-raced: dosen wali menyetujui tahap pertama setelah reference dibaca
+	achRepo.On("TransitionReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.ID == "ref-raced"
+	}), mock.MatchedBy(func(h *model.AchievementStatusHistory) bool {
+		return h.ApprovalStage != nil && *h.ApprovalStage == 0
+	})).Return(repository.ErrStatusChanged)
+
+	withdraw := func(id string) int {
+		resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/"+id+"/withdraw", nil))
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 200, withdraw("ref-new"))
+	assert.Equal(t, 409, withdraw("ref-commented"))
+	assert.Equal(t, 409, withdraw("ref-approved"))
+	assert.Equal(t, 409, withdraw("ref-verified"))
+	assert.Equal(t, 409, withdraw("ref-raced"))
+	achRepo.AssertNumberOfCalls(t, "TransitionReference", 2)
+}
+
+func TestRestoreAchievement(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	svc := service.NewAchievementService(achRepo, nil, nil, nil, nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/restore", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-1", Role: "Admin", Permissions: []string{model.PermissionUserManage}})
+		return svc.RestoreAchievement(c)
+	})
+
+	deletedAt := time.Now().Add(-24 * time.Hour)
+	achRepo.On("GetReferenceByID", "ref-deleted").Return(&model.AchievementReference{ID: "ref-deleted", StudentID: "student-123", MongoAchievementID: "mongo-1", Status: "deleted", DeletedAt: &deletedAt}, nil)
+	achRepo.On("GetReferenceByID", "ref-purged").Return(&model.AchievementReference{ID: "ref-purged", MongoAchievementID: "mongo-2", Status: "deleted", DeletedAt: &deletedAt}, nil)
+	achRepo.On("GetReferenceByID", "ref-draft").Return(&model.AchievementReference{ID: "ref-draft", MongoAchievementID: "mongo-3", Status: "draft"}, nil)
+	achRepo.On("RestoreAchievement", "mongo-1").Return(nil)
+	achRepo.On("RestoreAchievement", "mongo-2").Return(repository.ErrAchievementNotFound)
+	achRepo.On("TransitionReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.ID == "ref-deleted" && r.Status == "draft" && r.DeletedAt == nil
+	}), mock.MatchedBy(func(h *model.AchievementStatusHistory) bool {
+		return *h.FromStatus == "deleted" && *h.ActorID == "admin-1"
+	})).Return(nil)
+
+	restore := func(id string) int {
+		resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/"+id+"/restore", nil))
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 200, restore("ref-deleted"))
+	assert.Equal(t, 404, restore("ref-purged"))
+	assert.Equal(t, 409, restore("ref-draft"))
+	achRepo.AssertNumberOfCalls(t, "TransitionReference", 1)
+}
+
+func TestGetAchievementByID_DeletedOnlyVisibleToAdmin(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, nil)
+
+	app := fiber.New()
+	app.Get("/achievements/:id", func(c *fiber.Ctx) error {
+		if c.Query("as") == "admin" {
+			c.Locals("user", &model.JWTClaims{UserID: "admin-1", Role: "Admin", Permissions: []string{model.PermissionAchievementReadAll, model.PermissionUserManage}})
+		} else {
+			c.Locals("user", &model.JWTClaims{UserID: "student-123", Role: "Mahasiswa", Permissions: []string{model.PermissionAchievementRead}})
+		}
+		return svc.GetAchievementByID(c)
+	})
+
+	deletedAt := time.Now()
+	achRepo.On("GetReferenceByID", "ref-deleted").Return(&model.AchievementReference{ID: "ref-deleted", StudentID: "student-123", MongoAchievementID: "mongo-1", Status: "deleted", DeletedAt: &deletedAt}, nil)
+	achRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{StudentID: "student-123", DeletedAt: &deletedAt}, nil)
+
+	// Pemilik tidak lagi melihat prestasi yang sudah dihapus
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-deleted", nil))
+	assert.Equal(t, 404, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/achievements/ref-deleted?as=admin", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+	stuRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+func TestDeleteAchievement_ConcurrentStatusChangeUndoesSoftDelete(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, nil)
+
+	app := fiber.New()
+	app.Delete("/achievements/:id", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "student-123", Role: "Mahasiswa", Permissions: []string{model.PermissionAchievementDelete}})
+		return svc.DeleteAchievement(c)
+	})
+
+	stuRepo.On("FindByUserID", "student-123").Return(&model.Student{ID: "student-123"}, nil)
+
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", MongoAchievementID: "mongo-1", Status: "draft"}, nil)
+	achRepo.On("DeleteAchievement", "mongo-1").Return(nil)
+	// Mahasiswa mengajukan prestasi yang sama di request lain
+	achRepo.On("TransitionReference", mock.Anything, mock.Anything).Return(repository.ErrStatusChanged)
+	achRepo.On("RestoreAchievement", "mongo-1").Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/achievements/ref-1", nil))
+
+	assert.Equal(t, 409, resp.StatusCode)
+	achRepo.AssertExpectations(t)
+}
+
+func TestVerifyAchievement_SameApproverCannotDecideTwoStages(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, lecRepo, nil, nil)
+
+	chains, _ := workflow.ParseApprovalChains([]byte(`{"chains": [{"name": "international_competition",
+		"stages": [{"name": "advisor", "approver": "advisor"}, {"name": "kaprodi", "approver": "department"}]}]}`))
+	svc.SetApprovalChains(chains)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/verify", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "lecturer-1", Role: "Dosen Wali", Permissions: []string{model.PermissionAchievementVerify, model.PermissionAchievementReadDepartment}})
+		return svc.VerifyAchievement(c)
+	})
+
+	advisorID, chain := "lecturer-1", "international_competition"
+	lecRepo.On("FindByUserID", "lecturer-1").Return(&model.Lecturer{ID: advisorID, Department: "Informatika"}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisorID, ProgramStudy: "Informatika"}, nil)
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "submitted", ApprovalChain: &chain, ApprovalStage: 1}, nil)
+
+	// Dosen wali (juga reviewer departemen) sudah menyetujui tahap 1 pengajuan ulang ini
+	draft, submitted, revision := "draft", "submitted", "revision_requested"
+	owner, kaprodi, stage0, stage1 := "student-user", "lecturer-9", 0, 1
+	achRepo.On("GetStatusHistory", "ref-1").Return([]model.AchievementStatusHistory{
+		{FromStatus: &draft, ToStatus: "submitted", ActorID: &owner},
+		{FromStatus: &submitted, ToStatus: "submitted", ActorID: &advisorID, ApprovalStage: &stage0},
+		{FromStatus: &submitted, ToStatus: "revision_requested", ActorID: &kaprodi, ApprovalStage: &stage1},
+		{FromStatus: &revision, ToStatus: "submitted", ActorID: &owner},
+		{FromStatus: &submitted, ToStatus: "submitted", ActorID: &advisorID, ApprovalStage: &stage0},
+	}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/achievements/ref-1/verify", nil))
+	assert.Equal(t, 403, resp.StatusCode)
+	achRepo.AssertNotCalled(t, "TransitionReference", mock.Anything, mock.Anything)
+}
@@ -25,17 +25,22 @@ func TestGetStatistics_Admin_Success(t *testing.T) {
 	app := fiber.New()
 	app.Use(func(c *fiber.Ctx) error {
 		// Mock Admin Login
-		c.Locals("user", &model.JWTClaims{UserID: "admin-1", Role: "Admin"})
+		c.Locals("user", &model.JWTClaims{
+			UserID:      "admin-1",
+			Role:        "Admin",
+			Permissions: []string{model.PermissionAchievementReadAll},
+		})
 		return c.Next()
 	})
 	app.Get("/reports/statistics", svc.GetStatistics)
 
-	// 2. Mock Data & Expectations (Scope Admin: studentID & advisorID = nil)
-	reportRepo.On("GetTotalByType", (*string)(nil), (*string)(nil)).Return(map[string]int{"competition": 5}, nil)
-	reportRepo.On("GetTotalByPeriod", (*string)(nil), (*string)(nil)).Return([]model.PeriodStats{{Period: "2025-01", Count: 5}}, nil)
-	reportRepo.On("GetTopStudents", 10, (*string)(nil)).Return([]model.TopStudent{{StudentName: "John Doe"}}, nil)
-	reportRepo.On("GetCompetitionLevelDistribution", (*string)(nil), (*string)(nil)).Return(map[string]int{"national": 3}, nil)
-	reportRepo.On("GetStatusBreakdown", (*string)(nil), (*string)(nil)).Return(map[string]int{"verified": 5}, nil)
+	// 2. Mock Data & Expectations (achievement:read_all → scope semua data)
+	all := model.AccessScope{Kind: model.ScopeAll}
+	reportRepo.On("GetTotalByType", all).Return(map[string]int{"competition": 5}, nil)
+	reportRepo.On("GetTotalByPeriod", all).Return([]model.PeriodStats{{Period: "2025-01", Count: 5}}, nil)
+	reportRepo.On("GetTopStudents", 10, all).Return([]model.TopStudent{{StudentName: "John Doe"}}, nil)
+	reportRepo.On("GetCompetitionLevelDistribution", all).Return(map[string]int{"national": 3}, nil)
+	reportRepo.On("GetStatusBreakdown", all).Return(map[string]int{"verified": 5}, nil)
 
 	// 3. Execution
 	req := httptest.NewRequest("GET", "/reports/statistics", nil)
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// batchFixture - Dosen wali lecturer-1 dengan dua prestasi submitted milik bimbingannya,
+// satu prestasi mahasiswa lain, dan satu prestasi yang sudah diverifikasi
+func batchFixture() (*mocks.MockAchievementRepository, *fiber.App) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, lecRepo, nil, nil)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "lecturer-user", Role: "Dosen Wali", Permissions: []string{model.PermissionAchievementVerify}})
+		return c.Next()
+	})
+	app.Post("/achievements/batch/verify", svc.BatchVerify)
+	app.Post("/achievements/batch/reject", svc.BatchReject)
+
+	advisorID, otherAdvisor := "lecturer-1", "lecturer-2"
+	lecRepo.On("FindByUserID", "lecturer-user").Return(&model.Lecturer{ID: advisorID}, nil)
+	lecRepo.On("IsActiveDelegate", otherAdvisor, advisorID).Return(false, nil)
+	stuRepo.On("FindByID", "std-1").Return(&model.Student{ID: "std-1", AdvisorID: &advisorID}, nil)
+	stuRepo.On("FindByID", "std-2").Return(&model.Student{ID: "std-2", AdvisorID: &otherAdvisor}, nil)
+
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "std-1", Status: "submitted"}, nil)
+	achRepo.On("GetReferenceByID", "ref-2").Return(&model.AchievementReference{ID: "ref-2", StudentID: "std-1", Status: "submitted"}, nil)
+	achRepo.On("GetReferenceByID", "ref-other").Return(&model.AchievementReference{ID: "ref-other", StudentID: "std-2", Status: "submitted"}, nil)
+	achRepo.On("GetReferenceByID", "ref-verified").Return(&model.AchievementReference{ID: "ref-verified", StudentID: "std-1", Status: "verified"}, nil)
+	achRepo.On("GetReferenceByID", "ref-missing").Return(nil, errors.New("not found"))
+
+	return achRepo, app
+}
+
+func postBatch(app *fiber.App, path string, payload interface{}) (int, model.BatchResult) {
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	var res struct {
+		Data model.BatchResult `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+	return resp.StatusCode, res.Data
+}
+
+func TestBatchVerify_AtomicAbortsOnAnyFailure(t *testing.T) {
+	achRepo, app := batchFixture()
+
+	status, result := postBatch(app, "/achievements/batch/verify", model.BatchVerifyRequest{
+		IDs: []string{"ref-1", "ref-other", "ref-verified", "ref-missing"},
+	})
+
+	assert.Equal(t, 409, status)
+	assert.Equal(t, model.BatchModeAtomic, result.Mode)
+	assert.Equal(t, 0, result.Succeeded)
+	assert.Equal(t, 4, result.Failed)
+	if assert.Len(t, result.Results, 4) {
+		assert.Equal(t, "not applied: batch aborted", result.Results[0].Error)
+		assert.Equal(t, 403, result.Results[1].Code)
+		assert.Equal(t, 409, result.Results[2].Code)
+		assert.Equal(t, 404, result.Results[3].Code)
+	}
+	achRepo.AssertNotCalled(t, "TransitionReferences", mock.Anything, mock.Anything)
+	achRepo.AssertNotCalled(t, "TransitionReference", mock.Anything, mock.Anything)
+}
+
+func TestBatchVerify_AtomicSingleTransaction(t *testing.T) {
+	achRepo, app := batchFixture()
+	achRepo.On("TransitionReferences", mock.MatchedBy(func(refs []*model.AchievementReference) bool {
+		return len(refs) == 2 && refs[0].Status == "verified" && refs[1].VerifiedBy != nil && *refs[1].VerifiedBy == "lecturer-user"
+	}), mock.Anything).Return(nil)
+
+	status, result := postBatch(app, "/achievements/batch/verify", model.BatchVerifyRequest{IDs: []string{"ref-1", "ref-2"}})
+
+	assert.Equal(t, 200, status)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, "verified", result.Results[1].Status)
+	achRepo.AssertNumberOfCalls(t, "TransitionReferences", 1)
+}
+
+func TestBatchReject_PartialReportsPerItem(t *testing.T) {
+	achRepo, app := batchFixture()
+	achRepo.On("TransitionReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.ID == "ref-1" && r.Status == "rejected" && *r.RejectionNote == "Sertifikat buram"
+	}), mock.Anything).Return(nil)
+	achRepo.On("TransitionReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.ID == "ref-2" && *r.RejectionNote == "Bukti tidak lengkap"
+	}), mock.Anything).Return(nil)
+
+	status, result := postBatch(app, "/achievements/batch/reject", model.BatchRejectRequest{
+		Mode:          model.BatchModePartial,
+		RejectionNote: "Bukti tidak lengkap",
+		Items: []model.BatchRejectItem{
+			{ID: "ref-1", RejectionNote: "Sertifikat buram"},
+			{ID: "ref-other"},
+			{ID: "ref-2"},
+			{ID: "ref-1"},
+		},
+	})
+
+	assert.Equal(t, 200, status)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	assert.True(t, result.Results[0].Success)
+	assert.Equal(t, 403, result.Results[1].Code)
+	assert.True(t, result.Results[2].Success)
+	assert.Equal(t, "duplicate id in batch", result.Results[3].Error)
+	achRepo.AssertNumberOfCalls(t, "TransitionReference", 2)
+}
+
+func TestBatchReject_RequiresNote(t *testing.T) {
+	achRepo, app := batchFixture()
+
+	status, _ := postBatch(app, "/achievements/batch/reject", model.BatchRejectRequest{
+		Items: []model.BatchRejectItem{{ID: "ref-1"}},
+	})
+
+	assert.Equal(t, 422, status)
+	achRepo.AssertNotCalled(t, "GetReferenceByID", mock.Anything)
+}
@@ -0,0 +1,221 @@
+package service_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateComment_ReplyWithMentions(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	userRepo := new(mocks.MockUserRepository)
+	permRepo := new(mocks.MockPermissionRepository)
+	commentRepo := new(mocks.MockAchievementCommentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, lecRepo, userRepo, commentRepo)
+	svc.SetPermissionRepository(permRepo)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/comments", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Username: "budi", Role: "Mahasiswa"})
+		return svc.CreateComment(c)
+	})
+
+	advisor := "lecturer-user"
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "revision_requested"}, nil)
+	stuRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123"}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisor}, nil)
+	commentRepo.On("FindByID", "comment-1").Return(&model.AchievementComment{ID: "comment-1", AchievementRefID: "ref-1"}, nil)
+
+	// @bu.sari (dosen wali) dikenali, @pak.joko (dosen lain) tidak boleh membaca prestasi,
+	// @tidakada tidak ada, email bukan mention
+	userRepo.On("FindByUsername", "bu.sari").Return(&model.User{ID: "lecturer-user", Username: "bu.sari", RoleID: "role-dosen", IsActive: true}, nil)
+	userRepo.On("FindByUsername", "pak.joko").Return(&model.User{ID: "lecturer-other", Username: "pak.joko", RoleID: "role-dosen", IsActive: true}, nil)
+	userRepo.On("FindByUsername", "tidakada").Return(nil, errors.New("not found"))
+	permRepo.On("GetPermissionsByRoleID", "role-dosen").Return([]string{model.PermissionAchievementRead, model.PermissionAchievementVerify}, nil)
+	stuRepo.On("FindByUserID", "lecturer-user").Return(nil, errors.New("not found"))
+	stuRepo.On("FindByUserID", "lecturer-other").Return(nil, errors.New("not found"))
+	lecRepo.On("FindByUserID", "lecturer-user").Return(&model.Lecturer{ID: "lecturer-user"}, nil)
+	lecRepo.On("FindByUserID", "lecturer-other").Return(&model.Lecturer{ID: "lecturer-other"}, nil)
+	lecRepo.On("IsActiveDelegate", "lecturer-user", "lecturer-other").Return(false, nil)
+
+	commentRepo.On("Create", mock.MatchedBy(func(c *model.AchievementComment) bool {
+		return *c.ParentID == "comment-1" && *c.AuthorID == "user-123" && c.AuthorRole == "Mahasiswa" &&
+			len(c.Mentions) == 1 && c.Mentions[0].UserID == "lecturer-user"
+	}), mock.MatchedBy(func(n []model.Notification) bool {
+		return len(n) == 1 && n[0].UserID == "lecturer-user" && n[0].Type == model.NotificationCommentMention && *n[0].AchievementRefID == "ref-1"
+	})).Return(nil)
+
+	parentID := "comment-1"
+	body, _ := json.Marshal(model.AchievementCommentRequest{
+		Body:     "@bu.sari sertifikat sudah saya ganti, cc @pak.joko @tidakada (kirim ke admin@kampus.ac.id)",
+		ParentID: &parentID,
+	})
+	req := httptest.NewRequest("POST", "/achievements/ref-1/comments", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 201, resp.StatusCode)
+	userRepo.AssertNotCalled(t, "FindByUsername", "kampus.ac.id")
+	commentRepo.AssertExpectations(t)
+}
+
+func TestCreateComment_ParentFromOtherAchievement(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	commentRepo := new(mocks.MockAchievementCommentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, commentRepo)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/comments", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Role: "Mahasiswa"})
+		return svc.CreateComment(c)
+	})
+
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "submitted"}, nil)
+	stuRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123"}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123"}, nil)
+	commentRepo.On("FindByID", "comment-9").Return(&model.AchievementComment{ID: "comment-9", AchievementRefID: "ref-2"}, nil)
+
+	parentID := "comment-9"
+	body, _ := json.Marshal(model.AchievementCommentRequest{Body: "balasan", ParentID: &parentID})
+	req := httptest.NewRequest("POST", "/achievements/ref-1/comments", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 422, resp.StatusCode)
+	commentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestRequestRevision_IsNotARejection(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, lecRepo, nil, nil)
+
+	app := fiber.New()
+	app.Post("/achievements/:id/request-revision", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "lecturer-user", Role: "Dosen Wali", Permissions: []string{model.PermissionAchievementVerify}})
+		return svc.RequestRevision(c)
+	})
+
+	advisorID := "lecturer-1"
+	lecRepo.On("FindByUserID", "lecturer-user").Return(&model.Lecturer{ID: advisorID}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123", AdvisorID: &advisorID}, nil)
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "submitted"}, nil)
+	achRepo.On("TransitionReference", mock.MatchedBy(func(r *model.AchievementReference) bool {
+		return r.Status == "revision_requested" && r.RejectionNote == nil
+	}), mock.MatchedBy(func(e *model.AchievementStatusHistory) bool {
+		return *e.FromStatus == "submitted" && *e.Note == "Lengkapi nama penyelenggara"
+	})).Return(nil)
+
+	body, _ := json.Marshal(model.RequestRevisionRequest{Note: "Lengkapi nama penyelenggara"})
+	req := httptest.NewRequest("POST", "/achievements/ref-1/request-revision", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	achRepo.AssertExpectations(t)
+}
+
+func TestGetComments_Threaded(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	commentRepo := new(mocks.MockAchievementCommentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, commentRepo)
+
+	app := fiber.New()
+	app.Get("/achievements/:id/comments", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Role: "Mahasiswa"})
+		return svc.GetComments(c)
+	})
+
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "submitted"}, nil)
+	stuRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123"}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123"}, nil)
+
+	c1, c2 := "c1", "c2"
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	commentRepo.On("GetByAchievementRefID", "ref-1").Return([]model.AchievementComment{
+		{ID: "c1", Body: "Tanggal lomba?", CreatedAt: base},
+		{ID: "c2", ParentID: &c1, Body: "12 Maret", CreatedAt: base.Add(time.Minute)},
+		{ID: "c3", Body: "Sertifikat?", CreatedAt: base.Add(2 * time.Minute)},
+		{ID: "c4", ParentID: &c2, Body: "Oke", CreatedAt: base.Add(3 * time.Minute)},
+	}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-1/comments", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var res struct {
+		Data struct {
+			Total    int                        `json:"total"`
+			Comments []model.AchievementComment `json:"comments"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+
+	assert.Equal(t, 4, res.Data.Total)
+	if assert.Len(t, res.Data.Comments, 2) {
+		assert.Equal(t, "c1", res.Data.Comments[0].ID)
+		assert.Equal(t, "c2", res.Data.Comments[0].Replies[0].ID)
+		assert.Equal(t, "c4", res.Data.Comments[0].Replies[0].Replies[0].ID)
+		assert.Equal(t, "c3", res.Data.Comments[1].ID)
+	}
+}
+
+func TestGetAchievementHistory_IncludesComments(t *testing.T) {
+	achRepo := new(mocks.MockAchievementRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	commentRepo := new(mocks.MockAchievementCommentRepository)
+	svc := service.NewAchievementService(achRepo, stuRepo, nil, nil, commentRepo)
+
+	app := fiber.New()
+	app.Get("/achievements/:id/history", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-123", Role: "Mahasiswa"})
+		return svc.GetAchievementHistory(c)
+	})
+
+	achRepo.On("GetReferenceByID", "ref-1").Return(&model.AchievementReference{ID: "ref-1", StudentID: "student-123", Status: "revision_requested"}, nil)
+	stuRepo.On("FindByUserID", "user-123").Return(&model.Student{ID: "student-123"}, nil)
+	stuRepo.On("FindByID", "student-123").Return(&model.Student{ID: "student-123"}, nil)
+
+	draft, submitted := "draft", "submitted"
+	lecturer := "lecturer-1"
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	achRepo.On("GetStatusHistory", "ref-1").Return([]model.AchievementStatusHistory{
+		{ToStatus: "draft", CreatedAt: base},
+		{FromStatus: &draft, ToStatus: "submitted", CreatedAt: base.Add(time.Hour)},
+		{FromStatus: &submitted, ToStatus: "revision_requested", ActorID: &lecturer, CreatedAt: base.Add(3 * time.Hour)},
+	}, nil)
+	commentRepo.On("GetByAchievementRefID", "ref-1").Return([]model.AchievementComment{
+		{ID: "c1", AuthorID: &lecturer, AuthorRole: "Dosen Wali", AuthorName: "Bu Sari", Body: "Penyelenggaranya siapa?", CreatedAt: base.Add(2 * time.Hour)},
+	}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/ref-1/history", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var res struct {
+		Data struct {
+			History []service.HistoryEntry `json:"history"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+
+	if assert.Len(t, res.Data.History, 4) {
+		assert.Equal(t, "comment", res.Data.History[2].Type)
+		assert.Equal(t, "Bu Sari (Dosen Wali)", res.Data.History[2].Actor)
+		assert.Equal(t, "Penyelenggaranya siapa?", *res.Data.History[2].Notes)
+		assert.Equal(t, "Revision requested", res.Data.History[3].Action)
+	}
+}
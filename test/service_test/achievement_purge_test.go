@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/repository"
+	"UASBE/app/service"
+	"UASBE/config"
+	"UASBE/test/mocks"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPurge_RemovesDocumentsAndFiles(t *testing.T) {
+	config.AppConfig = config.Config{DeletedAchievementRetention: 30 * 24 * time.Hour}
+	achRepo := new(mocks.MockAchievementRepository)
+	uploadsDir := t.TempDir()
+	svc := service.NewAchievementPurgeService(achRepo, uploadsDir)
+
+	for _, name := range []string{"sertifikat.pdf", "foto.jpg", "lain.pdf"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(uploadsDir, name), []byte("x"), 0644))
+	}
+
+	now := time.Date(2025, 3, 20, 9, 0, 0, 0, time.UTC)
+	deletedAt := now.Add(-31 * 24 * time.Hour)
+	achRepo.On("GetPurgeableReferences", now.Add(-30*24*time.Hour)).Return([]model.AchievementReference{
+		{ID: "ref-1", MongoAchievementID: "mongo-1", Status: "deleted", DeletedAt: &deletedAt},
+		{ID: "ref-2", MongoAchievementID: "mongo-2", Status: "deleted", DeletedAt: &deletedAt},
+		{ID: "ref-3", MongoAchievementID: "mongo-3", Status: "deleted", DeletedAt: &deletedAt},
+		{ID: "ref-4", MongoAchievementID: "mongo-4", Status: "deleted", DeletedAt: &deletedAt},
+	}, nil)
+
+	// ref-1: lampiran yang sudah hilang dan URL di luar /uploads dilewati
+	achRepo.On("GetAchievementByID", "mongo-1").Return(&model.Achievement{Attachments: []model.Attachment{
+		{FileURL: "/uploads/sertifikat.pdf"},
+		{FileURL: "/uploads/foto.jpg"},
+		{FileURL: "/uploads/hilang.pdf"},
+		{FileURL: "https://drive.example.com/bukti.pdf"},
+	}}, nil)
+	achRepo.On("PurgeAchievement", "mongo-1").Return(nil)
+	achRepo.On("PurgeReference", "ref-1").Return(nil)
+
+	// ref-2: dokumen sudah dihapus run sebelumnya, tinggal reference
+	achRepo.On("GetAchievementByID", "mongo-2").Return((*model.Achievement)(nil), repository.ErrAchievementNotFound)
+	achRepo.On("PurgeReference", "ref-2").Return(nil)
+
+	// ref-3: MongoDB gagal, penghapusan reference dibatalkan dan diulang run berikutnya
+	achRepo.On("PurgeReference", "ref-3").Return(nil)
+	achRepo.On("GetAchievementByID", "mongo-3").Return(&model.Achievement{}, nil)
+	achRepo.On("PurgeAchievement", "mongo-3").Return(errors.New("mongo unavailable"))
+
+	// ref-4: sudah di-restore admin sebelum di-klaim; dokumen dan file tidak disentuh
+	achRepo.On("PurgeReference", "ref-4").Return(repository.ErrStatusChanged)
+
+	result, err := svc.RunPurge(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AchievementPurgeResult{Checked: 4, Purged: 2, FilesRemoved: 2}, result)
+	assert.NoFileExists(t, filepath.Join(uploadsDir, "sertifikat.pdf"))
+	assert.NoFileExists(t, filepath.Join(uploadsDir, "foto.jpg"))
+	assert.FileExists(t, filepath.Join(uploadsDir, "lain.pdf"))
+	achRepo.AssertNotCalled(t, "PurgeAchievement", "mongo-2")
+	achRepo.AssertNotCalled(t, "GetAchievementByID", "mongo-4")
+	achRepo.AssertExpectations(t)
+}
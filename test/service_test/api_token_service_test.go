@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"UASBE/utils"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAPITokenApp(tokenRepo *mocks.MockAPITokenRepository) *fiber.App {
+	svc := service.NewAPITokenService(tokenRepo)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Permissions: []string{"achievement:read", "achievement:create"}})
+		return c.Next()
+	})
+	app.Post("/tokens", svc.CreateToken)
+	app.Delete("/tokens/:id", svc.RevokeToken)
+	return app
+}
+
+func postTokenRequest(app *fiber.App, body interface{}) (int, []byte) {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/tokens", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, respBody
+}
+
+func TestCreateAPIToken_ScopesMustBeOwned(t *testing.T) {
+	tokenRepo := new(mocks.MockAPITokenRepository)
+	app := newAPITokenApp(tokenRepo)
+
+	// user:manage bukan permission user ini
+	status, _ := postTokenRequest(app, model.APITokenCreateRequest{Name: "ci", Scopes: []string{"achievement:read", "user:manage"}})
+	assert.Equal(t, 400, status)
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	var stored *model.APIToken
+	tokenRepo.On("Create", mock.MatchedBy(func(token *model.APIToken) bool {
+		stored = token
+		return true
+	})).Return(nil)
+
+	status, body := postTokenRequest(app, model.APITokenCreateRequest{Name: "ci", Scopes: []string{"achievement:read"}, ExpiresInDays: 30})
+	assert.Equal(t, 201, status)
+
+	var res struct {
+		Data model.APITokenCreatedResponse `json:"data"`
+	}
+	json.Unmarshal(body, &res)
+
+	// Yang disimpan hanya hash; token asli hanya ada di response
+	assert.True(t, utils.IsAPIToken(res.Data.Token))
+	assert.Equal(t, utils.HashAPIToken(res.Data.Token), stored.TokenHash)
+	assert.NotContains(t, string(body), stored.TokenHash)
+	assert.NotNil(t, stored.ExpiresAt)
+}
+
+func TestRevokeAPIToken_OwnOnly(t *testing.T) {
+	tokenRepo := new(mocks.MockAPITokenRepository)
+	app := newAPITokenApp(tokenRepo)
+
+	tokenRepo.On("FindByID", "token-other").Return(&model.APIToken{ID: "token-other", UserID: "user-2"}, nil)
+	tokenRepo.On("FindByID", "token-1").Return(&model.APIToken{ID: "token-1", UserID: "user-1"}, nil)
+	tokenRepo.On("Revoke", "token-1").Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/tokens/token-other", nil))
+	assert.Equal(t, 404, resp.StatusCode)
+	tokenRepo.AssertNotCalled(t, "Revoke", "token-other")
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/tokens/token-1", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+}
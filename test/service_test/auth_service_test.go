@@ -2,16 +2,23 @@ package service_test
 
 import (
 	"UASBE/app/model"
+	"UASBE/app/repository"
 	"UASBE/app/service"
+	"UASBE/config"
 	"UASBE/test/mocks"
 	"UASBE/utils"
 	"bytes"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestLogin_Success(t *testing.T) {
@@ -19,7 +26,9 @@ func TestLogin_Success(t *testing.T) {
 	userRepo := new(mocks.MockUserRepository)
 	roleRepo := new(mocks.MockRoleRepository)
 	permRepo := new(mocks.MockPermissionRepository)
-	authSvc := service.NewAuthService(userRepo, roleRepo, permRepo)
+	refreshRepo := new(mocks.MockRefreshTokenRepository)
+	sessionRepo := new(mocks.MockSessionRepository)
+	authSvc := service.NewAuthService(userRepo, roleRepo, permRepo, refreshRepo, nil, nil, nil, sessionRepo)
 	utils.JwtKey = []byte("test_secret")
 
 	app := fiber.New()
@@ -42,6 +51,12 @@ func TestLogin_Success(t *testing.T) {
 	userRepo.On("FindByUsername", "mahasiswa123").Return(mockUser, nil)
 	roleRepo.On("GetRoleByID", "role-1").Return(mockRole, nil)
 	permRepo.On("GetPermissionsByRoleID", "role-1").Return(mockPerms, nil)
+	sessionRepo.On("Create", mock.MatchedBy(func(s *model.UserSession) bool {
+		return s.UserID == "uuid-1" && s.ID != ""
+	})).Return(nil)
+	refreshRepo.On("Create", mock.MatchedBy(func(t *model.RefreshToken) bool {
+		return t.UserID == "uuid-1" && t.FamilyID != ""
+	})).Return(nil)
 
 	// 4. Request
 	loginReq := model.LoginRequest{Username: "mahasiswa123", Password: password}
@@ -62,13 +77,14 @@ func TestLogin_Success(t *testing.T) {
 
 func TestLogin_WrongPassword(t *testing.T) {
 	userRepo := new(mocks.MockUserRepository)
-	authSvc := service.NewAuthService(userRepo, nil, nil)
+	authSvc := service.NewAuthService(userRepo, nil, nil, nil, nil, nil, nil, nil)
 	app := fiber.New()
 	app.Post("/login", authSvc.Login)
 
 	hashed, _ := utils.HashPassword("correct_pass")
 	mockUser := &model.User{Username: "user1", PasswordHash: hashed}
 	userRepo.On("FindByUsername", "user1").Return(mockUser, nil)
+	userRepo.On("IncrementFailedLogins", mock.Anything).Return(1, nil)
 
 	loginReq := model.LoginRequest{Username: "user1", Password: "wrong_password"}
 	body, _ := json.Marshal(loginReq)
@@ -78,4 +94,328 @@ func TestLogin_WrongPassword(t *testing.T) {
 	resp, _ := app.Test(req)
 
 	assert.Equal(t, 401, resp.StatusCode) // Unauthorized
-}
\ No newline at end of file
+}
+
+func TestRefresh_ReusedToken_RevokesFamily(t *testing.T) {
+	refreshRepo := new(mocks.MockRefreshTokenRepository)
+	authSvc := service.NewAuthService(nil, nil, nil, refreshRepo, nil, nil, nil, nil)
+	utils.JwtKey = []byte("test_secret")
+
+	app := fiber.New()
+	app.Post("/refresh", authSvc.Refresh)
+
+	// Token lama sudah dirotasi (replaced_by terisi) lalu dipakai lagi
+	refreshToken, _ := utils.GenerateRefreshToken("uuid-1", "jti-old", time.Now().Add(time.Hour))
+	revokedAt := time.Now()
+	replacedBy := "jti-new"
+	refreshRepo.On("FindByID", "jti-old").Return(&model.RefreshToken{
+		ID:         "jti-old",
+		UserID:     "uuid-1",
+		FamilyID:   "family-1",
+		RevokedAt:  &revokedAt,
+		ReplacedBy: &replacedBy,
+	}, nil)
+	refreshRepo.On("RevokeFamily", "family-1").Return(nil)
+
+	body, _ := json.Marshal(model.RefreshTokenRequest{RefreshToken: refreshToken})
+	req := httptest.NewRequest("POST", "/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 401, resp.StatusCode)
+	refreshRepo.AssertCalled(t, "RevokeFamily", "family-1")
+}
+
+func TestLoginAndRefresh_RoleLookupFails(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	refreshRepo := new(mocks.MockRefreshTokenRepository)
+	sessionRepo := new(mocks.MockSessionRepository)
+	authSvc := service.NewAuthService(userRepo, roleRepo, nil, refreshRepo, nil, nil, nil, sessionRepo)
+	utils.JwtKey = []byte("test_secret")
+
+	app := fiber.New()
+	app.Post("/login", authSvc.Login)
+	app.Post("/refresh", authSvc.Refresh)
+
+	hashed, _ := utils.HashPassword("correct_pass")
+	userRepo.On("FindByUsername", "user1").Return(&model.User{ID: "uuid-1", Username: "user1", PasswordHash: hashed, IsActive: true, RoleID: "role-1"}, nil)
+	userRepo.On("FindByID", "uuid-1").Return(&model.User{ID: "uuid-1", IsActive: true, RoleID: "role-gone"}, nil)
+	roleRepo.On("GetRoleByID", "role-1").Return(nil, errors.New("connection refused"))
+	roleRepo.On("GetRoleByID", "role-gone").Return(nil, sql.ErrNoRows)
+
+	// Database error: login gagal tanpa membuat sesi (dan tanpa melewati kewajiban MFA)
+	body, _ := json.Marshal(model.LoginRequest{Username: "user1", Password: "correct_pass"})
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, 500, resp.StatusCode)
+
+	// Role user sudah dihapus: refresh ditolak sebelum token dirotasi
+	refreshToken, _ := utils.GenerateRefreshToken("uuid-1", "jti-1", time.Now().Add(time.Hour))
+	refreshRepo.On("FindByID", "jti-1").Return(&model.RefreshToken{ID: "jti-1", UserID: "uuid-1", FamilyID: "family-1"}, nil)
+	body, _ = json.Marshal(model.RefreshTokenRequest{RefreshToken: refreshToken})
+	req = httptest.NewRequest("POST", "/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+	assert.Equal(t, 401, resp.StatusCode)
+
+	sessionRepo.AssertNotCalled(t, "Create", mock.Anything)
+	refreshRepo.AssertNotCalled(t, "MarkRotated", mock.Anything, mock.Anything)
+}
+
+func TestLogin_InactiveAccount(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	authSvc := service.NewAuthService(userRepo, nil, nil, nil, nil, nil, nil, nil)
+	app := fiber.New()
+	app.Post("/login", authSvc.Login)
+
+	hashed, _ := utils.HashPassword("correct_pass")
+	mockUser := &model.User{Username: "user1", PasswordHash: hashed, IsActive: false}
+	userRepo.On("FindByUsername", "user1").Return(mockUser, nil)
+
+	loginReq := model.LoginRequest{Username: "user1", Password: "correct_pass"}
+	body, _ := json.Marshal(loginReq)
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 403, resp.StatusCode)
+
+	var apiResp model.APIResponse
+	json.NewDecoder(resp.Body).Decode(&apiResp)
+	assert.Equal(t, model.ErrCodeAccountInactive, apiResp.Code)
+}
+
+func TestLogin_LocksAccountAfterMaxAttempts(t *testing.T) {
+	config.AppConfig.LoginMaxAttempts = 3
+	config.AppConfig.LoginLockoutBase = time.Minute
+	config.AppConfig.LoginLockoutMax = time.Hour
+	defer func() { config.AppConfig = config.Config{} }()
+
+	userRepo := new(mocks.MockUserRepository)
+	authSvc := service.NewAuthService(userRepo, nil, nil, nil, nil, nil, nil, nil)
+	app := fiber.New()
+	app.Post("/login", authSvc.Login)
+
+	hashed, _ := utils.HashPassword("correct_pass")
+	mockUser := &model.User{ID: "uuid-1", Username: "user1", PasswordHash: hashed, IsActive: true, FailedLoginAttempts: 2}
+	userRepo.On("FindByUsername", "user1").Return(mockUser, nil)
+	userRepo.On("IncrementFailedLogins", "uuid-1").Return(3, nil)
+	userRepo.On("LockUntil", "uuid-1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	loginReq := model.LoginRequest{Username: "user1", Password: "wrong_password"}
+	body, _ := json.Marshal(loginReq)
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 423, resp.StatusCode)
+
+	var apiResp model.APIResponse
+	json.NewDecoder(resp.Body).Decode(&apiResp)
+	assert.Equal(t, model.ErrCodeAccountLocked, apiResp.Code)
+	userRepo.AssertExpectations(t)
+}
+
+func TestChangePassword_RevokesExistingTokens(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	refreshRepo := new(mocks.MockRefreshTokenRepository)
+	resetRepo := new(mocks.MockPasswordResetRepository)
+	revokeRepo := repository.NewMemoryTokenRevocationRepository()
+	authSvc := service.NewAuthService(userRepo, nil, nil, refreshRepo, revokeRepo, resetRepo, nil, nil)
+
+	app := fiber.New()
+	app.Post("/password", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "uuid-1"})
+		return authSvc.ChangePassword(c)
+	})
+
+	hashed, _ := utils.HashPassword("old_pass")
+	userRepo.On("FindByID", "uuid-1").Return(&model.User{ID: "uuid-1", PasswordHash: hashed, IsActive: true}, nil)
+	userRepo.On("UpdatePassword", "uuid-1", mock.Anything).Return(nil)
+	refreshRepo.On("RevokeAllByUserID", "uuid-1").Return(nil)
+	resetRepo.On("InvalidateByUserID", "uuid-1").Return(nil)
+
+	body, _ := json.Marshal(model.ChangePasswordRequest{OldPassword: "old_pass", NewPassword: "new_pass"})
+	req := httptest.NewRequest("POST", "/password", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req, -1) // bcrypt dipanggil 3x, lewati timeout default 1 detik
+
+	assert.Equal(t, 200, resp.StatusCode)
+	refreshRepo.AssertExpectations(t)
+	cutoff, _ := revokeRepo.GetTokensValidAfter("uuid-1")
+	assert.NotNil(t, cutoff)
+}
+
+func TestResetPassword_UsedToken(t *testing.T) {
+	resetRepo := new(mocks.MockPasswordResetRepository)
+	authSvc := service.NewAuthService(nil, nil, nil, nil, nil, resetRepo, nil, nil)
+
+	app := fiber.New()
+	app.Post("/password/reset", authSvc.ResetPassword)
+
+	usedAt := time.Now()
+	resetRepo.On("FindByTokenHash", utils.HashResetToken("reset-token")).Return(&model.PasswordResetToken{
+		ID:        "reset-1",
+		UserID:    "uuid-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}, nil)
+
+	body, _ := json.Marshal(model.ResetPasswordRequest{Token: "reset-token", NewPassword: "new_pass"})
+	req := httptest.NewRequest("POST", "/password/reset", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestLogin_MFA_TwoStep(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	permRepo := new(mocks.MockPermissionRepository)
+	refreshRepo := new(mocks.MockRefreshTokenRepository)
+	revokeRepo := repository.NewMemoryTokenRevocationRepository()
+	sessionRepo := new(mocks.MockSessionRepository)
+	authSvc := service.NewAuthService(userRepo, roleRepo, permRepo, refreshRepo, revokeRepo, nil, new(mocks.MockMFARepository), sessionRepo)
+	utils.JwtKey = []byte("test_secret")
+	config.AppConfig.MFAChallengeTTL = time.Minute
+	defer func() { config.AppConfig = config.Config{} }()
+
+	app := fiber.New()
+	app.Post("/login", authSvc.Login)
+	app.Post("/mfa/verify", authSvc.VerifyMFA)
+
+	secret, _ := utils.GenerateTOTPSecret()
+	hashed, _ := utils.HashPassword("SecurePass123!")
+	mockUser := &model.User{
+		ID:           "uuid-1",
+		Username:     "admin",
+		PasswordHash: hashed,
+		IsActive:     true,
+		RoleID:       "role-1",
+		MFAEnabled:   true,
+		MFASecret:    &secret,
+	}
+
+	userRepo.On("FindByUsername", "admin").Return(mockUser, nil)
+	userRepo.On("FindByID", "uuid-1").Return(mockUser, nil)
+	roleRepo.On("GetRoleByID", "role-1").Return(&model.Role{ID: "role-1", Name: "Admin"}, nil)
+	permRepo.On("GetPermissionsByRoleID", "role-1").Return([]string{"user:manage"}, nil)
+	refreshRepo.On("Create", mock.Anything).Return(nil)
+	sessionRepo.On("Create", mock.Anything).Return(nil)
+
+	// Langkah 1: password benar → challenge token, belum ada access token
+	body, _ := json.Marshal(model.LoginRequest{Username: "admin", Password: "SecurePass123!"})
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req, -1)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var challengeResp struct {
+		Data model.MFAChallengeResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&challengeResp)
+	assert.True(t, challengeResp.Data.MFARequired)
+	assert.False(t, challengeResp.Data.EnrollmentRequired)
+	refreshRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	// Langkah 2: kode TOTP → token
+	verify := func() int {
+		code, _ := utils.TOTPCode(secret, time.Now())
+		body, _ := json.Marshal(model.MFAVerifyRequest{ChallengeToken: challengeResp.Data.ChallengeToken, Code: code})
+		req := httptest.NewRequest("POST", "/mfa/verify", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 200, verify())
+	refreshRepo.AssertNumberOfCalls(t, "Create", 1)
+
+	// Challenge token hanya bisa dipakai sekali
+	assert.Equal(t, 401, verify())
+}
+
+func TestSessions_ListAndRevokeOwnOnly(t *testing.T) {
+	sessionRepo := new(mocks.MockSessionRepository)
+	authSvc := service.NewAuthService(nil, nil, nil, nil, nil, nil, nil, sessionRepo)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", SessionID: "session-1"})
+		return c.Next()
+	})
+	app.Get("/sessions", authSvc.GetSessions)
+	app.Delete("/sessions/:id", authSvc.RevokeSession)
+
+	now := time.Now()
+	sessionRepo.On("GetActiveByUserID", "user-1").Return([]model.UserSession{
+		{ID: "session-1", UserID: "user-1", UserAgent: "Firefox", IssuedAt: now, LastUsedAt: now},
+		{ID: "session-2", UserID: "user-1", UserAgent: "Android", IssuedAt: now, LastUsedAt: now},
+	}, nil)
+	sessionRepo.On("FindByID", "session-2").Return(&model.UserSession{ID: "session-2", UserID: "user-1"}, nil)
+	sessionRepo.On("FindByID", "session-other").Return(&model.UserSession{ID: "session-other", UserID: "user-2"}, nil)
+	sessionRepo.On("Revoke", "session-2").Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/sessions", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var listResp struct {
+		Data []model.SessionResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&listResp)
+	assert.Len(t, listResp.Data, 2)
+	assert.True(t, listResp.Data[0].Current)
+	assert.False(t, listResp.Data[1].Current)
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/sessions/session-2", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	// Sesi user lain: 404, tidak di-revoke
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/sessions/session-other", nil))
+	assert.Equal(t, 404, resp.StatusCode)
+	sessionRepo.AssertNotCalled(t, "Revoke", "session-other")
+}
+
+func TestLogout_RevokesOnlyCurrentSession(t *testing.T) {
+	refreshRepo := new(mocks.MockRefreshTokenRepository)
+	sessionRepo := new(mocks.MockSessionRepository)
+	revokeRepo := repository.NewMemoryTokenRevocationRepository()
+	authSvc := service.NewAuthService(nil, nil, nil, refreshRepo, revokeRepo, nil, nil, sessionRepo)
+
+	expiresAt := jwt.NewNumericDate(time.Now().Add(time.Hour))
+	app := fiber.New()
+	app.Post("/logout", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "uuid-1", SessionID: "session-1", RegisteredClaims: jwt.RegisteredClaims{ID: "jti-1", ExpiresAt: expiresAt}})
+		return authSvc.Logout(c)
+	})
+	app.Post("/logout/all", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "uuid-1", SessionID: "session-2", RegisteredClaims: jwt.RegisteredClaims{ID: "jti-2", ExpiresAt: expiresAt}})
+		return authSvc.LogoutAll(c)
+	})
+
+	// Tanpa refresh token: hanya sesi dari access token (sid) yang di-revoke
+	sessionRepo.On("Revoke", "session-1").Return(nil)
+	resp, _ := app.Test(httptest.NewRequest("POST", "/logout", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+	refreshRepo.AssertNotCalled(t, "RevokeAllByUserID", mock.Anything)
+
+	// Logout semua device harus eksplisit
+	refreshRepo.On("RevokeAllByUserID", "uuid-1").Return(nil)
+	resp, _ = app.Test(httptest.NewRequest("POST", "/logout/all", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	denied, _ := revokeRepo.IsTokenDenied("jti-1")
+	assert.True(t, denied)
+	denied, _ = revokeRepo.IsTokenDenied("jti-2")
+	assert.True(t, denied)
+	sessionRepo.AssertExpectations(t)
+	refreshRepo.AssertExpectations(t)
+}
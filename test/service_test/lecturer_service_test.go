@@ -5,10 +5,13 @@ import (
 	"UASBE/app/service"
 	"UASBE/test/mocks"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetLecturerAdvisees_Success(t *testing.T) {
@@ -47,4 +50,39 @@ func TestGetLecturerAdvisees_Success(t *testing.T) {
 	resp, _ := app.Test(req)
 
 	assert.Equal(t, 200, resp.StatusCode)
-}
\ No newline at end of file
+}
+func TestCreateDelegation_Validation(t *testing.T) {
+	lecRepo := new(mocks.MockLecturerRepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	svc := service.NewLecturerService(lecRepo, stuRepo, nil, nil)
+
+	app := fiber.New()
+	app.Post("/lecturers/:id/delegations", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-lec-1", Role: "Dosen Wali", Permissions: []string{model.PermissionAchievementVerify}})
+		return svc.CreateDelegation(c)
+	})
+
+	lecRepo.On("FindByUserID", "user-lec-1").Return(&model.Lecturer{ID: "lec-1"}, nil)
+	lecRepo.On("FindByID", "lec-1").Return(&model.Lecturer{ID: "lec-1"}, nil)
+	lecRepo.On("FindByID", "lec-2").Return(&model.Lecturer{ID: "lec-2"}, nil)
+	lecRepo.On("FindByID", "lec-3").Return(&model.Lecturer{ID: "lec-3"}, nil)
+	lecRepo.On("CreateDelegation", mock.MatchedBy(func(d *model.VerifierDelegation) bool {
+		return d.AdvisorID == "lec-1" && d.DelegateID == "lec-2" && *d.CreatedBy == "user-lec-1"
+	})).Return(nil)
+
+	post := func(lecturerID string, body string) int {
+		req := httptest.NewRequest("POST", "/lecturers/"+lecturerID+"/delegations", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		return resp.StatusCode
+	}
+
+	today := time.Now()
+	from, until := today.Format("2006-01-02"), today.AddDate(0, 0, 14).Format("2006-01-02")
+
+	assert.Equal(t, 422, post("lec-1", `{"delegate_id":"lec-2","starts_on":"`+until+`","ends_on":"`+from+`"}`))
+	assert.Equal(t, 422, post("lec-1", `{"delegate_id":"lec-1","starts_on":"`+from+`","ends_on":"`+until+`"}`))
+	assert.Equal(t, 403, post("lec-3", `{"delegate_id":"lec-2","starts_on":"`+from+`","ends_on":"`+until+`"}`))
+	assert.Equal(t, 201, post("lec-1", `{"delegate_id":"lec-2","starts_on":"`+from+`","ends_on":"`+until+`"}`))
+	lecRepo.AssertNumberOfCalls(t, "CreateDelegation", 1)
+}
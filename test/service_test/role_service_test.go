@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/service"
+	"UASBE/test/mocks"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDetachPermission_AdminKeepsUserManage(t *testing.T) {
+	roleRepo := new(mocks.MockRoleRepository)
+	permRepo := new(mocks.MockPermissionRepository)
+	svc := service.NewRoleService(roleRepo, permRepo)
+
+	app := fiber.New()
+	app.Delete("/roles/:id/permissions/:permission", svc.DetachPermission)
+
+	roleRepo.On("GetRoleByID", "role-admin").Return(&model.Role{ID: "role-admin", Name: model.RoleAdmin}, nil)
+	permRepo.On("FindByName", "user:manage").Return(&model.Permission{ID: "perm-1", Name: "user:manage"}, nil)
+
+	req := httptest.NewRequest("DELETE", "/roles/role-admin/permissions/user:manage", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 409, resp.StatusCode)
+	permRepo.AssertNotCalled(t, "DetachFromRole", "role-admin", "perm-1")
+}
+
+func TestDeleteRole_BuiltInRejected(t *testing.T) {
+	roleRepo := new(mocks.MockRoleRepository)
+	svc := service.NewRoleService(roleRepo, nil)
+
+	app := fiber.New()
+	app.Delete("/roles/:id", svc.DeleteRole)
+
+	roleRepo.On("GetRoleByID", "role-mhs").Return(&model.Role{ID: "role-mhs", Name: model.RoleMahasiswa}, nil)
+
+	req := httptest.NewRequest("DELETE", "/roles/role-mhs", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 409, resp.StatusCode)
+	roleRepo.AssertNotCalled(t, "Delete", "role-mhs")
+}
+
+func TestCreateRole_WithPermissions(t *testing.T) {
+	roleRepo := new(mocks.MockRoleRepository)
+	permRepo := new(mocks.MockPermissionRepository)
+	svc := service.NewRoleService(roleRepo, permRepo)
+
+	app := fiber.New()
+	app.Post("/roles", svc.CreateRole)
+
+	roleRepo.On("GetRoleByName", "Kaprodi").Return(nil, assert.AnError)
+	permRepo.On("FindByName", "achievement:verify").Return(&model.Permission{ID: "perm-verify", Name: "achievement:verify"}, nil)
+	roleRepo.On("Create", &model.Role{Name: "Kaprodi", Description: "Kepala program studi"}, []string{"perm-verify"}).
+		Run(func(args mock.Arguments) { args.Get(0).(*model.Role).ID = "role-kaprodi" }).
+		Return(nil)
+	permRepo.On("GetPermissionsByRoleID", "role-kaprodi").Return([]string{"achievement:verify"}, nil)
+
+	body, _ := json.Marshal(model.RoleCreateRequest{
+		Name:        "Kaprodi",
+		Description: "Kepala program studi",
+		Permissions: []string{"achievement:verify"},
+	})
+	req := httptest.NewRequest("POST", "/roles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, 201, resp.StatusCode)
+	roleRepo.AssertExpectations(t)
+	permRepo.AssertExpectations(t)
+	permRepo.AssertNotCalled(t, "AttachToRole", mock.Anything, mock.Anything)
+}
@@ -2,12 +2,17 @@ package service_test
 
 import (
 	"UASBE/app/model"
+	"UASBE/app/repository"
 	"UASBE/app/service"
+	"UASBE/config"
 	"UASBE/test/mocks"
+	"UASBE/utils"
 	"bytes"
 	"encoding/json"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -19,7 +24,7 @@ func TestCreateUser_Student_Success(t *testing.T) {
 	userRepo := new(mocks.MockUserRepository)
 	roleRepo := new(mocks.MockRoleRepository)
 	stuRepo := new(mocks.MockStudentRepository)
-	svc := service.NewUserService(userRepo, roleRepo, nil, stuRepo, nil)
+	svc := service.NewUserService(userRepo, roleRepo, nil, stuRepo, nil, nil, nil, nil, nil)
 
 	app := fiber.New()
 	app.Post("/users", svc.CreateUser)
@@ -65,4 +70,117 @@ func TestCreateUser_Student_Success(t *testing.T) {
 
 	// Assert
 	assert.Equal(t, 201, resp.StatusCode)
-}
\ No newline at end of file
+}
+func TestImpersonate_IssuesActorTokenAndAudits(t *testing.T) {
+	utils.JwtKey = []byte("test_secret")
+	config.AppConfig.ImpersonationTTL = 30 * time.Minute
+	defer func() { config.AppConfig = config.Config{} }()
+
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	permRepo := new(mocks.MockPermissionRepository)
+	auditRepo := new(mocks.MockAuditLogRepository)
+	svc := service.NewUserService(userRepo, roleRepo, permRepo, nil, nil, nil, nil, nil, auditRepo)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-1", Username: "admin"})
+		return c.Next()
+	})
+	app.Post("/users/:id/impersonate", svc.Impersonate)
+
+	userRepo.On("FindByID", "student-1").Return(&model.User{ID: "student-1", Username: "budi", RoleID: "role-mhs", IsActive: true}, nil)
+	userRepo.On("FindByID", "admin-2").Return(&model.User{ID: "admin-2", Username: "admin2", RoleID: "role-admin", IsActive: true}, nil)
+	roleRepo.On("GetRoleByID", "role-mhs").Return(&model.Role{ID: "role-mhs", Name: "Mahasiswa"}, nil)
+	roleRepo.On("GetRoleByID", "role-admin").Return(&model.Role{ID: "role-admin", Name: model.RoleAdmin}, nil)
+	permRepo.On("GetPermissionsByRoleID", "role-mhs").Return([]string{"achievement:read"}, nil)
+	permRepo.On("GetPermissionsByRoleID", "role-admin").Return([]string{"user:manage"}, nil)
+	auditRepo.On("Create", mock.MatchedBy(func(e *model.AuditLog) bool {
+		return e.Action == model.AuditActionImpersonationStart && e.ActorID == "admin-1" && e.SubjectID == "student-1" && e.Detail == "tiket #12"
+	})).Return(nil).Once()
+
+	impersonate := func(id string) *http.Response {
+		body, _ := json.Marshal(model.ImpersonateRequest{Reason: "tiket #12"})
+		req := httptest.NewRequest("POST", "/users/"+id+"/impersonate", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		return resp
+	}
+
+	// Admin lain tidak bisa di-impersonate
+	assert.Equal(t, 403, impersonate("admin-2").StatusCode)
+
+	resp := impersonate("student-1")
+	assert.Equal(t, 201, resp.StatusCode)
+
+	var res struct {
+		Data model.ImpersonationResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+	assert.True(t, res.Data.ReadOnly)
+	assert.Equal(t, "admin", res.Data.ImpersonatedBy)
+
+	// Token membawa subject dan actor sekaligus
+	claims, err := utils.ValidateToken(res.Data.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, "student-1", claims.UserID)
+	assert.Equal(t, "admin-1", claims.Impersonator.UserID)
+
+	auditRepo.AssertExpectations(t)
+}
+
+func TestUserManagement_CannotLockOutAdmins(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	roleRepo := new(mocks.MockRoleRepository)
+	permRepo := new(mocks.MockPermissionRepository)
+	revokeRepo := repository.NewMemoryTokenRevocationRepository()
+	svc := service.NewUserService(userRepo, roleRepo, permRepo, nil, nil, revokeRepo, nil, nil, nil)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-1", Username: "admin"})
+		return c.Next()
+	})
+	app.Delete("/users/:id", svc.DeleteUser)
+	app.Put("/users/:id", svc.UpdateUser)
+	app.Put("/users/:id/role", svc.AssignRole)
+
+	userRepo.On("FindByID", "admin-1").Return(&model.User{ID: "admin-1", RoleID: "role-admin", IsActive: true}, nil)
+	userRepo.On("FindByID", "admin-2").Return(&model.User{ID: "admin-2", RoleID: "role-admin", IsActive: true}, nil)
+	roleRepo.On("GetRoleByName", "Mahasiswa").Return(&model.Role{ID: "role-mhs", Name: "Mahasiswa"}, nil)
+	permRepo.On("GetPermissionsByRoleID", "role-admin").Return([]string{"user:manage"}, nil)
+	permRepo.On("GetPermissionsByRoleID", "role-mhs").Return([]string{"achievement:read"}, nil)
+
+	send := func(method, path string, body interface{}) int {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		return resp.StatusCode
+	}
+	inactive := false
+
+	// Akun sendiri
+	assert.Equal(t, 409, send("DELETE", "/users/admin-1", nil))
+	assert.Equal(t, 409, send("PUT", "/users/admin-1", model.UserUpdateRequest{IsActive: &inactive}))
+	assert.Equal(t, 409, send("PUT", "/users/admin-1/role", model.AssignRoleRequest{RoleName: "Mahasiswa"}))
+
+	// Admin aktif terakhir yang lain (admin-1 sudah tidak dihitung, mis. nonaktif)
+	userRepo.On("CountActiveWithPermission", "user:manage").Return(1, nil).Once()
+	assert.Equal(t, 409, send("PUT", "/users/admin-2/role", model.AssignRoleRequest{RoleName: "Mahasiswa"}))
+
+	// Masih ada admin lain: boleh dinonaktifkan
+	userRepo.On("CountActiveWithPermission", "user:manage").Return(2, nil).Once()
+	userRepo.On("Update", mock.MatchedBy(func(u *model.User) bool { return u.ID == "admin-2" && !u.IsActive })).Return(nil)
+	userRepo.On("GetRoleName", "role-admin").Return(model.RoleAdmin, nil)
+	assert.Equal(t, 200, send("PUT", "/users/admin-2", model.UserUpdateRequest{IsActive: &inactive}))
+
+	userRepo.AssertNotCalled(t, "SoftDelete", mock.Anything)
+	userRepo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything)
+	userRepo.AssertExpectations(t)
+
+	validAfter, _ := revokeRepo.GetTokensValidAfter("admin-2")
+	assert.NotNil(t, validAfter)
+	validAfter, _ = revokeRepo.GetTokensValidAfter("admin-1")
+	assert.Nil(t, validAfter)
+}
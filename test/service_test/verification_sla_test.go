@@ -0,0 +1,155 @@
+package service_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/service"
+	"UASBE/app/workflow"
+	"UASBE/config"
+	"UASBE/test/mocks"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func slaConfig() {
+	config.AppConfig = config.Config{
+		VerificationReminderAfter: 3 * 24 * time.Hour,
+		VerificationEscalateAfter: 7 * 24 * time.Hour,
+	}
+}
+
+func TestRunChecks_RemindsAdvisorThenEscalates(t *testing.T) {
+	slaConfig()
+	slaRepo := new(mocks.MockVerificationSLARepository)
+	svc := service.NewVerificationSLAService(slaRepo, nil, nil)
+
+	now := time.Date(2025, 3, 20, 9, 0, 0, 0, time.UTC)
+	advisor := "lecturer-1"
+	remindedAt := now.Add(-2 * 24 * time.Hour)
+	slaRepo.On("GetOverdue", now.Add(-3*24*time.Hour), model.AccessScope{Kind: model.ScopeAll}).Return([]model.OverdueAchievement{
+		{ID: "ref-new", StudentName: "Budi", StudentNIM: "2101", ProgramStudy: "Informatika", AdvisorID: &advisor, SubmittedAt: now.Add(-4 * 24 * time.Hour)},
+		{ID: "ref-reminded", ProgramStudy: "Informatika", AdvisorID: &advisor, SubmittedAt: now.Add(-5 * 24 * time.Hour), RemindedAt: &remindedAt},
+		{ID: "ref-old", ProgramStudy: "Informatika", AdvisorID: &advisor, SubmittedAt: now.Add(-8 * 24 * time.Hour), RemindedAt: &remindedAt},
+	}, nil)
+
+	slaRepo.On("MarkReminded", "ref-new", now, mock.MatchedBy(func(n []model.Notification) bool {
+		return len(n) == 1 && n[0].UserID == advisor && n[0].Type == model.NotificationVerificationReminder && *n[0].AchievementRefID == "ref-new"
+	})).Return(true, nil)
+
+	// Kaprodi Informatika (dosen wali sendiri tidak ikut menerima eskalasi)
+	slaRepo.On("FindReviewers", model.PermissionAchievementReadDepartment, "Informatika").Return([]string{"lecturer-1", "kaprodi-1"}, nil)
+	slaRepo.On("MarkEscalated", "ref-old", now, mock.MatchedBy(func(n []model.Notification) bool {
+		return len(n) == 1 && n[0].UserID == "kaprodi-1" && n[0].Type == model.NotificationVerificationEscalated
+	})).Return(true, nil)
+
+	result, err := svc.RunChecks(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.SLARunResult{Checked: 3, Reminded: 1, Escalated: 1}, result)
+	slaRepo.AssertNotCalled(t, "MarkReminded", "ref-reminded", mock.Anything, mock.Anything)
+	slaRepo.AssertExpectations(t)
+}
+
+func TestRunChecks_EscalatesToAdminQueueWithoutDepartmentReviewer(t *testing.T) {
+	slaConfig()
+	slaRepo := new(mocks.MockVerificationSLARepository)
+	svc := service.NewVerificationSLAService(slaRepo, nil, nil)
+
+	now := time.Date(2025, 3, 20, 9, 0, 0, 0, time.UTC)
+	escalatedAt := now.Add(-time.Hour)
+	slaRepo.On("GetOverdue", mock.Anything, mock.Anything).Return([]model.OverdueAchievement{
+		{ID: "ref-1", ProgramStudy: "Sistem Informasi", SubmittedAt: now.Add(-10 * 24 * time.Hour)},
+		{ID: "ref-2", ProgramStudy: "Sistem Informasi", SubmittedAt: now.Add(-10 * 24 * time.Hour), EscalatedAt: &escalatedAt},
+	}, nil)
+	slaRepo.On("FindReviewers", model.PermissionAchievementReadDepartment, "Sistem Informasi").Return([]string{}, nil)
+	slaRepo.On("FindReviewers", model.PermissionAchievementReadAll, "").Return([]string{"admin-1", "admin-2"}, nil)
+	slaRepo.On("MarkEscalated", "ref-1", now, mock.MatchedBy(func(n []model.Notification) bool {
+		return len(n) == 2 && n[0].UserID == "admin-1" && n[1].UserID == "admin-2"
+	})).Return(true, nil)
+
+	result, err := svc.RunChecks(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Escalated)
+	slaRepo.AssertNumberOfCalls(t, "MarkEscalated", 1)
+}
+
+func TestRunChecks_RemindsCurrentApprovalStage(t *testing.T) {
+	slaConfig()
+	slaRepo := new(mocks.MockVerificationSLARepository)
+	svc := service.NewVerificationSLAService(slaRepo, nil, nil)
+	chains, err := workflow.ParseApprovalChains([]byte(`{"chains": [{"name": "international_competition",
+		"stages": [{"name": "advisor", "approver": "advisor"}, {"name": "kaprodi", "approver": "department"}]}]}`))
+	assert.NoError(t, err)
+	svc.SetApprovalChains(chains)
+
+	// Sudah disetujui dosen wali 4 hari lalu; diajukan 9 hari lalu tapi tahap kaprodi baru mulai
+	now := time.Date(2025, 3, 20, 9, 0, 0, 0, time.UTC)
+	advisor := "lecturer-1"
+	chain := "international_competition"
+	stageStartedAt := now.Add(-4 * 24 * time.Hour)
+	slaRepo.On("GetOverdue", mock.Anything, mock.Anything).Return([]model.OverdueAchievement{
+		{ID: "ref-1", ProgramStudy: "Informatika", AdvisorID: &advisor, SubmittedAt: now.Add(-9 * 24 * time.Hour),
+			ApprovalChain: &chain, ApprovalStage: 1, StageStartedAt: &stageStartedAt},
+	}, nil)
+	slaRepo.On("FindReviewers", model.PermissionAchievementReadDepartment, "Informatika").Return([]string{"lecturer-1", "kaprodi-1"}, nil)
+	slaRepo.On("MarkReminded", "ref-1", now, mock.MatchedBy(func(n []model.Notification) bool {
+		return len(n) == 1 && n[0].UserID == "kaprodi-1" && n[0].Type == model.NotificationVerificationReminder
+	})).Return(true, nil)
+
+	result, err := svc.RunChecks(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.SLARunResult{Checked: 1, Reminded: 1}, result)
+	slaRepo.AssertNotCalled(t, "MarkEscalated", mock.Anything, mock.Anything, mock.Anything)
+	slaRepo.AssertExpectations(t)
+}
+
+func TestGetOverdueReport_AdvisorScopeAndStageFilter(t *testing.T) {
+	slaConfig()
+	slaRepo := new(mocks.MockVerificationSLARepository)
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	svc := service.NewVerificationSLAService(slaRepo, stuRepo, lecRepo)
+
+	app := fiber.New()
+	app.Get("/reports/overdue", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "lecturer-user", Role: "Dosen Wali", Permissions: []string{model.PermissionAchievementRead}})
+		return svc.GetOverdueReport(c)
+	})
+
+	stuRepo.On("FindByUserID", "lecturer-user").Return(nil, nil)
+	lecRepo.On("FindByUserID", "lecturer-user").Return(&model.Lecturer{ID: "lecturer-1"}, nil)
+
+	now := time.Now()
+	escalatedAt := now.Add(-time.Hour)
+	slaRepo.On("GetOverdue", mock.Anything, model.AccessScope{Kind: model.ScopeAdvisees, AdvisorID: "lecturer-1"}).Return([]model.OverdueAchievement{
+		{ID: "ref-1", SubmittedAt: now.Add(-9 * 24 * time.Hour), EscalatedAt: &escalatedAt},
+		{ID: "ref-2", SubmittedAt: now.Add(-4 * 24 * time.Hour)},
+	}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/reports/overdue?stage=escalation", nil))
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var res struct {
+		Data model.OverdueReport `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&res)
+
+	assert.Equal(t, float64(3), res.Data.ReminderAfterDays)
+	assert.Equal(t, 1, res.Data.Total)
+	assert.Equal(t, 1, res.Data.Escalated)
+	if assert.Len(t, res.Data.Items, 1) {
+		assert.Equal(t, "ref-1", res.Data.Items[0].ID)
+		assert.Equal(t, 9, res.Data.Items[0].WaitingDays)
+		assert.Equal(t, model.SLAStageEscalation, res.Data.Items[0].Stage)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/reports/overdue?stage=late", nil))
+	assert.Equal(t, 422, resp.StatusCode)
+}
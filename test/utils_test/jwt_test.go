@@ -0,0 +1,121 @@
+package utils_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/config"
+	"UASBE/utils"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, name string, key interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	return path
+}
+
+func useKeys(t *testing.T, alg, signingKey, verifyKeys string) {
+	config.AppConfig.JWTAlgorithm = alg
+	config.AppConfig.JWTSigningKey = signingKey
+	config.AppConfig.JWTVerifyKeys = verifyKeys
+	require.NoError(t, utils.InitJWT())
+
+	t.Cleanup(func() {
+		config.AppConfig = config.Config{}
+		utils.InitJWT()
+	})
+}
+
+func TestJWT_RS256_KeyRotation(t *testing.T) {
+	oldKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	newKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	oldPath := writeKey(t, "old.pem", oldKey)
+	newPath := writeKey(t, "new.pem", newKey)
+
+	// Token terbit sebelum rotasi
+	useKeys(t, utils.JWTAlgRS256, oldPath, "")
+	oldToken, err := utils.GenerateJWT(model.UserResponse{ID: "user-1"})
+	require.NoError(t, err)
+
+	// Rotasi: key baru aktif, key lama hanya untuk verifikasi
+	useKeys(t, utils.JWTAlgRS256, newPath, oldPath)
+	newToken, err := utils.GenerateJWT(model.UserResponse{ID: "user-2"})
+	require.NoError(t, err)
+
+	claims, err := utils.ValidateToken(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+
+	claims, err = utils.ValidateToken(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-2", claims.UserID)
+
+	jwks := utils.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+
+	// kid di header harus ada di JWKS
+	parsed, _, _ := jwt.NewParser().ParseUnverified(newToken, &model.JWTClaims{})
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+	kids := []string{jwks.Keys[0].Kid, jwks.Keys[1].Kid}
+	assert.Contains(t, kids, parsed.Header["kid"])
+
+	// Key lama dilepas: token lama ditolak
+	useKeys(t, utils.JWTAlgRS256, newPath, "")
+	_, err = utils.ValidateToken(oldToken)
+	assert.Error(t, err)
+}
+
+func TestJWT_EdDSA(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	useKeys(t, utils.JWTAlgEdDSA, writeKey(t, "ed.pem", key), "")
+
+	token, err := utils.GenerateJWT(model.UserResponse{ID: "user-1"})
+	require.NoError(t, err)
+
+	claims, err := utils.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+
+	jwks := utils.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+}
+
+func TestJWT_RejectsAlgorithmConfusion(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	useKeys(t, utils.JWTAlgRS256, writeKey(t, "rsa.pem", key), "")
+	kid := utils.JWKS().Keys[0].Kid
+
+	// Token HS256 yang ditandatangani dengan public key sebagai secret
+	publicDER, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &model.JWTClaims{UserID: "attacker"})
+	forged.Header["kid"] = kid
+	forgedStr, _ := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+
+	_, err := utils.ValidateToken(forgedStr)
+	assert.Error(t, err)
+}
+
+func TestJWT_AlgorithmMismatchFailsStartup(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+
+	config.AppConfig.JWTAlgorithm = utils.JWTAlgRS256
+	config.AppConfig.JWTSigningKey = writeKey(t, "ed.pem", key)
+	defer func() { config.AppConfig = config.Config{} }()
+
+	assert.Error(t, utils.InitJWT())
+}
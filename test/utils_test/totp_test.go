@@ -0,0 +1,63 @@
+package utils_test
+
+import (
+	"UASBE/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test vector RFC 6238 (SHA1, secret ASCII "12345678901234567890"), 6 digit terakhir
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := utils.TOTPCode(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "t=%d", unix)
+	}
+}
+
+func TestTOTP_VerifyAllowsOneStepSkew(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	previous, _ := utils.TOTPCode(rfcSecret, now.Add(-30*time.Second))
+	stale, _ := utils.TOTPCode(rfcSecret, now.Add(-90*time.Second))
+
+	assert.True(t, utils.VerifyTOTP(rfcSecret, "081804", now))
+	assert.True(t, utils.VerifyTOTP(rfcSecret, previous, now))
+	assert.False(t, utils.VerifyTOTP(rfcSecret, stale, now))
+	assert.False(t, utils.VerifyTOTP(rfcSecret, "", now))
+}
+
+func TestRecoveryCodes_HashIsNormalized(t *testing.T) {
+	codes, hashes, err := utils.GenerateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+
+	assert.Equal(t, hashes[0], utils.HashRecoveryCode(" "+codes[0]+" "))
+	assert.Equal(t, hashes[0], utils.HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))))
+}
+
+func TestMFAChallengeToken_IsNotAnAccessToken(t *testing.T) {
+	utils.JwtKey = []byte("test_secret")
+
+	token, _, err := utils.GenerateMFAChallengeToken("user-1", time.Minute)
+	require.NoError(t, err)
+
+	_, err = utils.ValidateToken(token)
+	assert.Error(t, err)
+
+	claims, err := utils.ValidateMFAChallengeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+}
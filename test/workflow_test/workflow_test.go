@@ -0,0 +1,196 @@
+package workflow_test
+
+import (
+	"UASBE/app/model"
+	"UASBE/app/policy"
+	"UASBE/app/workflow"
+	"UASBE/test/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ownerContext(status string) *workflow.Context {
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	stuRepo.On("FindByUserID", "user-1").Return(&model.Student{ID: "std-1"}, nil)
+
+	claims := &model.JWTClaims{
+		UserID:      "user-1",
+		Role:        "Mahasiswa",
+		Permissions: []string{model.PermissionAchievementUpdate, model.PermissionAchievementDelete},
+	}
+	return &workflow.Context{
+		Reference: &model.AchievementReference{ID: "ref-1", StudentID: "std-1", Status: status},
+		Actor:     policy.New(stuRepo, lecRepo).Actor(claims),
+		Claims:    claims,
+	}
+}
+
+func TestWorkflow_AllowedNext(t *testing.T) {
+	m := workflow.Default()
+
+	assert.Equal(t, []string{"submitted", "deleted"}, m.AllowedNext("draft"))
+	assert.Equal(t, []string{"verified", "rejected", "revision_requested", "draft"}, m.AllowedNext("submitted"))
+	assert.Empty(t, m.AllowedNext("verified"))
+	assert.Equal(t, []string{"submitted"}, m.AllowedNext("revision_requested"))
+	assert.Equal(t, []string{"draft"}, m.AllowedNext("deleted"))
+}
+
+func TestWorkflow_InvalidTransitionListsAllowedStates(t *testing.T) {
+	m := workflow.Default()
+	ctx := ownerContext("submitted")
+
+	_, err := m.Apply(workflow.ActionSubmit, ctx)
+
+	var invalid *workflow.TransitionError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "submitted", invalid.Status)
+	assert.Equal(t, []string{"verified", "rejected", "revision_requested", "draft"}, invalid.Allowed)
+	assert.Nil(t, ctx.Reference.SubmittedAt) // side effect tidak dijalankan
+}
+
+func TestWorkflow_SubmitRunsEffect(t *testing.T) {
+	m := workflow.Default()
+	ctx := ownerContext("draft")
+
+	transition, err := m.Apply(workflow.ActionSubmit, ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "submitted", transition.To)
+	assert.NotNil(t, ctx.Reference.SubmittedAt)
+	assert.Equal(t, "draft", ctx.Reference.Status) // status disimpan pemanggil
+}
+
+func TestWorkflow_AuthorizationCheckedBeforeStatus(t *testing.T) {
+	m := workflow.Default()
+
+	// Mahasiswa tidak bisa verifikasi: 403, bukan 409, walaupun statusnya juga salah
+	_, err := m.Apply(workflow.ActionVerify, ownerContext("draft"))
+
+	var forbidden *workflow.ForbiddenError
+	assert.ErrorAs(t, err, &forbidden)
+	assert.Equal(t, "forbidden: you are not the advisor of this student", forbidden.Message)
+}
+
+func TestWorkflow_CustomTransition(t *testing.T) {
+	// Aturan bisa diganti tanpa menyentuh handler
+	m := workflow.Default()
+	m.Register(workflow.Transition{
+		Action:     workflow.ActionDelete,
+		From:       []string{model.AchievementStatusDraft, model.AchievementStatusRejected},
+		To:         model.AchievementStatusDeleted,
+		Permission: model.PermissionAchievementDelete,
+	})
+
+	_, err := m.Apply(workflow.ActionDelete, ownerContext("rejected"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"submitted", "deleted"}, m.AllowedNext("rejected"))
+}
+
+const internationalChains = `{"chains": [{
+	"name": "international_competition",
+	"achievement_type": "competition",
+	"competition_levels": ["international"],
+	"stages": [{"name": "advisor", "approver": "advisor"}, {"name": "kaprodi", "approver": "department"}]
+}]}`
+
+func TestApprovalChains_ChainForMatchesCriteria(t *testing.T) {
+	chains, err := workflow.ParseApprovalChains([]byte(internationalChains))
+	assert.NoError(t, err)
+
+	international := &model.Achievement{AchievementType: "competition", Details: map[string]interface{}{"competitionLevel": "International"}}
+	national := &model.Achievement{AchievementType: "competition", Details: map[string]interface{}{"competitionLevel": "national"}}
+
+	assert.Equal(t, "international_competition", chains.ChainFor(international).Name)
+	assert.Equal(t, workflow.DefaultChainName, chains.ChainFor(national).Name)
+
+	// Rantai yang sudah tidak ada di konfigurasi jatuh ke rantai bawaan
+	removed := "old_chain"
+	assert.Equal(t, workflow.DefaultChainName, chains.Chain(&removed).Name)
+}
+
+func TestApprovalChains_InvalidConfig(t *testing.T) {
+	_, err := workflow.ParseApprovalChains([]byte(`{"chains": [{"name": "x", "stages": [{"name": "dekan", "approver": "permission"}]}]}`))
+	assert.ErrorContains(t, err, "requires a permission")
+
+	_, err = workflow.ParseApprovalChains([]byte(`{"chains": [{"name": "default", "stages": [{"name": "advisor", "approver": "advisor"}]}]}`))
+	assert.ErrorContains(t, err, "reserved")
+}
+
+func TestWorkflow_MultiStageApproval(t *testing.T) {
+	chains, _ := workflow.ParseApprovalChains([]byte(internationalChains))
+	chain := chains.Chain(&chains.Chains[0].Name)
+
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	advisorID := "lecturer-1"
+	stuRepo.On("FindByID", "std-1").Return(&model.Student{ID: "std-1", AdvisorID: &advisorID, ProgramStudy: "Informatika"}, nil)
+	lecRepo.On("FindByUserID", "advisor-user").Return(&model.Lecturer{ID: advisorID, Department: "Informatika"}, nil)
+	lecRepo.On("FindByUserID", "kaprodi-user").Return(&model.Lecturer{ID: "lecturer-9", Department: "Informatika"}, nil)
+	p := policy.New(stuRepo, lecRepo)
+
+	m := workflow.Default()
+	ref := &model.AchievementReference{ID: "ref-1", StudentID: "std-1", Status: "submitted"}
+	contextFor := func(userID string, permissions ...string) *workflow.Context {
+		claims := &model.JWTClaims{UserID: userID, Permissions: permissions}
+		return &workflow.Context{Reference: ref, Actor: p.Actor(claims), Claims: claims, Chain: chain}
+	}
+
+	// Tahap 1: dosen wali menyetujui, status tetap submitted
+	advisor := contextFor("advisor-user", model.PermissionAchievementVerify)
+	assert.Equal(t, workflow.ActionApprove, workflow.VerifyAction(advisor))
+	transition, err := m.Apply(workflow.VerifyAction(advisor), advisor)
+	assert.NoError(t, err)
+	assert.Equal(t, "submitted", transition.To)
+	assert.Equal(t, 1, ref.ApprovalStage)
+	assert.Equal(t, "advisor", advisor.Stage.Name)
+
+	// Dosen wali tidak bisa memutuskan tahap Kaprodi
+	_, err = m.Apply(workflow.VerifyAction(advisor), contextFor("advisor-user", model.PermissionAchievementVerify))
+	var forbidden *workflow.ForbiddenError
+	assert.ErrorAs(t, err, &forbidden)
+	assert.Equal(t, "forbidden: you are not an approver for stage 'kaprodi' of this achievement", forbidden.Message)
+
+	// Tahap 2 (terakhir): Kaprodi memverifikasi
+	kaprodi := contextFor("kaprodi-user", model.PermissionAchievementVerify, model.PermissionAchievementReadDepartment)
+	transition, err = m.Apply(workflow.VerifyAction(kaprodi), kaprodi)
+	assert.NoError(t, err)
+	assert.Equal(t, "verified", transition.To)
+	assert.Equal(t, 1, kaprodi.StageIndex)
+}
+
+func TestWorkflow_StageNeedsDifferentApprover(t *testing.T) {
+	chains, _ := workflow.ParseApprovalChains([]byte(internationalChains))
+	chain := chains.Chain(&chains.Chains[0].Name)
+
+	stuRepo := new(mocks.MockStudentRepository)
+	lecRepo := new(mocks.MockLecturerRepository)
+	advisorID := "lecturer-1"
+	stuRepo.On("FindByID", "std-1").Return(&model.Student{ID: "std-1", AdvisorID: &advisorID, ProgramStudy: "Informatika"}, nil)
+	lecRepo.On("FindByUserID", "advisor-user").Return(&model.Lecturer{ID: advisorID, Department: "Informatika"}, nil)
+	lecRepo.On("FindByUserID", "kaprodi-user").Return(&model.Lecturer{ID: "lecturer-9", Department: "Informatika"}, nil)
+	p := policy.New(stuRepo, lecRepo)
+
+	m := workflow.Default()
+	// Tahap dosen wali sudah disetujui oleh delegasinya atas nama lecturer-1
+	ref := &model.AchievementReference{ID: "ref-1", StudentID: "std-1", Status: "submitted", ApprovalStage: 1}
+	contextFor := func(userID string) *workflow.Context {
+		claims := &model.JWTClaims{UserID: userID, Permissions: []string{model.PermissionAchievementVerify, model.PermissionAchievementReadDepartment}}
+		return &workflow.Context{Reference: ref, Actor: p.Actor(claims), Claims: claims, Chain: chain,
+			EarlierDeciders: []string{"delegate-user", advisorID}}
+	}
+
+	// Dosen wali yang juga reviewer departemen tidak bisa menjadi persetujuan kedua
+	for _, action := range []string{workflow.ActionVerify, workflow.ActionReject} {
+		_, err := m.Apply(action, contextFor("advisor-user"))
+		var forbidden *workflow.ForbiddenError
+		assert.ErrorAs(t, err, &forbidden)
+		assert.Equal(t, "forbidden: you already decided an earlier approval stage of this submission", forbidden.Message)
+	}
+	assert.Equal(t, "submitted", ref.Status)
+
+	transition, err := m.Apply(workflow.ActionVerify, contextFor("kaprodi-user"))
+	assert.NoError(t, err)
+	assert.Equal(t, "verified", transition.To)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+// APITokenPrefix - Penanda token personal; AuthRequired memakainya untuk
+// membedakan API token dari JWT, dan secret scanner bisa mendeteksinya
+const APITokenPrefix = "uasbe_pat_"
+
+// apiTokenDisplayLength - Panjang awal token yang disimpan untuk ditampilkan di daftar token
+const apiTokenDisplayLength = len(APITokenPrefix) + 6
+
+// GenerateAPIToken - Token acak 256 bit. Return token asli, prefix untuk
+// ditampilkan, dan hash SHA-256 untuk disimpan.
+func GenerateAPIToken() (token string, displayPrefix string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+
+	token = APITokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, token[:apiTokenDisplayLength], HashAPIToken(token), nil
+}
+
+// IsAPIToken - Cek apakah bearer token adalah API token (bukan JWT)
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
+
+// HashAPIToken - Hash SHA-256 untuk lookup di database
+func HashAPIToken(token string) string {
+	return HashResetToken(token)
+}
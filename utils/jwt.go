@@ -1,60 +1,200 @@
 package utils
 
 import (
+	"errors"
+	"strings"
 	"time"
 	"UASBE/app/model"
 	"UASBE/config"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var JwtKey []byte
 
+const (
+	AccessTokenTTL  = 2 * time.Hour
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
 // InitJWT - Initialize JWT key from config
-func InitJWT() {
+// HS256 memakai JWT_SECRET; RS256/EdDSA memakai key PEM dari JWT_SIGNING_KEY
+// ditambah key lama dari JWT_VERIFY_KEYS (dipisah koma) untuk rotasi
+func InitJWT() error {
 	JwtKey = []byte(config.AppConfig.JWTSecret)
+
+	alg := config.AppConfig.JWTAlgorithm
+	if alg == "" || alg == JWTAlgHS256 {
+		resetAsymmetricKeys()
+		return nil
+	}
+
+	var verifyKeyFiles []string
+	if config.AppConfig.JWTVerifyKeys != "" {
+		verifyKeyFiles = strings.Split(config.AppConfig.JWTVerifyKeys, ",")
+	}
+	return loadAsymmetricKeys(alg, config.AppConfig.JWTSigningKey, verifyKeyFiles)
 }
 
 func GenerateJWT(user model.UserResponse) (string, error) {
+	return GenerateSessionJWT(user, "")
+}
+
+// GenerateSessionJWT - Access token yang terikat ke sesi login (claim "sid"),
+// sehingga ikut ditolak saat sesi tersebut di-revoke
+func GenerateSessionJWT(user model.UserResponse, sessionID string) (string, error) {
 
 	claims := &model.JWTClaims{
 		UserID:      user.ID,
 		Username:    user.Username,
 		Role:        user.Role,
+		RoleID:      user.RoleID,
 		Permissions: user.Permissions,
+		SessionID:   sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
+		},
+	}
+
+	return signToken(claims)
+}
+
+// GenerateImpersonationJWT - Access token atas nama user lain yang membawa
+// identitas admin di claim "act". Tidak terikat sesi dan tidak punya refresh token.
+func GenerateImpersonationJWT(user model.UserResponse, actor model.ImpersonationActor, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+
+	claims := &model.JWTClaims{
+		UserID:       user.ID,
+		Username:     user.Username,
+		Role:         user.Role,
+		RoleID:       user.RoleID,
+		Permissions:  user.Permissions,
+		Impersonator: &actor,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(2 * time.Hour)),
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(JwtKey)
+	token, err := signToken(claims)
+	return token, expiresAt, err
 }
 
-func GenerateRefreshToken(userID string) (string, error) {
+// GenerateRefreshToken - tokenID dipakai sebagai jti dan harus sama dengan ID di tabel refresh_tokens
+func GenerateRefreshToken(userID string, tokenID string, expiresAt time.Time) (string, error) {
 
 	claims := &model.RefreshTokenClaims{
-		UserID: userID,
+		UserID:  userID,
+		Purpose: model.TokenPurposeRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+			ID:        tokenID,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(JwtKey)
+	return signToken(claims)
+}
+
+// GenerateMFAChallengeToken - Token sementara antara verifikasi password dan kode MFA.
+// Tidak membawa role/permission dan ditolak oleh ValidateToken.
+func GenerateMFAChallengeToken(userID string, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+
+	claims := &model.JWTClaims{
+		UserID:  userID,
+		Purpose: model.TokenPurposeMFA,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := signToken(claims)
+	return token, expiresAt, err
 }
 
 func ValidateToken(tokenStr string) (*model.JWTClaims, error) {
 
 	claims := &model.JWTClaims{}
 
-	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
-		return JwtKey, nil
-	})
+	if err := parseToken(tokenStr, claims); err != nil {
+		return nil, err
+	}
+
+	// challenge token MFA / refresh token bukan access token
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token purpose")
+	}
+
+	return claims, nil
+}
+
+func ValidateMFAChallengeToken(tokenStr string) (*model.JWTClaims, error) {
+
+	claims := &model.JWTClaims{}
+
+	if err := parseToken(tokenStr, claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != model.TokenPurposeMFA {
+		return nil, errors.New("invalid token purpose")
+	}
+
+	return claims, nil
+}
+
+func ValidateRefreshToken(tokenStr string) (*model.RefreshTokenClaims, error) {
+
+	claims := &model.RefreshTokenClaims{}
+
+	if err := parseToken(tokenStr, claims); err != nil {
+		return nil, err
+	}
+
+	// access token / challenge token bukan refresh token
+	if claims.Purpose != model.TokenPurposeRefresh {
+		return nil, errors.New("invalid token purpose")
+	}
+
+	return claims, nil
+}
+
+// GenerateOIDCStateToken - State + nonce login OIDC, disimpan di cookie selama redirect ke IdP.
+// Ditolak oleh ValidateToken karena claim "purpose" terisi.
+func GenerateOIDCStateToken(state string, nonce string, ttl time.Duration) (string, error) {
+
+	claims := &model.OIDCStateClaims{
+		Nonce:   nonce,
+		Purpose: model.TokenPurposeOIDCState,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        state,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+
+	return signToken(claims)
+}
+
+func ValidateOIDCStateToken(tokenStr string) (*model.OIDCStateClaims, error) {
 
-	if err != nil || !token.Valid {
+	claims := &model.OIDCStateClaims{}
+
+	if err := parseToken(tokenStr, claims); err != nil {
 		return nil, err
 	}
 
+	if claims.Purpose != model.TokenPurposeOIDCState {
+		return nil, errors.New("invalid token purpose")
+	}
+
 	return claims, nil
-}
\ No newline at end of file
+}
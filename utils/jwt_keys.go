@@ -0,0 +1,266 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"UASBE/app/model"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algoritma yang didukung untuk JWT_ALGORITHM
+const (
+	JWTAlgHS256 = "HS256"
+	JWTAlgRS256 = "RS256"
+	JWTAlgEdDSA = "EdDSA"
+)
+
+// jwtKey - Satu key yang dikenal; private hanya terisi untuk key aktif
+type jwtKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	public  crypto.PublicKey
+	private crypto.Signer
+}
+
+// signingKey - Key aktif untuk menandatangani token baru.
+// nil = mode HS256 dengan JwtKey (shared secret).
+var signingKey *jwtKey
+
+// verifyKeys - Semua public key yang diterima, berdasarkan kid.
+// Berisi key aktif + key lama dari JWT_VERIFY_KEYS supaya token yang
+// ditandatangani sebelum rotasi tetap valid sampai kadaluarsa.
+var verifyKeys map[string]*jwtKey
+
+// loadAsymmetricKeys - Load signing key dan verify key dari file PEM
+func loadAsymmetricKeys(alg string, signingKeyFile string, verifyKeyFiles []string) error {
+	private, err := readPrivateKey(signingKeyFile)
+	if err != nil {
+		return fmt.Errorf("JWT_SIGNING_KEY: %w", err)
+	}
+
+	active, err := newJWTKey(private.Public())
+	if err != nil {
+		return fmt.Errorf("JWT_SIGNING_KEY: %w", err)
+	}
+	if active.method.Alg() != alg {
+		return fmt.Errorf("JWT_SIGNING_KEY: key type does not match JWT_ALGORITHM %s", alg)
+	}
+
+	keys := map[string]*jwtKey{active.kid: active}
+	for _, file := range verifyKeyFiles {
+		if strings.TrimSpace(file) == "" {
+			continue
+		}
+
+		public, err := readPublicKey(file)
+		if err != nil {
+			return fmt.Errorf("JWT_VERIFY_KEYS %s: %w", file, err)
+		}
+
+		key, err := newJWTKey(public)
+		if err != nil {
+			return fmt.Errorf("JWT_VERIFY_KEYS %s: %w", file, err)
+		}
+		keys[key.kid] = key
+	}
+
+	active.private = private
+	signingKey = active
+	verifyKeys = keys
+	return nil
+}
+
+// resetAsymmetricKeys - Kembali ke mode HS256
+func resetAsymmetricKeys() {
+	signingKey = nil
+	verifyKeys = nil
+}
+
+// signToken - Tandatangani claims dengan key aktif (kid di header),
+// atau HS256 + JwtKey jika tidak ada key asimetris
+func signToken(claims jwt.Claims) (string, error) {
+	if signingKey == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(JwtKey)
+	}
+
+	token := jwt.NewWithClaims(signingKey.method, claims)
+	token.Header["kid"] = signingKey.kid
+	return token.SignedString(signingKey.private)
+}
+
+// parseToken - Parse dan verifikasi token.
+// Algoritma di header harus sama dengan algoritma key yang dipilih lewat kid,
+// supaya token HS256 yang ditandatangani dengan public key (alg confusion) ditolak.
+func parseToken(tokenStr string, claims jwt.Claims) error {
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
+		if verifyKeys == nil {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.New("unexpected signing method")
+			}
+			return JwtKey, nil
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		key, ok := verifyKeys[kid]
+		if !ok {
+			return nil, errors.New("unknown key id")
+		}
+		if t.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.public, nil
+	})
+
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+// JWKS - Public key yang dipakai untuk verifikasi token (format RFC 7517).
+// Kosong jika server memakai HS256.
+func JWKS() model.JWKSet {
+	set := model.JWKSet{Keys: []model.JWK{}}
+	for _, key := range verifyKeys {
+		set.Keys = append(set.Keys, key.jwk())
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// newJWTKey - Tentukan algoritma dari tipe key dan hitung kid (JWK thumbprint)
+func newJWTKey(public crypto.PublicKey) (*jwtKey, error) {
+	key := &jwtKey{public: public}
+
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("unsupported key type (use RSA or Ed25519)")
+	}
+
+	key.kid = key.thumbprint()
+	return key, nil
+}
+
+func (k *jwtKey) jwk() model.JWK {
+	jwk := model.JWK{Use: "sig", Alg: k.method.Alg(), Kid: k.kid}
+
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return jwk
+}
+
+// thumbprint - JWK thumbprint SHA-256 (RFC 7638): hash dari member wajib
+// JWK dengan urutan leksikografis, jadi kid stabil untuk key yang sama
+func (k *jwtKey) thumbprint() string {
+	jwk := k.jwk()
+
+	var members interface{}
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	raw, _ := json.Marshal(members)
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// readPrivateKey - Baca private key PEM (PKCS#8, atau PKCS#1 untuk RSA)
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key")
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+}
+
+// readPublicKey - Baca public key PEM (PKIX, atau PKCS#1 untuk RSA).
+// Private key juga diterima; public key-nya yang dipakai.
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		private, err := readPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return private.Public(), nil
+	}
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	raw, err := os.ReadFile(strings.TrimSpace(path))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	return block, nil
+}
@@ -1,6 +1,18 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"UASBE/config"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 12)
@@ -10,3 +22,63 @@ func HashPassword(password string) (string, error) {
 func CheckPasswordHash(password, hash string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
+
+// ValidatePasswordStrength - Cek password terhadap policy di config.AppConfig
+// Semua aturan yang dilanggar dikembalikan sekaligus dalam satu error
+func ValidatePasswordStrength(password string) error {
+	cfg := config.AppConfig
+
+	var upper, lower, digit, symbol bool
+	for _, ch := range password {
+		switch {
+		case unicode.IsUpper(ch):
+			upper = true
+		case unicode.IsLower(ch):
+			lower = true
+		case unicode.IsDigit(ch):
+			digit = true
+		case unicode.IsPunct(ch) || unicode.IsSymbol(ch):
+			symbol = true
+		}
+	}
+
+	var problems []string
+	if len([]rune(password)) < cfg.PasswordMinLength {
+		problems = append(problems, fmt.Sprintf("at least %d characters", cfg.PasswordMinLength))
+	}
+	if cfg.PasswordRequireUpper && !upper {
+		problems = append(problems, "an uppercase letter")
+	}
+	if cfg.PasswordRequireLower && !lower {
+		problems = append(problems, "a lowercase letter")
+	}
+	if cfg.PasswordRequireDigit && !digit {
+		problems = append(problems, "a digit")
+	}
+	if cfg.PasswordRequireSymbol && !symbol {
+		problems = append(problems, "a symbol")
+	}
+
+	if len(problems) > 0 {
+		return errors.New("password must contain " + strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// GenerateResetToken - Buat token acak untuk reset password
+// Return token asli (dikirim ke user) dan hash SHA-256 (disimpan di database)
+func GenerateResetToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := hex.EncodeToString(buf)
+	return token, HashResetToken(token), nil
+}
+
+// HashResetToken - Hash SHA-256 token reset untuk lookup di database
+func HashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}